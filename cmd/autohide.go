@@ -0,0 +1,294 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/daemon"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+)
+
+const (
+	autoHideFlag  = "auto-hide"
+	hideAfterFlag = "hide-after"
+
+	hideTimerSubcommand = "hide-timer"
+	// noHideTimerEnv disables starting the hide-timer process, the windows
+	// shown with --hide-after are then only hidden by the daemon.
+	noHideTimerEnv = "AEROSPACE_SCRATCHPAD_NO_HIDE_TIMER"
+	hideTimerTick  = time.Second
+)
+
+func openAutoHide() *state.AutoHide {
+	return state.NewAutoHide(state.DefaultAutoHidePath())
+}
+
+func enableHideAfterFlag(command *cobra.Command) *cobra.Command {
+	command.Flags().Duration(
+		hideAfterFlag, 0,
+		"Hide the shown windows again after this long without focus, e.g. 5m",
+	)
+	return command
+}
+
+// trackAutoHide records the shown windows, so the focus-changed hook hides
+// them once they lose focus, or the hide timer after hideAfter without focus.
+func trackAutoHide(
+	cmd *cobra.Command,
+	windows []windowsipc.Window,
+	monitorID int,
+	workspace string,
+	hideAfter time.Duration,
+) {
+	if len(windows) == 0 || !persistsState(cmd) {
+		return
+	}
+
+	tracked := make([]state.AutoHideWindow, 0, len(windows))
+	for _, window := range windows {
+		tracked = append(tracked, state.AutoHideWindow{
+			WindowID:  window.WindowID,
+			AppName:   window.AppName,
+			MonitorID: monitorID,
+			HideAfter: hideAfter,
+			Workspace: workspace,
+			ShownAt:   time.Now(),
+		})
+	}
+	if err := openAutoHide().Add(tracked...); err != nil {
+		logger.GetDefaultLogger().LogError("AUTOHIDE: unable to track windows", "error", err)
+		return
+	}
+
+	if hideAfter > 0 {
+		startHideTimer()
+	}
+}
+
+// startHideTimer starts the hide-timer process in the background, unless the
+// daemon or another hide-timer is already running it.
+func startHideTimer() {
+	log := logger.GetDefaultLogger()
+	if os.Getenv(noHideTimerEnv) != "" || daemon.IsRunning(daemon.DefaultSocketPath()) {
+		return
+	}
+
+	lock, err := state.TryLock(state.DefaultHideTimerLockPath())
+	if err != nil {
+		if !errors.Is(err, state.ErrLocked) {
+			log.LogError("AUTOHIDE: unable to check the hide timer", "error", err)
+		}
+		return
+	}
+	_ = lock.Unlock()
+
+	executable, err := os.Executable()
+	if err != nil {
+		log.LogError("AUTOHIDE: unable to start the hide timer", "error", err)
+		return
+	}
+	timer := exec.Command(executable, "hook", hideTimerSubcommand)
+	// The timer must outlive this command and never hold a daemon started
+	// meanwhile.
+	timer.Env = append(os.Environ(), noDaemonEnv+"=1")
+	timer.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err = timer.Start(); err != nil {
+		log.LogError("AUTOHIDE: unable to start the hide timer", "error", err)
+		return
+	}
+	_ = timer.Process.Release()
+}
+
+func newHideTimerCmd(
+	aerospaceClient aerospace.AeroSpaceWMClient,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   hideTimerSubcommand,
+		Short: "Hide the windows shown with --hide-after once their time is up",
+		Long: `Send the windows shown with --hide-after back to the scratchpad of their
+monitor once they have been without focus for that long.
+
+It runs until no window is left to hide. show and summon start it in the
+background when needed, the daemon does the same by itself.
+`,
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lock, err := state.TryLock(state.DefaultHideTimerLockPath())
+			if errors.Is(err, state.ErrLocked) {
+				logger.GetDefaultLogger().LogDebug("AUTOHIDE: hide timer already running")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Unlock() }()
+
+			tick, _ := cmd.Flags().GetDuration(watchIntervalFlag)
+			runHideTimer(cmd.Context(), cmd, aerospaceClient, tick, true, func(hide func()) { hide() })
+			return nil
+		},
+	}
+
+	command.Flags().Duration(watchIntervalFlag, hideTimerTick, "How often the focus is checked")
+
+	return command
+}
+
+// runHideTimer hides the timed auto-hide windows every tick, until ctx is
+// done or, with untilIdle, no timed window is left.
+func runHideTimer(
+	ctx context.Context,
+	cmd *cobra.Command,
+	aerospaceClient aerospace.AeroSpaceWMClient,
+	tick time.Duration,
+	untilIdle bool,
+	exclusive func(hide func()),
+) {
+	log := logger.GetDefaultLogger()
+	lastFocused := map[int]time.Time{}
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		var pending int
+		var err error
+		exclusive(func() {
+			pending, err = hideTimedWindows(ctx, cmd, aerospaceClient, lastFocused, time.Now())
+		})
+		if err != nil {
+			// AeroSpace may be restarting, the next tick tries again.
+			log.LogError("AUTOHIDE: unable to hide timed windows", "error", err)
+		} else if untilIdle && pending == 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// hideTimedWindows hides the timed windows without focus for their time and
+// stops tracking the ones closed or moved meanwhile. lastFocused remembers
+// when each window was seen focused. It returns how many are still pending.
+func hideTimedWindows(
+	ctx context.Context,
+	cmd *cobra.Command,
+	aerospaceClient aerospace.AeroSpaceWMClient,
+	lastFocused map[int]time.Time,
+	now time.Time,
+) (int, error) {
+	log := logger.GetDefaultLogger()
+
+	autoHide := openAutoHide()
+	tracked, err := autoHide.List()
+	if err != nil {
+		return 0, err
+	}
+	var timed []state.AutoHideWindow
+	for _, window := range tracked {
+		if window.Timed() {
+			timed = append(timed, window)
+		}
+	}
+	if len(timed) == 0 {
+		return 0, nil
+	}
+
+	wm := aerospace.WithContext(ctx, aerospaceClient)
+	allWindows, err := wm.Windows().GetAllWindows()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get windows: %w", err)
+	}
+	windowsByID := make(map[int]windowsipc.Window, len(allWindows))
+	for _, window := range allWindows {
+		windowsByID[window.WindowID] = window
+	}
+	focusedWindowID := 0
+	if focusedWindow, focusErr := wm.Windows().GetFocusedWindow(); focusErr == nil {
+		focusedWindowID = focusedWindow.WindowID
+	}
+
+	mover := newMover(cmd, wm)
+	pending := 0
+	var untracked []int
+	for _, trackedWindow := range timed {
+		window, exists := windowsByID[trackedWindow.WindowID]
+		switch {
+		case !exists,
+			aerospace.IsScratchpadWorkspace(window.Workspace),
+			trackedWindow.Workspace != "" && window.Workspace != trackedWindow.Workspace:
+			// Closed or moved by hand, the auto-hide is cancelled.
+			untracked = append(untracked, trackedWindow.WindowID)
+			continue
+		case window.WindowID == focusedWindowID:
+			lastFocused[window.WindowID] = now
+			pending++
+			continue
+		}
+
+		since := trackedWindow.ShownAt
+		if seen, ok := lastFocused[window.WindowID]; ok && seen.After(since) {
+			since = seen
+		}
+		if now.Sub(since) < trackedWindow.HideAfter {
+			pending++
+			continue
+		}
+
+		markFocusing(cmd, window.WindowID)
+		if _, moveErr := mover.MoveWindowToScratchpadForMonitor(
+			ctx,
+			window,
+			trackedWindow.MonitorID,
+		); moveErr != nil {
+			return pending, fmt.Errorf("unable to move window %d to scratchpad: %w", window.WindowID, moveErr)
+		}
+		untracked = append(untracked, trackedWindow.WindowID)
+		delete(lastFocused, window.WindowID)
+		log.LogInfo("AUTOHIDE: hid timed window", "window", window, "hideAfter", trackedWindow.HideAfter)
+	}
+
+	if len(untracked) > 0 {
+		if removeErr := autoHide.Remove(untracked...); removeErr != nil {
+			return pending, removeErr
+		}
+	}
+	return pending, nil
+}
+
+// markFocusing tells the focus-changed hook the windows are being focused or
+// moved by this command, so it doesn't hide them meanwhile.
+func markFocusing(cmd *cobra.Command, windowIDs ...int) {
+	if len(windowIDs) == 0 || !persistsState(cmd) {
+		return
+	}
+	if err := state.WriteFocusMarker(windowIDs...); err != nil {
+		logger.GetDefaultLogger().LogError("AUTOHIDE: unable to write focus marker", "error", err)
+	}
+}
+
+// windowIDsOf returns the ids of the windows.
+func windowIDsOf(windows []windowsipc.Window) []int {
+	ids := make([]int, 0, len(windows))
+	for _, window := range windows {
+		ids = append(ids, window.WindowID)
+	}
+	return ids
+}
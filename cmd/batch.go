@@ -0,0 +1,333 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/cli"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+const (
+	batchStopOnErrorFlag = "stop-on-error"
+	programName          = "aerospace-scratchpad"
+)
+
+// batchUnsupportedCommands can't run inside a batch.
+//
+//nolint:gochecknoglobals // static list of commands
+var batchUnsupportedCommands = []string{"batch", "daemon", "completion", "help"}
+
+// BatchCmd represents the batch command.
+func BatchCmd(aerospaceClient *aerospace.AeroSpaceClient) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "batch",
+		Short: "Run several commands read from stdin over a single connection",
+		Long: `Run several commands read from stdin over a single connection.
+
+Each input line is a command, as it would be typed after aerospace-scratchpad.
+Empty lines and lines starting with # are ignored. The input may also be a
+JSON array of command lines or of argument lists.
+
+The commands run in order and their results are printed as one stream in the
+--output format. A command that fails is reported as an error event, and the
+batch exits with an error once all the commands ran, or right away with
+--stop-on-error.
+
+Example:
+  printf 'move Finder\nshow Notes\n' | aerospace-scratchpad batch -o json
+  echo '[["move", "Finder"], "show Notes"]' | aerospace-scratchpad batch
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := logger.GetDefaultLogger()
+
+			outputFormat, _ := cmd.Flags().GetString("output")
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
+			if err != nil {
+				logger.LogError("BATCH: invalid output format", "error", err)
+				stderr.Println("Error: unsupported output format")
+				return nil
+			}
+			stopOnError, _ := cmd.Flags().GetBool(batchStopOnErrorFlag)
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			input, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				stderr.Println("Error: unable to read the commands: %v", err)
+				return nil
+			}
+			commands, err := parseBatchInput(input)
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return nil
+			}
+
+			failed := 0
+			for _, commandArgs := range commands {
+				logger.LogDebug("BATCH: running", "args", commandArgs)
+				result := runBatchCommand(cmd.Context(), aerospaceClient, commandArgs, dryRun)
+				printBatchOutput(cmd.OutOrStdout(), formatter, result.stdout)
+				if result.exitCode == 0 {
+					if result.stderr != "" {
+						fmt.Fprint(cmd.ErrOrStderr(), result.stderr)
+					}
+					continue
+				}
+
+				failed++
+				logger.LogError("BATCH: command failed", "args", commandArgs, "error", result.message())
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command: commandBatch,
+					Action:  "run",
+					Result:  "error",
+					Message: fmt.Sprintf("%s: %s", strings.Join(commandArgs, " "), result.message()),
+				}); printErr != nil {
+					logger.LogError("BATCH: unable to write output", "error", printErr)
+				}
+				if stopOnError {
+					break
+				}
+			}
+
+			if failed > 0 {
+				stderr.Println("Error: %d of %d commands failed", failed, len(commands))
+			}
+			return nil
+		},
+	}
+
+	command.Flags().Bool(
+		batchStopOnErrorFlag, false,
+		"Stop at the first command that fails",
+	)
+
+	return command
+}
+
+type batchResult struct {
+	stdout   string
+	stderr   string
+	exitCode int
+	err      error
+}
+
+// message describes why the command failed.
+func (r batchResult) message() string {
+	lines := strings.Split(strings.TrimSpace(r.stderr), "\n")
+	lines = slices.DeleteFunc(lines, func(line string) bool {
+		return strings.TrimSpace(line) == ""
+	})
+	if len(lines) > 0 {
+		return strings.Join(lines, "; ")
+	}
+	if r.err != nil {
+		return r.err.Error()
+	}
+	return fmt.Sprintf("exit code %d", r.exitCode)
+}
+
+// runBatchCommand runs one command of the batch with the batch client, so
+// they all share the connection and the global flags (--dry-run, --timeout).
+// The events are collected as JSON to be printed in the batch format.
+func runBatchCommand(
+	ctx context.Context,
+	aerospaceClient *aerospace.AeroSpaceClient,
+	args []string,
+	dryRun bool,
+) batchResult {
+	rootCmd := RootCmd(aerospaceClient)
+	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
+
+	subcommand, _, err := rootCmd.Find(args)
+	if err != nil || subcommand == rootCmd {
+		return batchResult{exitCode: 1, err: fmt.Errorf("unknown command %q", args[0])}
+	}
+	if slices.Contains(batchUnsupportedCommands, subcommand.Name()) {
+		return batchResult{exitCode: 1, err: fmt.Errorf("%s can't run in a batch", subcommand.Name())}
+	}
+	args = slices.Clone(args)
+	if subcommand.Flags().Lookup("output") != nil {
+		args = append(args, "--output", string(cli.OutputFormatJSON))
+	}
+	if dryRun {
+		// The batch client already skips the changes, the flag lets the
+		// commands know they don't change anything.
+		args = append(args, "--dry-run")
+	}
+	rootCmd.SetArgs(args)
+
+	// The commands report errors through stderr, which exits by default.
+	previousBehavior := stderr.ShouldExit
+	stderr.SetBehavior(false)
+	stderr.ResetExitCode()
+	defer func() {
+		stderr.SetBehavior(previousBehavior)
+		stderr.ResetExitCode()
+	}()
+
+	stdout, errOut, runErr := executeCollectingOutput(ctx, rootCmd)
+
+	exitCode := stderr.LastExitCode()
+	if runErr != nil {
+		exitCode = exitCodeOf(runErr)
+		if errOut == "" {
+			errOut = runErr.Error()
+		}
+	}
+	return batchResult{
+		stdout:   stdout,
+		stderr:   errOut,
+		exitCode: exitCode,
+		err:      runErr,
+	}
+}
+
+// printBatchOutput prints the events of a command in the batch format.
+// Other lines, e.g. the dry-run ones, are printed as they are to out.
+func printBatchOutput(out io.Writer, formatter *cli.OutputFormatter, output string) {
+	logger := logger.GetDefaultLogger()
+
+	for line := range strings.SplitSeq(strings.TrimRight(output, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var event cli.OutputEvent
+		if strings.HasPrefix(line, "{") && json.Unmarshal([]byte(line), &event) == nil {
+			if printErr := formatter.Print(event); printErr != nil {
+				logger.LogError("BATCH: unable to write output", "error", printErr)
+			}
+			continue
+		}
+		fmt.Fprintln(out, line)
+	}
+}
+
+// parseBatchInput reads the commands of a batch, either one per line or a
+// JSON array whose items are command lines or argument lists.
+func parseBatchInput(input []byte) ([][]string, error) {
+	trimmed := bytes.TrimSpace(input)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		return parseBatchJSON(trimmed)
+	}
+
+	var commands [][]string
+	for number, line := range strings.Split(string(input), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		args, err := splitCommandLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number+1, err)
+		}
+		commands = append(commands, trimProgramName(args))
+	}
+
+	return commands, nil
+}
+
+func parseBatchJSON(input []byte) ([][]string, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(input, &items); err != nil {
+		return nil, fmt.Errorf("invalid JSON input: %w", err)
+	}
+
+	commands := make([][]string, 0, len(items))
+	for index, item := range items {
+		var args []string
+		var line string
+		switch {
+		case json.Unmarshal(item, &args) == nil:
+		case json.Unmarshal(item, &line) == nil:
+			var err error
+			if args, err = splitCommandLine(line); err != nil {
+				return nil, fmt.Errorf("item %d: %w", index, err)
+			}
+		default:
+			return nil, fmt.Errorf("item %d: expected a command line or a list of arguments", index)
+		}
+
+		args = trimProgramName(args)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("item %d: empty command", index)
+		}
+		commands = append(commands, args)
+	}
+
+	return commands, nil
+}
+
+// trimProgramName allows copying the lines from a script as they are.
+func trimProgramName(args []string) []string {
+	if len(args) > 1 && args[0] == programName {
+		return args[1:]
+	}
+	return args
+}
+
+// splitCommandLine splits a line into arguments like a shell would, honoring
+// quotes and backslash escapes.
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, char := range line {
+		switch {
+		case escaped:
+			current.WriteRune(char)
+			escaped = false
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else if char == '\\' && quote == '"' {
+				escaped = true
+			} else {
+				current.WriteRune(char)
+			}
+		case char == '\\':
+			escaped, inArg = true, true
+		case char == '\'' || char == '"':
+			quote, inArg = char, true
+		case char == ' ' || char == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(char)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,103 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+func TestBatch(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	t.Run("runs the lines in order as one stream", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		rootCmd := cmd.RootCmd(client)
+		rootCmd.SetIn(strings.NewReader(`
+# bring Finder and hide it again
+summon Finder
+aerospace-scratchpad move 'Finder'
+`))
+		out, err := testutils.CmdExecute(rootCmd, "batch", "-o", "json")
+
+		testutils.MatchSnapshot(t, "batch -o json", out, err)
+	})
+
+	t.Run("reads a JSON array and prints a single header", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		rootCmd := cmd.RootCmd(client)
+		rootCmd.SetIn(strings.NewReader(`[["summon", "Finder"], "list"]`))
+		out, err := testutils.CmdExecute(rootCmd, "batch", "-o", "tsv")
+
+		testutils.MatchSnapshot(t, "batch -o tsv", out, err)
+	})
+
+	t.Run("writes to the command output", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		rootCmd := cmd.RootCmd(client)
+		rootCmd.SetIn(strings.NewReader("summon Finder\n"))
+		rootCmd.SetArgs([]string{"batch", "-o", "json"})
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !strings.Contains(out.String(), `"command":"summon"`) {
+			t.Fatalf("expected the summon event in the command output, got %q", out.String())
+		}
+	})
+
+	t.Run("reports failures and keeps going", func(t *testing.T) {
+		client, world := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		rootCmd := cmd.RootCmd(client)
+		rootCmd.SetIn(strings.NewReader("show Missing\nsummon Finder\n"))
+		_, err := testutils.CmdExecute(rootCmd, "batch")
+		if err == nil || !strings.Contains(err.Error(), "1 of 2 commands failed") {
+			t.Fatalf("expected the batch to fail, got %v", err)
+		}
+
+		for _, window := range world.Snapshot().Windows {
+			if window.WindowID == 2 && window.Workspace != "ws1" {
+				t.Fatalf("expected Finder summoned to ws1, got %q", window.Workspace)
+			}
+		}
+	})
+
+	t.Run("stops at the first failure with --stop-on-error", func(t *testing.T) {
+		client, world := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		rootCmd := cmd.RootCmd(client)
+		rootCmd.SetIn(strings.NewReader("show Missing\nsummon Finder\n"))
+		_, err := testutils.CmdExecute(rootCmd, "batch", "--stop-on-error")
+		if err == nil || !strings.Contains(err.Error(), "1 of 2 commands failed") {
+			t.Fatalf("expected the batch to fail, got %v", err)
+		}
+
+		for _, window := range world.Snapshot().Windows {
+			if window.WindowID == 2 && window.Workspace != ".scratchpad" {
+				t.Fatalf("expected Finder left in the scratchpad, got %q", window.Workspace)
+			}
+		}
+	})
+
+	t.Run("rejects invalid input before running anything", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		rootCmd := cmd.RootCmd(client)
+		rootCmd.SetIn(strings.NewReader("summon Finder\nshow 'Notes\n"))
+		_, err := testutils.CmdExecute(rootCmd, "batch")
+		if err == nil || !strings.Contains(err.Error(), "line 2: unterminated quote") {
+			t.Fatalf("expected a parse error, got %v", err)
+		}
+	})
+}
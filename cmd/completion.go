@@ -0,0 +1,195 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/cli"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+)
+
+// completionTimeout bounds the queries of the completions, no suggestions
+// are better than a shell waiting on AeroSpace.
+const completionTimeout = 500 * time.Millisecond
+
+// completeAppNames completes the <pattern> argument with the app names of
+// the live windows.
+func completeAppNames(aerospaceClient *aerospace.AeroSpaceClient) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		appNames := queryCompletions(cmd, aerospaceClient, func(client aerospace.AeroSpaceWMClient) ([]string, error) {
+			windows, err := client.Windows().GetAllWindows()
+			if err != nil {
+				return nil, err
+			}
+
+			var names []string
+			for _, window := range windows {
+				if window.AppName != "" && !slices.Contains(names, window.AppName) {
+					names = append(names, window.AppName)
+				}
+			}
+			slices.Sort(names)
+			return names, nil
+		})
+
+		return filterCompletions(appNames, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeMonitors completes --monitor with current, all and the monitor ids.
+func completeMonitors(aerospaceClient *aerospace.AeroSpaceClient) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		monitors := queryCompletions(cmd, aerospaceClient, func(client aerospace.AeroSpaceWMClient) ([]string, error) {
+			monitors, err := aerospace.ListMonitors(client)
+			if err != nil {
+				return nil, err
+			}
+
+			ids := make([]string, 0, len(monitors))
+			for _, monitor := range monitors {
+				ids = append(ids, fmt.Sprintf("%d\t%s", monitor.MonitorID, monitor.MonitorName))
+			}
+			return ids, nil
+		})
+
+		completions := append([]string{
+			"current\tthe focused monitor",
+			"all\tall the monitors",
+		}, monitors...)
+		return filterCompletions(completions, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeWorkspaces completes --workspace with prev and the workspaces other
+// than the scratchpad ones.
+func completeWorkspaces(aerospaceClient *aerospace.AeroSpaceClient) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names := queryCompletions(cmd, aerospaceClient, func(client aerospace.AeroSpaceWMClient) ([]string, error) {
+			workspaces, err := aerospace.ListWorkspacesWithMonitors(client)
+			if err != nil {
+				return nil, err
+			}
+
+			names := make([]string, 0, len(workspaces))
+			for _, workspace := range workspaces {
+				if aerospace.IsScratchpadWorkspace(workspace.Workspace) {
+					continue
+				}
+				names = append(names, workspace.Workspace)
+			}
+			return names, nil
+		})
+
+		completions := append([]string{
+			previousWorkspace + "\tthe previous workspace",
+		}, names...)
+		return filterCompletions(completions, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeFilterProperties completes --filter with the property part.
+func completeFilterProperties(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	if strings.Contains(toComplete, "=") {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	properties := make([]string, 0, len(aerospace.FilterProperties))
+	for _, property := range aerospace.FilterProperties {
+		properties = append(properties, property+"=")
+	}
+	return filterCompletions(properties, toComplete),
+		cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeOutputFormats completes --output with the supported formats.
+func completeOutputFormats(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	formats := make([]string, 0, len(cli.OutputFormats))
+	for _, format := range cli.OutputFormats {
+		formats = append(formats, string(format))
+	}
+	return filterCompletions(formats, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// queryCompletions runs query against AeroSpace. The completions degrade to
+// none when AeroSpace isn't reachable: errors are only logged and a missing
+// connection, which panics, is recovered.
+func queryCompletions(
+	cmd *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+	query func(client aerospace.AeroSpaceWMClient) ([]string, error),
+) (completions []string) {
+	logger := logger.GetDefaultLogger()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.LogDebug("COMPLETION: AeroSpace not reachable", "error", recovered)
+			completions = nil
+		}
+	}()
+
+	if aerospaceClient == nil || aerospaceClient.GetUnderlyingClient() == nil {
+		return nil
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	completions, err := query(aerospace.WithContext(ctx, aerospaceClient))
+	if err != nil {
+		logger.LogDebug("COMPLETION: unable to query AeroSpace", "error", err)
+		return nil
+	}
+	return completions
+}
+
+// filterCompletions keeps the completions starting with toComplete, ignoring
+// the case. The description after a tab isn't matched.
+func filterCompletions(completions []string, toComplete string) []string {
+	prefix := strings.ToLower(toComplete)
+
+	var matching []string
+	for _, completion := range completions {
+		value, _, _ := strings.Cut(completion, "\t")
+		if strings.HasPrefix(strings.ToLower(value), prefix) {
+			matching = append(matching, completion)
+		}
+	}
+	return matching
+}
+
+// registerMonitorCompletions completes --monitor on every command having it,
+// the flag is added after the commands are built.
+func registerMonitorCompletions(rootCmd *cobra.Command, aerospaceClient *aerospace.AeroSpaceClient) {
+	for _, command := range rootCmd.Commands() {
+		if command.Flags().Lookup("monitor") == nil {
+			continue
+		}
+		if err := command.RegisterFlagCompletionFunc("monitor", completeMonitors(aerospaceClient)); err != nil {
+			logger.GetDefaultLogger().LogError("COMPLETION: unable to register", "error", err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cmd_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	aerospacecli "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+func complete(t *testing.T, client aerospace.AeroSpaceWMClient, args ...string) []string {
+	t.Helper()
+
+	rootCmd := cmd.RootCmd(client)
+	// The directive is described on stderr.
+	rootCmd.SetErr(io.Discard)
+	out, err := testutils.CmdExecute(rootCmd, append([]string{"__complete"}, args...)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return lines
+}
+
+func TestCompletion(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	tcs := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "patterns from the live app names",
+			args:     []string{"show", ""},
+			expected: []string{"Finder", "Ghostty", ":4"},
+		},
+		{
+			name:     "patterns matching the typed prefix",
+			args:     []string{"summon", "gh"},
+			expected: []string{"Ghostty", ":4"},
+		},
+		{
+			name:     "only the first argument",
+			args:     []string{"move", "Finder", ""},
+			expected: []string{":4"},
+		},
+		{
+			name:     "filter properties",
+			args:     []string{"show", "--filter", "window-"},
+			expected: []string{"window-title=", "window-id=", "window-layout=", ":6"},
+		},
+		{
+			name:     "output formats",
+			args:     []string{"list", "--output", ""},
+			expected: []string{"text", "json", "tsv", "csv", ":4"},
+		},
+		{
+			name:     "monitors",
+			args:     []string{"next", "--monitor", ""},
+			expected: []string{"current\tthe focused monitor", "all\tall the monitors", "1\tBuilt-in Retina Display", ":4"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+			completions := complete(t, client, tc.args...)
+			if strings.Join(completions, "|") != strings.Join(tc.expected, "|") {
+				t.Fatalf("expected %q, got %q", tc.expected, completions)
+			}
+		})
+	}
+
+	t.Run("degrades quietly without AeroSpace", func(t *testing.T) {
+		var disconnected *aerospacecli.AeroSpaceWM
+
+		for _, client := range []aerospace.AeroSpaceWMClient{nil, disconnected} {
+			if completions := complete(t, client, "show", ""); strings.Join(completions, "|") != ":4" {
+				t.Fatalf("expected no completions, got %q", completions)
+			}
+			completions := complete(t, client, "list", "--monitor", "")
+			if len(completions) != 3 || completions[2] != ":4" {
+				t.Fatalf("expected only the static monitors, got %q", completions)
+			}
+		}
+	})
+}
@@ -0,0 +1,317 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/daemon"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+const (
+	daemonSocketFlag   = "socket"
+	daemonCacheTTLFlag = "cache-ttl"
+	// noDaemonEnv disables forwarding the commands to the daemon.
+	noDaemonEnv     = "AEROSPACE_SCRATCHPAD_NO_DAEMON"
+	defaultCacheTTL = 5 * time.Second
+)
+
+// localOnlyCommands never run in the daemon, e.g. batch reads stdin, doctor
+// checks the environment of the CLI and watch keeps running.
+//
+//nolint:gochecknoglobals // static list of commands
+var localOnlyCommands = []string{
+	"daemon", "batch", doctorCommand, commandWatch,
+	"help", "completion", "__complete", "__completeNoDesc",
+}
+
+// localOnlyFlags change how the command talks to AeroSpace or the terminal,
+// so they run in the CLI process. --watch keeps the command running, it would
+// hold the daemon.
+//
+//nolint:gochecknoglobals // static list of flags
+var localOnlyFlags = []string{simulateFlag, recordFlag, statusWatchFlag, "help", "version"}
+
+// localOnlyEnv are read by aerospace-ipc from the environment of the process
+// sending the requests, which the daemon can't take from its clients. The
+// commands run with them, e.g. from an AeroSpace callback, stay in the CLI.
+//
+//nolint:gochecknoglobals // static list of variables
+var localOnlyEnv = []string{"AEROSPACE_WINDOW_ID", "AEROSPACE_WORKSPACE"}
+
+// DaemonCmd represents the daemon command.
+func DaemonCmd(aerospaceClient *aerospace.AeroSpaceClient) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "daemon",
+		Short: "Keep a connection to AeroSpace and run the CLI commands from it",
+		Long: `Keep a connection to AeroSpace and run the CLI commands from it.
+
+Every hotkey starts a new aerospace-scratchpad process, which has to open the
+logs, connect to AeroSpace and query everything from scratch. While the daemon
+is running, the CLI forwards its arguments to it and prints the result, and
+falls back to running the command by itself when the daemon is not running.
+
+The daemon caches what doesn't change while you work (monitors, config) and
+reuses the answers of repeated queries within a command. It also hides the
+windows shown with --hide-after once their time is up.
+
+Set AEROSPACE_SCRATCHPAD_NO_DAEMON=1 to never forward the commands.
+
+Add this snippet in your aerospace.toml config:
+
+'''toml
+after-startup-command = ["exec-and-forget aerospace-scratchpad daemon"]
+'''
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			socketPath, _ := cmd.Flags().GetString(daemonSocketFlag)
+			cacheTTL, _ := cmd.Flags().GetDuration(daemonCacheTTLFlag)
+
+			cache := aerospace.NewResponseCache(cacheTTL)
+			shared := aerospace.NewClientFromConnection(aerospace.NewChainConnection(
+				aerospaceClient.GetUnderlyingClient().Connection(),
+				cache.Interceptor(),
+			))
+
+			// Errors belong to the request, they must not stop the daemon.
+			stderr.SetBehavior(false)
+
+			server := daemon.NewServer(newDaemonHandler(shared, cache))
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				_ = server.Close()
+			}()
+
+			// The hide timer sees AeroSpace as it is, not through the cache,
+			// and runs between the requests so it doesn't move windows under
+			// a running command.
+			go runHideTimer(
+				ctx,
+				cmd,
+				aerospace.NewClientFromConnection(aerospaceClient.GetUnderlyingClient().Connection()),
+				hideTimerTick,
+				false,
+				func(hide func()) {
+					server.Do(func() {
+						hide()
+						cache.Reset()
+					})
+				},
+			)
+
+			logger.GetDefaultLogger().LogInfo("DAEMON: listening", "socket", socketPath)
+			cmd.Printf("aerospace-scratchpad daemon listening on %s\n", socketPath)
+			return server.ListenAndServe(socketPath)
+		},
+	}
+
+	command.Flags().String(
+		daemonSocketFlag, daemon.DefaultSocketPath(),
+		"Unix socket to listen on (env: "+daemon.SocketEnv+")",
+	)
+	command.Flags().Duration(
+		daemonCacheTTLFlag, defaultCacheTTL,
+		"How long monitors and config are cached",
+	)
+
+	return command
+}
+
+// ForwardToDaemon runs the CLI arguments on the daemon, when one is running,
+// and writes the output to stdout and errOut.
+// It returns false when the command must run in this process instead.
+func ForwardToDaemon(args []string, stdout, errOut io.Writer) (int, bool) {
+	if !shouldForward(args) {
+		return 0, false
+	}
+
+	cwd, _ := os.Getwd()
+	response, err := daemon.Send(daemon.DefaultSocketPath(), daemon.Request{
+		Version: VERSION,
+		Args:    args,
+		Cwd:     cwd,
+		Env:     aerospaceEnv(),
+	})
+	if err != nil {
+		return 0, false
+	}
+
+	_, _ = io.WriteString(stdout, response.Stdout)
+	_, _ = io.WriteString(errOut, response.Stderr)
+	return response.ExitCode, true
+}
+
+func shouldForward(args []string) bool {
+	if os.Getenv(noDaemonEnv) != "" || len(args) == 0 {
+		return false
+	}
+	for _, flag := range localOnlyFlags {
+		if hasFlagArg(args, flag) {
+			return false
+		}
+	}
+	for _, name := range localOnlyEnv {
+		if _, ok := os.LookupEnv(name); ok {
+			return false
+		}
+	}
+	if slices.Contains(args, "-h") {
+		return false
+	}
+	// The snapshots of watch are taken by the daemon, watch itself is local.
+	if hasFlagArg(args, watchSnapshotFlag) && slices.Contains(args, commandWatch) {
+		return true
+	}
+
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return !slices.Contains(localOnlyCommands, arg)
+		}
+	}
+	return true
+}
+
+// aerospaceEnv collects the AEROSPACE_* variables, which AeroSpace sets for
+// the commands it runs and aerospace-ipc reads.
+func aerospaceEnv() map[string]string {
+	env := map[string]string{}
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(name, "AEROSPACE_") && name != daemon.SocketEnv {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+func newDaemonHandler(
+	shared aerospace.AeroSpaceWMClient,
+	cache *aerospace.ResponseCache,
+) daemon.Handler {
+	return func(request daemon.Request) daemon.Response {
+		log := logger.GetDefaultLogger()
+		if request.Version != VERSION {
+			return daemon.Response{Error: fmt.Sprintf(
+				"version mismatch: daemon %s, client %s", VERSION, request.Version,
+			)}
+		}
+		if !shouldForward(request.Args) {
+			return daemon.Response{Error: "command must run outside of the daemon"}
+		}
+
+		restore, err := changeRequestDir(request)
+		if err != nil {
+			return daemon.Response{Error: err.Error()}
+		}
+		defer restore()
+
+		startedAt := time.Now()
+		cache.Reset()
+		stderr.ResetExitCode()
+		defer stderr.SetExitCodeFunc(nil)
+
+		rootCmd := RootCmd(shared)
+		rootCmd.SetArgs(request.Args)
+		ctx := context.WithValue(context.Background(), requestEnvKey{}, requestEnv(request))
+		stdout, errOut, runErr := executeCollectingOutput(ctx, rootCmd)
+
+		exitCode := stderr.LastExitCode()
+		if runErr != nil {
+			exitCode = exitCodeOf(runErr)
+		}
+
+		log.LogInfo(
+			"DAEMON: request",
+			"args", request.Args,
+			"exitCode", exitCode,
+			"duration", time.Since(startedAt),
+		)
+		return daemon.Response{
+			Stdout:   stdout,
+			Stderr:   errOut,
+			ExitCode: exitCode,
+		}
+	}
+}
+
+// requestEnvKey holds the AEROSPACE_* variables of the client a daemon
+// request runs for, see getenv.
+type requestEnvKey struct{}
+
+func requestEnv(request daemon.Request) map[string]string {
+	if request.Env == nil {
+		return map[string]string{}
+	}
+	return request.Env
+}
+
+// getenv returns the environment variable of the command: the client's one
+// for a daemon request, the commands share the daemon process environment.
+func getenv(cmd *cobra.Command, name string) string {
+	if env, ok := cmd.Context().Value(requestEnvKey{}).(map[string]string); ok {
+		return env[name]
+	}
+	return os.Getenv(name)
+}
+
+// changeRequestDir runs the command from the client working directory. It
+// returns a function to go back.
+func changeRequestDir(request daemon.Request) (func(), error) {
+	previousCwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get working directory: %w", err)
+	}
+	if request.Cwd != "" {
+		if err = os.Chdir(request.Cwd); err != nil {
+			return nil, fmt.Errorf("unable to change working directory: %w", err)
+		}
+	}
+
+	return func() {
+		_ = os.Chdir(previousCwd)
+	}, nil
+}
+
+// executeCollectingOutput runs rootCmd and returns what it printed, the
+// errors printed with the stderr package included. The process stdio is left
+// alone, e.g. for the daemon logs.
+func executeCollectingOutput(
+	ctx context.Context,
+	rootCmd *cobra.Command,
+) (stdout string, errOut string, runErr error) {
+	var stdoutBuffer, errOutBuffer bytes.Buffer
+	rootCmd.SetOut(&stdoutBuffer)
+	rootCmd.SetErr(&errOutBuffer)
+	stderr.SetOutput(&errOutBuffer)
+	defer stderr.SetOutput(nil)
+
+	// A broken command must not take the process down with it.
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			runErr = fmt.Errorf("command panicked: %v", recovered)
+			fmt.Fprintln(&errOutBuffer, "Error:", runErr)
+		}
+		stdout, errOut = stdoutBuffer.String(), errOutBuffer.String()
+	}()
+
+	runErr = rootCmd.ExecuteContext(ctx)
+	return stdout, errOut, runErr
+}
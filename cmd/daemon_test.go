@@ -0,0 +1,168 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/daemon"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+)
+
+// startDaemon runs the daemon command against the world and points the CLI
+// to it. It is stopped when the test finishes.
+func startDaemon(t *testing.T, world *fakeaerospace.World) {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "sd")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	socketPath := filepath.Join(dir, "daemon.sock")
+	t.Setenv(daemon.SocketEnv, socketPath)
+
+	rootCmd := cmd.RootCmd(aerospace.NewClientFromConnection(fakeaerospace.NewConnection(world)))
+	rootCmd.SetArgs([]string{"daemon", "--socket", socketPath})
+	rootCmd.SetOut(&bytes.Buffer{})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- rootCmd.ExecuteContext(ctx)
+	}()
+	for range 100 {
+		if daemon.IsRunning(socketPath) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		if runErr := <-done; runErr != nil {
+			t.Errorf("unexpected daemon error: %v", runErr)
+		}
+		_ = os.RemoveAll(dir)
+	})
+}
+
+func TestDaemon(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("runs forwarded commands against its connection", func(t *testing.T) {
+		world, err := fakeaerospace.ParseWorld([]byte(fakeWorld))
+		if err != nil {
+			t.Fatalf("unable to parse world: %v", err)
+		}
+		startDaemon(t, world)
+
+		var out, errOut bytes.Buffer
+		exitCode, forwarded := cmd.ForwardToDaemon(
+			[]string{"summon", "Finder", "-o", "json"}, &out, &errOut,
+		)
+		if !forwarded || exitCode != 0 {
+			t.Fatalf("expected a successful forward, got %d %v: %s", exitCode, forwarded, errOut.String())
+		}
+		if !strings.Contains(out.String(), `"app_name":"Finder"`) {
+			t.Fatalf("unexpected output: %s", out.String())
+		}
+
+		for _, window := range world.Snapshot().Windows {
+			if window.WindowID == 2 && window.Workspace != "ws1" {
+				t.Fatalf("expected Finder in ws1, got %s", window.Workspace)
+			}
+		}
+	})
+
+	t.Run("returns the errors and exit code of the command", func(t *testing.T) {
+		world, err := fakeaerospace.ParseWorld([]byte(fakeWorld))
+		if err != nil {
+			t.Fatalf("unable to parse world: %v", err)
+		}
+		startDaemon(t, world)
+
+		var out, errOut bytes.Buffer
+		exitCode, forwarded := cmd.ForwardToDaemon([]string{"summon", "Nothing"}, &out, &errOut)
+		if !forwarded || exitCode != 1 {
+			t.Fatalf("expected exit code 1, got %d %v", exitCode, forwarded)
+		}
+		if !strings.Contains(errOut.String(), "Nothing") {
+			t.Fatalf("expected the error on stderr, got %q", errOut.String())
+		}
+	})
+
+	t.Run("runs the commands with the client environment", func(t *testing.T) {
+		world, err := fakeaerospace.ParseWorld([]byte(fakeWorld))
+		if err != nil {
+			t.Fatalf("unable to parse world: %v", err)
+		}
+		startDaemon(t, world)
+
+		response, err := daemon.Send(os.Getenv(daemon.SocketEnv), daemon.Request{
+			Version: cmd.VERSION,
+			Args:    []string{"summon", "Finder", "--workspace", "prev"},
+			Env:     map[string]string{"AEROSPACE_PREV_WORKSPACE": "ws2"},
+		})
+		if err != nil || response.ExitCode != 0 {
+			t.Fatalf("expected a successful request, got %+v %v", response, err)
+		}
+
+		for _, window := range world.Snapshot().Windows {
+			if window.WindowID == 2 && window.Workspace != "ws2" {
+				t.Fatalf("expected Finder in ws2, got %s", window.Workspace)
+			}
+		}
+		if value, ok := os.LookupEnv("AEROSPACE_PREV_WORKSPACE"); ok {
+			t.Fatalf("expected the daemon environment untouched, got %q", value)
+		}
+	})
+
+	t.Run("falls back to the CLI when no daemon is running", func(t *testing.T) {
+		t.Setenv(daemon.SocketEnv, filepath.Join(t.TempDir(), "missing.sock"))
+
+		if _, forwarded := cmd.ForwardToDaemon([]string{"list"}, &bytes.Buffer{}, &bytes.Buffer{}); forwarded {
+			t.Fatalf("expected no forward without daemon")
+		}
+	})
+
+	t.Run("keeps local commands and flags in the CLI", func(t *testing.T) {
+		world, err := fakeaerospace.ParseWorld([]byte(fakeWorld))
+		if err != nil {
+			t.Fatalf("unable to parse world: %v", err)
+		}
+		startDaemon(t, world)
+
+		for _, args := range [][]string{
+			{},
+			{"daemon"},
+			{"list", "--simulate", "world.yaml"},
+			{"list", "--record=session.jsonl"},
+			{"show", "--help"},
+			{"completion", "zsh"},
+			{"watch", "-o", "json"},
+			{"status", "--watch", "1s"},
+		} {
+			if _, forwarded := cmd.ForwardToDaemon(args, &bytes.Buffer{}, &bytes.Buffer{}); forwarded {
+				t.Fatalf("expected %v to run in the CLI", args)
+			}
+		}
+
+		// aerospace-ipc would read them from the daemon environment instead.
+		t.Setenv("AEROSPACE_WINDOW_ID", "2")
+		if _, forwarded := cmd.ForwardToDaemon([]string{"list"}, &bytes.Buffer{}, &bytes.Buffer{}); forwarded {
+			t.Fatalf("expected a command run for a window to stay in the CLI")
+		}
+
+		t.Setenv("AEROSPACE_SCRATCHPAD_NO_DAEMON", "1")
+		if _, forwarded := cmd.ForwardToDaemon([]string{"list"}, &bytes.Buffer{}, &bytes.Buffer{}); forwarded {
+			t.Fatalf("expected forwarding to be disabled")
+		}
+	})
+}
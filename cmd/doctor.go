@@ -0,0 +1,469 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospacetoml"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+const (
+	doctorCommand = "doctor"
+
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// doctorCheck is the result of one of the checks of doctor.
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+type doctorReport struct {
+	Status string        `json:"status"`
+	Checks []doctorCheck `json:"checks"`
+}
+
+// RunsDoctor reports whether the CLI arguments run the doctor command,
+// which must start even when the log file can't be opened.
+func RunsDoctor(args []string) bool {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		return arg == doctorCommand
+	}
+	return false
+}
+
+// DoctorCmd represents the doctor command.
+func DoctorCmd(
+	aerospaceClient *aerospace.AeroSpaceClient,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   doctorCommand,
+		Short: "Check the setup of aerospace-scratchpad and how to fix it",
+		Long: `Check the setup of aerospace-scratchpad and report each check as pass, warn
+or fail, with a hint on how to fix it:
+
+  socket       AeroSpace answers on its socket
+  hook         the pull-window hook is in aerospace.toml
+  marker       no moving marker is left behind by a crashed command
+  monitors     no scratchpad workspace belongs to a monitor that is gone
+  logs         the log file is writable
+  floating     no floating window is taken for a scratchpad window by mistake
+
+It exits with an error when a check fails, warnings don't fail it.
+
+Example:
+  aerospace-scratchpad doctor
+  aerospace-scratchpad doctor -o json | jq '.checks[] | select(.status != "pass")'
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputFormat, err := reportOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			doctor := &doctor{cmd: cmd, client: aerospaceClient}
+			report := doctor.run()
+
+			out := cmd.OutOrStdout()
+			if outputFormat == "json" {
+				err = json.NewEncoder(out).Encode(report)
+			} else {
+				err = printDoctorReport(out, report)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to print the checks: %w", err)
+			}
+
+			if failed := countChecks(report.Checks, checkFail); failed > 0 {
+				stderr.Println("Error: %d of %d checks failed", failed, len(report.Checks))
+			}
+			return nil
+		},
+	}
+
+	enableReportOutputFlag(command)
+	command.Flags().String(
+		hookConfigFlag, "",
+		"The AeroSpace config file (default ~/.aerospace.toml or ~/.config/aerospace/aerospace.toml)",
+	)
+
+	return command
+}
+
+type doctor struct {
+	cmd    *cobra.Command
+	client *aerospace.AeroSpaceClient
+}
+
+func (d *doctor) run() doctorReport {
+	socket := d.checkSocket()
+	checks := []doctorCheck{
+		socket,
+		d.checkHook(),
+		d.checkMovingMarker(),
+	}
+
+	if socket.Status == checkFail {
+		checks = append(checks,
+			notChecked("monitors"),
+			d.checkLogs(),
+			notChecked("floating"),
+		)
+	} else {
+		checks = append(checks,
+			d.checkMonitors(),
+			d.checkLogs(),
+			d.checkFloating(),
+		)
+	}
+
+	status := checkPass
+	switch {
+	case countChecks(checks, checkFail) > 0:
+		status = checkFail
+	case countChecks(checks, checkWarn) > 0:
+		status = checkWarn
+	}
+
+	return doctorReport{Status: status, Checks: checks}
+}
+
+// wm returns the client bounded by the report timeout.
+func (d *doctor) wm() (aerospace.AeroSpaceWMClient, func()) {
+	ctx, cancel := boundedContext(d.cmd, reportTimeout)
+	return aerospace.WithContext(ctx, d.client), cancel
+}
+
+func (d *doctor) checkSocket() (check doctorCheck) {
+	check = doctorCheck{Name: "socket"}
+	// A client that failed to connect panics on the first use.
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			check.Status = checkFail
+			check.Message = "AeroSpace is not reachable"
+			check.Hint = "Start AeroSpace, or check that its socket is in /tmp"
+		}
+	}()
+
+	if d.client == nil || d.client.GetUnderlyingClient() == nil {
+		panic("no AeroSpace client")
+	}
+
+	conn := d.client.Connection()
+	socketPath, err := conn.GetSocketPath()
+	if err != nil {
+		socketPath = "unknown socket"
+	}
+	serverVersion, err := conn.GetServerVersion()
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("AeroSpace doesn't answer on %s: %v", socketPath, err)
+		check.Hint = "Restart AeroSpace, the socket may be left by a previous run"
+		return check
+	}
+
+	if err = conn.CheckServerVersion(); err != nil {
+		check.Status = checkWarn
+		check.Message = fmt.Sprintf("AeroSpace %s on %s may be incompatible: %v", serverVersion, socketPath, err)
+		check.Hint = "Update AeroSpace or aerospace-scratchpad to matching versions"
+		return check
+	}
+
+	check.Status = checkPass
+	check.Message = fmt.Sprintf("AeroSpace %s answers on %s", serverVersion, socketPath)
+	return check
+}
+
+func (d *doctor) checkHook() doctorCheck {
+	check := doctorCheck{Name: "hook"}
+
+	path, _ := d.cmd.Flags().GetString(hookConfigFlag)
+	if path == "" {
+		path = aerospacetoml.ConfigPath()
+	}
+	path = expandHome(path)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		check.Status = checkWarn
+		check.Message = fmt.Sprintf("no AeroSpace config at %s", path)
+		check.Hint = "Run: aerospace-scratchpad hook install"
+		return check
+	}
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("unable to read %s: %v", path, err)
+		check.Hint = "Check the permissions of the AeroSpace config"
+		return check
+	}
+
+	installed, err := aerospacetoml.HasHook(string(data), aerospacetoml.HookPullWindow)
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("unable to parse %s: %v", path, err)
+		check.Hint = "Fix the config, AeroSpace reports the error on: aerospace reload-config"
+		return check
+	}
+	if !installed {
+		check.Status = checkWarn
+		check.Message = fmt.Sprintf("the pull-window hook is not in %s", path)
+		check.Hint = "Run: aerospace-scratchpad hook install"
+		return check
+	}
+
+	check.Status = checkPass
+	check.Message = fmt.Sprintf("the pull-window hook is in %s", path)
+	return check
+}
+
+func (d *doctor) checkMovingMarker() doctorCheck {
+	check := doctorCheck{Name: "marker"}
+
+	path, err := state.MovingMarkerPath()
+	if err != nil {
+		check.Status = checkFail
+		check.Message = err.Error()
+		check.Hint = "Set XDG_RUNTIME_DIR to a directory of your own"
+		return check
+	}
+
+	marker, err := state.ReadMovingMarker()
+	if err != nil {
+		check.Status = checkWarn
+		check.Message = err.Error()
+		check.Hint = "Remove it: rm " + path
+		return check
+	}
+	if marker != nil && marker.Expired(time.Now()) {
+		check.Status = checkWarn
+		check.Message = fmt.Sprintf(
+			"a moving marker of window %d is left since %s",
+			marker.WindowID,
+			marker.Timestamp.Format(time.RFC3339),
+		)
+		check.Hint = "Remove it: rm " + path
+		return check
+	}
+
+	check.Status = checkPass
+	check.Message = "no moving marker left behind"
+	return check
+}
+
+func (d *doctor) checkMonitors() doctorCheck {
+	check := doctorCheck{Name: "monitors"}
+
+	wm, cancel := d.wm()
+	defer cancel()
+
+	monitors, err := aerospace.ListMonitors(wm)
+	if err != nil {
+		return failedQuery(check, err)
+	}
+	workspaces, err := aerospace.ListWorkspacesWithMonitors(wm)
+	if err != nil {
+		return failedQuery(check, err)
+	}
+
+	connected := make(map[int]bool, len(monitors))
+	for _, monitor := range monitors {
+		connected[monitor.MonitorID] = true
+	}
+
+	prefix := constants.DefaultScratchpadWorkspaceName + "."
+	var orphaned []string
+	for _, workspace := range workspaces {
+		monitorID, err := strconv.Atoi(strings.TrimPrefix(workspace.Workspace, prefix))
+		if !strings.HasPrefix(workspace.Workspace, prefix) || err != nil {
+			continue
+		}
+		if !connected[monitorID] {
+			orphaned = append(orphaned, workspace.Workspace)
+		}
+	}
+
+	if len(orphaned) > 0 {
+		check.Status = checkWarn
+		check.Message = "scratchpad workspaces of monitors that are gone: " + strings.Join(orphaned, ", ")
+		check.Hint = fmt.Sprintf(
+			"Bring their windows back with: aerospace-scratchpad summon --filter 'workspace=^%s$' '.'",
+			strings.ReplaceAll(orphaned[0], ".", `\.`),
+		)
+		return check
+	}
+
+	check.Status = checkPass
+	check.Message = fmt.Sprintf("the scratchpad workspaces match the %d monitors", len(monitors))
+	return check
+}
+
+func (d *doctor) checkLogs() doctorCheck {
+	check := doctorCheck{Name: "logs"}
+
+	path := logger.Path()
+	// #nosec G304 -- log path is intentionally configurable via env var.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("unable to write the logs to %s: %v", path, err)
+		check.Hint = fmt.Sprintf(
+			"Fix the permissions of %s or set %s to a writable file",
+			filepath.Dir(path),
+			constants.EnvAeroSpaceScratchpadLogsPath,
+		)
+		return check
+	}
+	_ = file.Close()
+
+	check.Status = checkPass
+	check.Message = fmt.Sprintf("the logs are written to %s", path)
+	return check
+}
+
+// checkFloating looks for floating windows out of the scratchpad workspaces
+// that aerospace-scratchpad never handled. They are scratchpad windows for
+// next and move --all-floating, which is rarely what their owner expects.
+func (d *doctor) checkFloating() doctorCheck {
+	check := doctorCheck{Name: "floating"}
+
+	wm, cancel := d.wm()
+	defer cancel()
+
+	allWindows, err := wm.Windows().GetAllWindows()
+	if err != nil {
+		return failedQuery(check, err)
+	}
+
+	known := knownWindowIDs()
+	var unknown []windowsipc.Window
+	for _, window := range allWindows {
+		if window.WindowLayout != "floating" ||
+			aerospace.IsScratchpadWorkspace(window.Workspace) ||
+			known[window.WindowID] {
+			continue
+		}
+		unknown = append(unknown, window)
+	}
+
+	if len(unknown) > 0 {
+		names := make([]string, 0, len(unknown))
+		for _, window := range unknown {
+			names = append(names, fmt.Sprintf("%s (%d)", window.AppName, window.WindowID))
+		}
+		check.Status = checkWarn
+		check.Message = fmt.Sprintf(
+			"%s floating out of the scratchpad, next and move --all-floating take them as scratchpad windows",
+			strings.Join(names, ", "),
+		)
+		check.Hint = fmt.Sprintf(
+			"Make them tiling with: aerospace layout tiling --window-id %d, or move them to the scratchpad on purpose",
+			unknown[0].WindowID,
+		)
+		return check
+	}
+
+	check.Status = checkPass
+	check.Message = "no floating window is taken for a scratchpad window by mistake"
+	return check
+}
+
+// knownWindowIDs returns the windows aerospace-scratchpad handled before:
+// moved to a scratchpad workspace, pinned, tagged or shown with auto-hide.
+func knownWindowIDs() map[int]bool {
+	log := logger.GetDefaultLogger()
+	known := managedWindows()
+
+	pinned, err := openPins().List()
+	if err != nil {
+		log.LogError("DOCTOR: unable to read pins", "error", err)
+	}
+	for _, pin := range pinned {
+		known[pin.WindowID] = true
+	}
+
+	tagged, err := openTags().List()
+	if err != nil {
+		log.LogError("DOCTOR: unable to read tags", "error", err)
+	}
+	for _, tag := range tagged {
+		known[tag.WindowID] = true
+	}
+
+	autoHidden, err := openAutoHide().List()
+	if err != nil {
+		log.LogError("DOCTOR: unable to read auto-hide windows", "error", err)
+	}
+	for _, window := range autoHidden {
+		known[window.WindowID] = true
+	}
+
+	return known
+}
+
+func failedQuery(check doctorCheck, err error) doctorCheck {
+	check.Status = checkFail
+	check.Message = fmt.Sprintf("unable to query AeroSpace: %v", err)
+	check.Hint = "Restart AeroSpace if it keeps not answering"
+	return check
+}
+
+func notChecked(name string) doctorCheck {
+	return doctorCheck{
+		Name:    name,
+		Status:  checkWarn,
+		Message: "not checked, AeroSpace is not reachable",
+		Hint:    "Fix the socket check first",
+	}
+}
+
+func countChecks(checks []doctorCheck, status string) int {
+	count := 0
+	for _, check := range checks {
+		if check.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func printDoctorReport(out io.Writer, report doctorReport) error {
+	for _, check := range report.Checks {
+		if _, err := fmt.Fprintf(out, "[%s] %-8s %s\n", check.Status, check.Name, check.Message); err != nil {
+			return err
+		}
+		if check.Hint == "" || check.Status == checkPass {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "       %-8s fix: %s\n", "", check.Hint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+const doctorConfig = `exec-on-workspace-change = ["/bin/bash", "-c",
+  "aerospace-scratchpad hook pull-window $AEROSPACE_PREV_WORKSPACE $AEROSPACE_FOCUSED_WORKSPACE"
+]
+`
+
+const doctorBrokenWorld = `
+monitors:
+  - monitor-id: 1
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+    monitor-id: 1
+  - workspace: .scratchpad.2
+    monitor-id: 1
+windows:
+  - window-id: 1
+    app-name: Ghostty
+    workspace: ws1
+  - window-id: 3
+    app-name: Calculator
+    workspace: ws1
+    window-layout: floating
+  - window-id: 4
+    app-name: Notes
+    workspace: .scratchpad.2
+    window-layout: floating
+`
+
+type doctorResult struct {
+	Status string `json:"status"`
+	Checks []struct {
+		Name    string `json:"name"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Hint    string `json:"hint"`
+	} `json:"checks"`
+}
+
+func (r doctorResult) statuses() map[string]string {
+	statuses := map[string]string{}
+	for _, check := range r.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+// setupDoctor isolates the files checked by doctor and returns the config.
+func setupDoctor(t *testing.T, config string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, dir)
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	t.Setenv(constants.EnvAeroSpaceScratchpadLogsPath, filepath.Join(dir, "scratchpad.log"))
+
+	configPath := filepath.Join(dir, "aerospace.toml")
+	if config != "" {
+		if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+			t.Fatalf("unable to write config: %v", err)
+		}
+	}
+	return configPath
+}
+
+func TestDoctorCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	t.Run("passes every check of a healthy setup", func(t *testing.T) {
+		configPath := setupDoctor(t, doctorConfig)
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "doctor", "--config", configPath, "-o", "json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var result doctorResult
+		if err = json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("expected a JSON report, got %q: %v", out, err)
+		}
+		if result.Status != "pass" || len(result.Checks) != 6 {
+			t.Fatalf("expected 6 passing checks, got %+v", result)
+		}
+		for _, check := range result.Checks {
+			if check.Status != "pass" {
+				t.Fatalf("expected %s to pass, got %+v", check.Name, check)
+			}
+		}
+	})
+
+	t.Run("warns about the setup to fix with hints", func(t *testing.T) {
+		configPath := setupDoctor(t, "start-at-login = true\n")
+		client, _ := testutils.StartFakeAeroSpace(t, doctorBrokenWorld)
+
+		markerPath, err := state.MovingMarkerPath()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		marker, _ := json.Marshal(state.MovingMarker{
+			PID:       1,
+			Timestamp: time.Now().Add(-time.Hour),
+			WindowID:  4,
+		})
+		if err = os.WriteFile(markerPath, marker, 0o600); err != nil {
+			t.Fatalf("unable to write marker: %v", err)
+		}
+
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "doctor", "--config", configPath, "-o", "json")
+		if err != nil {
+			t.Fatalf("warnings must not fail doctor, got: %v", err)
+		}
+
+		var result doctorResult
+		if err = json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("expected a JSON report, got %q: %v", out, err)
+		}
+		expected := map[string]string{
+			"socket":   "pass",
+			"hook":     "warn",
+			"marker":   "warn",
+			"monitors": "warn",
+			"logs":     "pass",
+			"floating": "warn",
+		}
+		statuses := result.statuses()
+		for name, status := range expected {
+			if statuses[name] != status {
+				t.Fatalf("expected %s to %s, got %+v", name, status, result)
+			}
+		}
+		if result.Status != "warn" {
+			t.Fatalf("expected the report to warn, got %q", result.Status)
+		}
+
+		for _, check := range result.Checks {
+			if check.Status == "warn" && check.Hint == "" {
+				t.Fatalf("expected a hint for %s", check.Name)
+			}
+		}
+		if !strings.Contains(out, `scratchpad workspaces of monitors that are gone: .scratchpad.2`) {
+			t.Fatalf("expected the orphaned scratchpad reported, got %s", out)
+		}
+		if !strings.Contains(out, "Calculator (3)") || strings.Contains(out, "Notes (4)") {
+			t.Fatalf("expected only Calculator reported as floating, got %s", out)
+		}
+	})
+
+	t.Run("doesn't take the windows handled before as misclassified", func(t *testing.T) {
+		configPath := setupDoctor(t, doctorConfig)
+		client, _ := testutils.StartFakeAeroSpace(t, doctorBrokenWorld)
+
+		if err := state.NewPins(state.DefaultPinsPath()).Add(state.PinnedWindow{WindowID: 3}); err != nil {
+			t.Fatalf("unable to pin: %v", err)
+		}
+
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "doctor", "--config", configPath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out, "[pass] floating") {
+			t.Fatalf("expected the pinned window to be known, got:\n%s", out)
+		}
+		if !strings.Contains(out, "fix: Bring their windows back with:") {
+			t.Fatalf("expected the text report to carry the hints, got:\n%s", out)
+		}
+	})
+
+	t.Run("fails when AeroSpace isn't reachable", func(t *testing.T) {
+		configPath := setupDoctor(t, doctorConfig)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(nil), "doctor", "--config", configPath)
+		if err == nil || !strings.Contains(err.Error(), "1 of 6 checks failed") {
+			t.Fatalf("expected the socket check to fail, got %v", err)
+		}
+	})
+
+	t.Run("fails when the logs aren't writable", func(t *testing.T) {
+		configPath := setupDoctor(t, doctorConfig)
+		t.Setenv(constants.EnvAeroSpaceScratchpadLogsPath, filepath.Join(t.TempDir(), "missing", "scratchpad.log"))
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "doctor", "--config", configPath)
+		if err == nil || !strings.Contains(err.Error(), "1 of 6 checks failed") {
+			t.Fatalf("expected the logs check to fail, got %v", err)
+		}
+	})
+}
+
+func TestRunsDoctor(t *testing.T) {
+	if !cmd.RunsDoctor([]string{"--dry-run", "doctor", "-o", "json"}) {
+		t.Fatalf("expected doctor to be detected")
+	}
+	if cmd.RunsDoctor([]string{"show", "doctor"}) {
+		t.Fatalf("expected show not to be doctor")
+	}
+}
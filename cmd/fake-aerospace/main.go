@@ -0,0 +1,104 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+)
+
+func main() {
+	if err := rootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func rootCmd() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "fake-aerospace",
+		Short: "In-memory AeroSpace server for offline testing",
+		Long: `In-memory AeroSpace server for offline testing
+
+Listens on a unix socket speaking the same protocol as AeroSpace and answers
+the commands used by aerospace-scratchpad from a world described in yaml.
+Commands are applied to the world, so moving or focusing a window changes
+the answers of the next queries.
+
+Point the CLI to it with the AEROSPACESOCK environment variable:
+
+  fake-aerospace --world world.yaml --socket /tmp/fake.sock &
+  AEROSPACESOCK=/tmp/fake.sock aerospace-scratchpad list
+
+Send 'fake-dump-world' through the socket to get the current world as yaml.
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         run,
+	}
+
+	command.Flags().StringP("world", "w", "", "Path to the yaml file describing the initial world")
+	command.Flags().StringP(
+		"socket", "s", defaultSocketPath(),
+		"Unix socket path to listen on (default: $AEROSPACESOCK or the AeroSpace default)",
+	)
+	command.Flags().BoolP("verbose", "v", false, "Log every received command to stderr")
+
+	return command
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	worldPath, _ := cmd.Flags().GetString("world")
+	socketPath, _ := cmd.Flags().GetString("socket")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	world := fakeaerospace.NewWorld(fakeaerospace.State{})
+	if worldPath != "" {
+		loaded, err := fakeaerospace.LoadWorld(worldPath)
+		if err != nil {
+			return err
+		}
+		world = loaded
+	}
+
+	logger := log.New(os.Stderr, "fake-aerospace: ", log.LstdFlags)
+	var logf fakeaerospace.Logf
+	if verbose {
+		logf = logger.Printf
+	}
+
+	server := fakeaerospace.NewServer(world, logf)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		_ = server.Close()
+	}()
+
+	logger.Printf("listening on %s", socketPath)
+	defer func() {
+		_ = os.Remove(socketPath)
+	}()
+
+	if err := server.ListenAndServe(socketPath); err != nil {
+		return fmt.Errorf("fake server stopped: %w", err)
+	}
+
+	return nil
+}
+
+func defaultSocketPath() string {
+	if socketPath := os.Getenv(constants.EnvAeroSpaceSock); socketPath != "" {
+		return socketPath
+	}
+	return fmt.Sprintf("/tmp/bobko.aerospace-%s.sock", os.Getenv("USER"))
+}
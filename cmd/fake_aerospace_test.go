@@ -0,0 +1,57 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+const fakeWorld = `
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+windows:
+  - window-id: 1
+    window-title: Terminal
+    app-name: Ghostty
+    workspace: ws1
+  - window-id: 2
+    window-title: Finder
+    app-name: Finder
+    workspace: .scratchpad
+    window-layout: floating
+`
+
+func TestFakeAeroSpaceEndToEnd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("summons a window from the scratchpad and moves it back", func(t *testing.T) {
+		client, world := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "summon", "Finder")
+		if err != nil {
+			t.Fatalf("unexpected error summoning: %v", err)
+		}
+
+		state := world.Snapshot()
+		for _, window := range state.Windows {
+			if window.WindowID == 2 && window.Workspace != "ws1" {
+				t.Fatalf("expected Finder in ws1, got %q", window.Workspace)
+			}
+		}
+
+		_, err = testutils.CmdExecute(cmd.RootCmd(client), "move", "Finder")
+		if err != nil {
+			t.Fatalf("unexpected error moving: %v", err)
+		}
+
+		state = world.Snapshot()
+		for _, window := range state.Windows {
+			if window.WindowID == 2 && window.Workspace != ".scratchpad" {
+				t.Fatalf("expected Finder in .scratchpad, got %q", window.Workspace)
+			}
+		}
+	})
+}
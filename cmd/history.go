@@ -0,0 +1,146 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/cli"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+// HistoryCmd represents the history command.
+func HistoryCmd(_ *aerospace.AeroSpaceClient) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "history",
+		Short: "Print the windows moved by the last commands",
+		Long: `Print the windows moved by the last commands, from the oldest to the newest.
+
+Every window moved by move, show, summon and next is recorded in a journal,
+with the workspace it came from and its previous layout. Use undo to revert
+the last operations.
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+
+			outputFormat, _ := cmd.Flags().GetString("output")
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
+			if err != nil {
+				stderr.Println("Error: unsupported output format")
+				return
+			}
+
+			entries, err := openJournal().Entries()
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return
+			}
+
+			if len(entries) == 0 {
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command: commandHistory,
+					Action:  "history",
+					Result:  "none",
+					Message: "no operations recorded",
+				}); printErr != nil {
+					logger.LogError("HISTORY: unable to write output", "error", printErr)
+				}
+				return
+			}
+
+			for _, entry := range entries {
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command:         commandHistory,
+					Action:          entry.Command,
+					WindowID:        entry.WindowID,
+					AppName:         entry.AppName,
+					Workspace:       entry.FromWorkspace,
+					TargetWorkspace: entry.ToWorkspace,
+					Result:          "ok",
+					Message: fmt.Sprintf(
+						"%s previous-layout=%s",
+						entry.Time.Format(time.RFC3339),
+						entry.PreviousLayout,
+					),
+				}); printErr != nil {
+					logger.LogError("HISTORY: unable to write output", "error", printErr)
+				}
+			}
+		},
+	}
+
+	return command
+}
+
+func openJournal() *state.Journal {
+	return state.NewJournal(state.DefaultJournalPath(), state.DefaultJournalSize)
+}
+
+func openManaged() *state.Managed {
+	return state.NewManaged(state.DefaultManagedPath(), state.DefaultManagedSize)
+}
+
+// journalRecorder appends the moves of a command to the journal, and the
+// windows moved to a scratchpad workspace to the managed set.
+type journalRecorder struct {
+	journal *state.Journal
+	managed *state.Managed
+	command string
+}
+
+func (r journalRecorder) RecordOperation(operation aerospace.Operation) {
+	err := r.journal.Append(state.JournalEntry{
+		Time:           time.Now(),
+		Command:        r.command,
+		WindowID:       operation.WindowID,
+		AppName:        operation.AppName,
+		FromWorkspace:  operation.FromWorkspace,
+		ToWorkspace:    operation.ToWorkspace,
+		PreviousLayout: operation.PreviousLayout,
+	})
+	if err != nil {
+		// The windows moved anyway, a journal failure must not fail the command.
+		logger.GetDefaultLogger().LogError("JOURNAL: unable to record operation", "error", err)
+	}
+
+	if !aerospace.IsScratchpadWorkspace(operation.ToWorkspace) {
+		return
+	}
+	if err = r.managed.Add(operation.WindowID); err != nil {
+		logger.GetDefaultLogger().LogError("JOURNAL: unable to record managed window", "error", err)
+	}
+}
+
+// newMover creates the mover for a command, recording its moves in the
+// journal unless nothing really moves (dry-run or a simulated world).
+func newMover(
+	cmd *cobra.Command,
+	aerospaceClient aerospace.AeroSpaceWMClient,
+) aerospace.MoverAeroSpace {
+	mover := aerospace.NewAeroSpaceMover(aerospaceClient)
+	if persistsState(cmd) {
+		mover.SetRecorder(journalRecorder{
+			journal: openJournal(),
+			managed: openManaged(),
+			command: cmd.Name(),
+		})
+	}
+
+	return mover
+}
+
+// persistsState reports whether the command changes the real windows, so what
+// it did can be remembered. Dry runs and simulations don't.
+func persistsState(cmd *cobra.Command) bool {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	worldPath, _ := cmd.Flags().GetString(simulateFlag)
+	return !dryRun && worldPath == ""
+}
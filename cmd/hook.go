@@ -6,29 +6,42 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"io/fs"
-	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
 
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/layout"
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
 
-	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/config"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
 )
 
 const (
-	pullWindowSubcommand = "pull-window"
+	hookCommand              = "hook"
+	pullWindowSubcommand     = "pull-window"
+	followSubcommand         = "follow"
+	windowDetectedSubcommand = "window-detected"
+	focusChangedSubcommand   = "focus-changed"
 
 	minArgsPullWindow = 2
+
+	pullTargetFlag = "target"
+	// pullTargetPrevious pulls the window to the workspace focused before the
+	// scratchpad one.
+	pullTargetPrevious = "previous"
+	// pullTargetFocusedMonitor pulls the window to the workspace last focused
+	// on the monitor showing the scratchpad one.
+	pullTargetFocusedMonitor = "focused-monitor"
 )
 
 func HookCmd(
 	aerospaceClient aerospace.AeroSpaceWMClient,
 ) *cobra.Command {
 	hookCmd := &cobra.Command{
-		Use:   "hook",
+		Use:   hookCommand,
 		Short: "Hook commands to react to specific actions outside AeroSpace WM",
 		Long: `Hook commands to react to actions that aren't handled by AeroSpace WM.
 Example of such action is when a window in a scratchpad workspace is focused, which happens when clicking in a notification or
@@ -37,14 +50,27 @@ when a program is focused by the launcher (alfred, raycast, etc).
 	}
 
 	hookCmd.AddCommand(newPullWindowCmd(aerospaceClient))
+	hookCmd.AddCommand(newFollowCmd(aerospaceClient))
+	hookCmd.AddCommand(newWindowDetectedCmd(aerospaceClient))
+	hookCmd.AddCommand(newFocusChangedCmd(aerospaceClient))
+	hookCmd.AddCommand(newHideTimerCmd(aerospaceClient))
+	hookCmd.AddCommand(newInstallCmd())
+	hookCmd.AddCommand(newUninstallCmd())
 
 	return hookCmd
 }
 
+// pullWindowOptions are the flags of hook pull-window.
+type pullWindowOptions struct {
+	// Target is previous, focused-monitor or a workspace name.
+	Target  string
+	Filters []aerospace.Filter
+}
+
 func newPullWindowCmd(
 	aerospaceClient aerospace.AeroSpaceWMClient,
 ) *cobra.Command {
-	return &cobra.Command{
+	command := &cobra.Command{
 		Use:   fmt.Sprintf("%s <previous-workspace> <focused-workspace>", pullWindowSubcommand),
 		Short: "Pull the focused scratchpad window back to the previous workspace",
 		Long: `Pull the focused scratchpad window back to the previous workspace so it behaves like it was summoned there.
@@ -57,12 +83,186 @@ Add this snippet in your aerospace.toml config:
 exec-on-workspace-change = ["/bin/bash", "-c",
   "aerospace-scratchpad hook pull-window $AEROSPACE_PREV_WORKSPACE $AEROSPACE_FOCUSED_WORKSPACE"
 ]
+
+With --target, the window goes to another workspace: "previous" (default), the
+one focused before the scratchpad, "focused-monitor", the one last focused on
+the monitor showing the scratchpad, or a workspace name.
+
+With --filter, only the matching windows are pulled. The pinned windows (see
+pin) are never pulled, and neither are the brand-new windows seen by the
+window-detected hook: they stay in the scratchpad and the target workspace is
+focused again.
 `,
 		Aliases: []string{"pull"},
 		Args:    cobra.ExactArgs(minArgsPullWindow),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			handler := newHookHandler(cmd, aerospaceClient)
-			return handler.handlePullWindow(args[0], args[1])
+
+			filterFlags, _ := cmd.Flags().GetStringArray("filter")
+			filters, err := aerospace.ParseFilters(filterFlags)
+			if err != nil {
+				return handler.fail("Error: invalid filter", err, "HOOK: invalid filter")
+			}
+			target, _ := cmd.Flags().GetString(pullTargetFlag)
+			if target == "" || aerospace.IsScratchpadWorkspace(target) {
+				return handler.fail(
+					fmt.Sprintf("Error: invalid --target %q", target),
+					nil,
+					"HOOK: invalid pull-window target",
+				)
+			}
+
+			return handler.handlePullWindow(args[0], args[1], pullWindowOptions{
+				Target:  target,
+				Filters: filters,
+			})
+		},
+	}
+
+	enableFilterFlag(command)
+	command.Flags().String(
+		pullTargetFlag, pullTargetPrevious,
+		`Where to pull the window: "previous", "focused-monitor" or a workspace name`,
+	)
+	_ = command.RegisterFlagCompletionFunc(pullTargetFlag, cobra.FixedCompletions(
+		[]string{pullTargetPrevious, pullTargetFocusedMonitor},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+
+	return command
+}
+
+func newFollowCmd(
+	aerospaceClient aerospace.AeroSpaceWMClient,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   fmt.Sprintf("%s [focused-workspace]", followSubcommand),
+		Short: "Move the pinned windows to the focused workspace",
+		Long: `Move the pinned windows (see pin) to the focused workspace, so they follow
+the workspace switches. The focused workspace defaults to $AEROSPACE_FOCUSED_WORKSPACE.
+
+Scratchpad workspaces are skipped, and so are the pinned windows hidden in
+the scratchpad.
+
+This is usually hooked via exec-on-workspace-change.
+
+Add this snippet in your aerospace.toml config:
+
+'''toml
+exec-on-workspace-change = ["/bin/bash", "-c",
+  "aerospace-scratchpad hook follow $AEROSPACE_FOCUSED_WORKSPACE"
+]
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			focusedWorkspace := getenv(cmd, "AEROSPACE_FOCUSED_WORKSPACE")
+			if len(args) > 0 {
+				focusedWorkspace = args[0]
+			}
+
+			handler := newHookHandler(cmd, aerospaceClient)
+			return handler.handleFollow(focusedWorkspace)
+		},
+	}
+}
+
+func newWindowDetectedCmd(
+	aerospaceClient aerospace.AeroSpaceWMClient,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   fmt.Sprintf("%s [window-id]", windowDetectedSubcommand),
+		Short: "Apply the config rules to a new window",
+		Long: `Apply the window-detected rules of the config to a new window.
+
+The window defaults to $AEROSPACE_WINDOW_ID, or the focused window. The rules
+are evaluated in order and the first one whose filters all match the window
+applies its action:
+
+  scratchpad  send the window to the scratchpad of the focused monitor
+  float       make the window floating
+  tag         tag the window
+  ignore      leave the window alone
+
+Example config (~/.config/aerospace-scratchpad/config.yaml):
+
+  window-detected:
+    rules:
+      - filter: ["app-name=^Zoom$", "window-title=Meeting"]
+        action: scratchpad
+      - filter: ["app-name=^Slack$"]
+        action: tag
+        tag: chat
+
+Add this snippet in your aerospace.toml config:
+
+'''toml
+[[on-window-detected]]
+run = ["exec-and-forget aerospace-scratchpad hook window-detected"]
+'''
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			handler := newHookHandler(cmd, aerospaceClient)
+
+			rawWindowID := getenv(cmd, "AEROSPACE_WINDOW_ID")
+			if len(args) > 0 {
+				rawWindowID = args[0]
+			}
+			windowID := 0
+			if rawWindowID != "" {
+				var err error
+				if windowID, err = strconv.Atoi(rawWindowID); err != nil {
+					return handler.fail(
+						fmt.Sprintf("Error: invalid window id %q", rawWindowID),
+						nil,
+						"HOOK: invalid window id",
+					)
+				}
+			}
+
+			return handler.handleWindowDetected(windowID)
+		},
+	}
+}
+
+func newFocusChangedCmd(
+	aerospaceClient aerospace.AeroSpaceWMClient,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   fmt.Sprintf("%s [window-id]", focusChangedSubcommand),
+		Short: "Hide the auto-hide windows that lost focus",
+		Long: `Send the windows shown with --auto-hide back to the scratchpad of their
+monitor once another window gains focus.
+
+The focused window defaults to the one AeroSpace reports. The windows
+aerospace-scratchpad itself is focusing or moving, e.g. while showing them,
+are kept.
+
+This is usually hooked via on-focus-changed.
+
+Add this snippet in your aerospace.toml config:
+
+'''toml
+on-focus-changed = ["exec-and-forget aerospace-scratchpad hook focus-changed"]
+'''
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			handler := newHookHandler(cmd, aerospaceClient)
+
+			windowID := 0
+			if len(args) > 0 {
+				var err error
+				if windowID, err = strconv.Atoi(args[0]); err != nil {
+					return handler.fail(
+						fmt.Sprintf("Error: invalid window id %q", args[0]),
+						nil,
+						"HOOK: invalid window id",
+					)
+				}
+			}
+
+			return handler.handleFocusChanged(windowID)
 		},
 	}
 }
@@ -79,19 +279,31 @@ func newHookHandler(
 ) *hookHandler {
 	return &hookHandler{
 		cmd:    cmd,
-		client: client,
+		client: aerospace.LazyWithContext(cmd.Context(), client),
+		logger: logger.GetDefaultLogger(),
+	}
+}
+
+// newConfigHookHandler returns a handler without a client, for the
+// subcommands that only edit the AeroSpace config.
+func newConfigHookHandler(cmd *cobra.Command) *hookHandler {
+	return &hookHandler{
+		cmd:    cmd,
 		logger: logger.GetDefaultLogger(),
 	}
 }
 
+//nolint:funlen // the steps deciding whether to pull read best in order
 func (h *hookHandler) handlePullWindow(
 	prevWorkspace string,
 	focusedWorkspace string,
+	options pullWindowOptions,
 ) error {
 	h.logger.LogInfo(
 		"HOOK: pull-window invoked",
 		"previous-workspace", prevWorkspace,
 		"focused-workspace", focusedWorkspace,
+		"target", options.Target,
 	)
 
 	if aerospace.IsScratchpadWorkspace(prevWorkspace) {
@@ -107,6 +319,15 @@ func (h *hookHandler) handlePullWindow(
 			"HOOK: focused workspace is not scratchpad",
 			"workspace", focusedWorkspace,
 		)
+		// Remembered for --workspace prev, AeroSpace doesn't tell it later.
+		if prevWorkspace != "" && prevWorkspace != focusedWorkspace && persistsState(h.cmd) {
+			if err := state.WritePreviousWorkspace(prevWorkspace); err != nil {
+				h.logger.LogError("HOOK: unable to remember previous workspace", "error", err)
+			}
+		}
+		if options.Target == pullTargetFocusedMonitor && persistsState(h.cmd) {
+			h.rememberMonitorWorkspace(focusedWorkspace)
+		}
 		return nil
 	}
 
@@ -131,60 +352,469 @@ func (h *hookHandler) handlePullWindow(
 		return nil
 	}
 
-	cleared, markerErr := h.clearMovingMarker()
+	marker, beingMoved, markerErr := state.ConsumeMovingMarker(focusedWindow.WindowID)
 	if markerErr != nil {
 		return h.fail(
-			"Error: unable to remove temp file",
+			"Error: unable to check moving marker",
 			markerErr,
-			"HOOK: unable to remove temp file",
+			"HOOK: unable to check moving marker",
 		)
 	}
 
-	if cleared {
-		h.logger.LogInfo("HOOK: temp file exists, returning")
+	if beingMoved {
+		h.logger.LogInfo("HOOK: window is being moved on purpose, returning", "marker", marker)
 		return nil
 	}
+	if marker != nil {
+		h.logger.LogDebug("HOOK: moving marker refers to another window", "marker", marker)
+	}
 
-	if moveErr := h.moveWindowToWorkspace(focusedWindow.WindowID, prevWorkspace); moveErr != nil {
+	target := h.pullTarget(prevWorkspace, options.Target)
+
+	exempt, err := h.isExemptFromPull(*focusedWindow, options.Filters)
+	if err != nil {
+		return err
+	}
+	if exempt {
+		// The window stays in the scratchpad, only the focus is taken back.
+		return h.focusWorkspace(target)
+	}
+
+	isNew, err := state.IsNewWindow(focusedWindow.WindowID)
+	if err != nil {
+		h.logger.LogError("HOOK: unable to check new windows", "error", err)
+	}
+	if isNew {
+		// Created in the scratchpad, e.g. by a window-detected rule, it stays
+		// hidden and only the focus is taken back.
+		h.logger.LogInfo("HOOK: window is brand-new, focusing target", "window", focusedWindow)
+		return h.focusWorkspace(target)
+	}
+
+	if moveErr := h.moveWindowToWorkspace(focusedWindow.WindowID, target, true); moveErr != nil {
 		return moveErr
 	}
 
 	h.logger.LogInfo(
 		"HOOK: [final] moved window to new focused workspace",
-		"workspace", prevWorkspace,
+		"workspace", target,
 		"window", focusedWindow,
 	)
 
 	return nil
 }
 
-func (h *hookHandler) clearMovingMarker() (bool, error) {
-	_, err := os.Stat(constants.TempScratchpadMovingFile)
+// isExemptFromPull reports whether pull-window leaves the window in the
+// scratchpad: it is pinned or doesn't match the filters.
+func (h *hookHandler) isExemptFromPull(
+	window windowsipc.Window,
+	filters []aerospace.Filter,
+) (bool, error) {
+	pinned, err := openPins().List()
 	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return false, nil
+		h.logger.LogError("HOOK: unable to read pinned windows", "error", err)
+	}
+	for _, pinnedWindow := range pinned {
+		if pinnedWindow.WindowID == window.WindowID {
+			h.logger.LogInfo("HOOK: window is pinned, leaving it alone", "window", window)
+			return true, nil
 		}
-		return false, err
 	}
 
-	if removeErr := os.Remove(constants.TempScratchpadMovingFile); removeErr != nil {
-		return true, removeErr
+	matched, err := aerospace.ApplyFilters(window, filters)
+	if err != nil {
+		return true, h.fail("Error: invalid filter", err, "HOOK: invalid filter")
+	}
+	if !matched {
+		h.logger.LogInfo("HOOK: window doesn't match the filters, leaving it alone", "window", window)
+		return true, nil
 	}
 
-	return true, nil
+	return false, nil
 }
 
-func (h *hookHandler) moveWindowToWorkspace(windowID int, workspace string) error {
-	client := h.client.Connection()
+// pullTarget returns the workspace of --target, the previous workspace when
+// the focused monitor has none remembered yet.
+func (h *hookHandler) pullTarget(prevWorkspace string, target string) string {
+	switch target {
+	case pullTargetPrevious:
+		return prevWorkspace
+	case pullTargetFocusedMonitor:
+		monitor, err := aerospace.GetFocusedMonitor(h.client)
+		if err != nil {
+			h.logger.LogError("HOOK: unable to get focused monitor", "error", err)
+			return prevWorkspace
+		}
+		workspace, err := state.ReadMonitorWorkspace(monitor.MonitorID)
+		if err != nil {
+			h.logger.LogError("HOOK: unable to read monitor workspaces", "error", err)
+		}
+		if workspace == "" {
+			h.logger.LogDebug("HOOK: no workspace remembered for monitor", "monitor", monitor)
+			return prevWorkspace
+		}
+		return workspace
+	default:
+		return target
+	}
+}
 
-	response, err := client.SendCommand(
-		"move-node-to-workspace",
-		[]string{
-			workspace,
-			"--window-id", strconv.Itoa(windowID),
-			"--focus-follows-window",
-		},
+// rememberMonitorWorkspace records the focused workspace for --target
+// focused-monitor, AeroSpace only tells the workspace visible on a monitor.
+func (h *hookHandler) rememberMonitorWorkspace(focusedWorkspace string) {
+	monitor, err := aerospace.GetFocusedMonitor(h.client)
+	if err != nil {
+		h.logger.LogError("HOOK: unable to get focused monitor", "error", err)
+		return
+	}
+	if err = state.WriteMonitorWorkspace(monitor.MonitorID, focusedWorkspace); err != nil {
+		h.logger.LogError("HOOK: unable to remember monitor workspace", "error", err)
+	}
+}
+
+func (h *hookHandler) handleFollow(focusedWorkspace string) error {
+	h.logger.LogInfo("HOOK: follow invoked", "focused-workspace", focusedWorkspace)
+
+	if focusedWorkspace == "" {
+		return h.fail(
+			"Error: the focused workspace is required",
+			nil,
+			"HOOK: follow without focused workspace",
+		)
+	}
+
+	if aerospace.IsScratchpadWorkspace(focusedWorkspace) {
+		h.logger.LogDebug(
+			"HOOK: focused workspace is scratchpad, nothing to do",
+			"workspace", focusedWorkspace,
+		)
+		return nil
+	}
+
+	pins := openPins()
+	pinned, err := pins.List()
+	if err != nil {
+		return h.fail(
+			"Error: unable to read pinned windows",
+			err,
+			"HOOK: unable to read pinned windows",
+		)
+	}
+	if len(pinned) == 0 {
+		h.logger.LogDebug("HOOK: no pinned windows")
+		return nil
+	}
+
+	allWindows, err := h.client.Windows().GetAllWindows()
+	if err != nil {
+		return h.fail(
+			"Error: unable to get windows",
+			err,
+			"HOOK: unable to get windows",
+		)
+	}
+	workspaceOf := make(map[int]string, len(allWindows))
+	for _, window := range allWindows {
+		workspaceOf[window.WindowID] = window.Workspace
+	}
+
+	var closed []int
+	for _, window := range pinned {
+		workspace, exists := workspaceOf[window.WindowID]
+		switch {
+		case !exists:
+			closed = append(closed, window.WindowID)
+			continue
+		case workspace == focusedWorkspace:
+			continue
+		case aerospace.IsScratchpadWorkspace(workspace):
+			h.logger.LogDebug(
+				"HOOK: pinned window is hidden in the scratchpad, skipping",
+				"window", window,
+			)
+			continue
+		}
+
+		if moveErr := h.moveWindowToWorkspace(window.WindowID, focusedWorkspace, false); moveErr != nil {
+			return moveErr
+		}
+		h.logger.LogInfo(
+			"HOOK: moved pinned window to focused workspace",
+			"window", window,
+			"workspace", focusedWorkspace,
+		)
+	}
+
+	if len(closed) > 0 && persistsState(h.cmd) {
+		h.logger.LogInfo("HOOK: unpinning closed windows", "windowIDs", closed)
+		if removeErr := pins.Remove(closed...); removeErr != nil {
+			h.logger.LogError("HOOK: unable to unpin closed windows", "error", removeErr)
+		}
+	}
+
+	return nil
+}
+
+// handleWindowDetected applies the first config rule matching the window.
+// windowID 0 stands for the focused window.
+func (h *hookHandler) handleWindowDetected(windowID int) error {
+	h.logger.LogInfo("HOOK: window-detected invoked", "windowID", windowID)
+
+	// Marked before any rule moves it, see pull-window.
+	if windowID != 0 {
+		h.markNewWindow(windowID)
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return h.fail("Error: unable to load config", err, "HOOK: unable to load config")
+	}
+	rules := cfg.WindowDetected.Rules
+	if len(rules) == 0 {
+		h.logger.LogDebug("HOOK: no window-detected rules configured")
+		return nil
+	}
+
+	window, err := h.findWindow(windowID)
+	if err != nil {
+		return h.fail("Error: unable to get the new window", err, "HOOK: unable to get the new window")
+	}
+	if windowID == 0 {
+		h.markNewWindow(window.WindowID)
+	}
+
+	for index, rule := range rules {
+		filters, parseErr := aerospace.ParseFilters(rule.Filter)
+		if parseErr != nil {
+			return h.fail(
+				fmt.Sprintf("Error: invalid filter in window-detected rule %d", index+1),
+				parseErr,
+				"HOOK: invalid window-detected rule",
+			)
+		}
+		matched, applyErr := aerospace.ApplyFilters(*window, filters)
+		if applyErr != nil {
+			return h.fail(
+				fmt.Sprintf("Error: invalid filter in window-detected rule %d", index+1),
+				applyErr,
+				"HOOK: invalid window-detected rule",
+			)
+		}
+		if !matched {
+			continue
+		}
+
+		h.logger.LogInfo(
+			"HOOK: window-detected decision",
+			"window", window,
+			"rule", index+1,
+			"action", rule.Action,
+			"tag", rule.Tag,
+		)
+		return h.applyWindowRule(*window, rule)
+	}
+
+	h.logger.LogInfo(
+		"HOOK: window-detected decision",
+		"window", window,
+		"rule", "none",
+		"action", "none",
 	)
+	return nil
+}
+
+// handleFocusChanged hides the auto-hide windows other than the focused one.
+// windowID 0 stands for the focused window.
+func (h *hookHandler) handleFocusChanged(windowID int) error {
+	h.logger.LogInfo("HOOK: focus-changed invoked", "windowID", windowID)
+
+	marker, err := state.RecentFocusMarker()
+	if err != nil {
+		return h.fail(
+			"Error: unable to check focus marker",
+			err,
+			"HOOK: unable to check focus marker",
+		)
+	}
+	if marker == nil {
+		marker = &state.FocusMarker{}
+	}
+
+	autoHide := openAutoHide()
+	tracked, err := autoHide.List()
+	if err != nil {
+		return h.fail(
+			"Error: unable to read auto-hide windows",
+			err,
+			"HOOK: unable to read auto-hide windows",
+		)
+	}
+	if len(tracked) == 0 {
+		h.logger.LogDebug("HOOK: no auto-hide windows")
+		return nil
+	}
+
+	if windowID == 0 {
+		// Nothing is focused e.g. on an empty workspace, which means the
+		// focus left the auto-hide windows as well.
+		if focusedWindow, focusErr := h.client.Windows().GetFocusedWindow(); focusErr == nil {
+			windowID = focusedWindow.WindowID
+		} else {
+			h.logger.LogDebug("HOOK: no focused window", "error", focusErr)
+		}
+	}
+
+	allWindows, err := h.client.Windows().GetAllWindows()
+	if err != nil {
+		return h.fail(
+			"Error: unable to get windows",
+			err,
+			"HOOK: unable to get windows",
+		)
+	}
+	windowsByID := make(map[int]windowsipc.Window, len(allWindows))
+	for _, window := range allWindows {
+		windowsByID[window.WindowID] = window
+	}
+
+	mover := newMover(h.cmd, h.client)
+	var untracked []int
+	for _, trackedWindow := range tracked {
+		window, exists := windowsByID[trackedWindow.WindowID]
+		switch {
+		case !exists, aerospace.IsScratchpadWorkspace(window.Workspace):
+			// Closed or hidden already, it has to be shown again to auto-hide.
+			untracked = append(untracked, trackedWindow.WindowID)
+			continue
+		case window.WindowID == windowID, trackedWindow.Timed():
+			// Timed windows are hidden by the hide timer instead.
+			continue
+		case marker.Covers(window.WindowID):
+			h.logger.LogInfo("HOOK: window moved by aerospace-scratchpad, keeping", "window", window)
+			continue
+		}
+
+		if _, moveErr := mover.MoveWindowToScratchpadForMonitor(
+			h.cmd.Context(),
+			window,
+			trackedWindow.MonitorID,
+		); moveErr != nil {
+			return h.fail(
+				fmt.Sprintf("Error: unable to move window %d to scratchpad", window.WindowID),
+				moveErr,
+				"HOOK: unable to hide auto-hide window",
+			)
+		}
+		untracked = append(untracked, trackedWindow.WindowID)
+		h.logger.LogInfo("HOOK: hid auto-hide window", "window", window, "focusedWindowID", windowID)
+	}
+
+	if len(untracked) > 0 && persistsState(h.cmd) {
+		if removeErr := autoHide.Remove(untracked...); removeErr != nil {
+			h.logger.LogError("HOOK: unable to update auto-hide windows", "error", removeErr)
+		}
+	}
+
+	return nil
+}
+
+// markNewWindow tells pull-window the window is brand-new, so it isn't pulled
+// out of the scratchpad it was created in.
+func (h *hookHandler) markNewWindow(windowID int) {
+	if !persistsState(h.cmd) {
+		return
+	}
+	if err := state.WriteNewWindow(windowID); err != nil {
+		h.logger.LogError("HOOK: unable to mark new window", "error", err)
+	}
+}
+
+func (h *hookHandler) findWindow(windowID int) (*windowsipc.Window, error) {
+	if windowID == 0 {
+		return h.client.Windows().GetFocusedWindow()
+	}
+
+	allWindows, err := h.client.Windows().GetAllWindows()
+	if err != nil {
+		return nil, err
+	}
+	for _, window := range allWindows {
+		if window.WindowID == windowID {
+			return &window, nil
+		}
+	}
+	return nil, fmt.Errorf("window %d not found", windowID)
+}
+
+func (h *hookHandler) applyWindowRule(window windowsipc.Window, rule config.WindowRule) error {
+	switch rule.Action {
+	case config.ActionScratchpad:
+		monitorID := 0
+		if monitor, err := aerospace.GetFocusedMonitor(h.client); err == nil {
+			monitorID = monitor.MonitorID
+		}
+		mover := newMover(h.cmd, h.client)
+		markFocusing(h.cmd, window.WindowID)
+		if _, err := mover.MoveWindowToScratchpadForMonitor(h.cmd.Context(), window, monitorID); err != nil {
+			return h.fail(
+				fmt.Sprintf("Error: unable to move window %d to scratchpad", window.WindowID),
+				err,
+				"HOOK: unable to move new window to scratchpad",
+			)
+		}
+	case config.ActionFloat:
+		if err := h.client.Layout().SetLayout([]string{floatingLayout}, layout.SetLayoutOpts{
+			WindowID: layout.IntPtr(window.WindowID),
+		}); err != nil {
+			return h.fail(
+				fmt.Sprintf("Error: unable to float window %d", window.WindowID),
+				err,
+				"HOOK: unable to float new window",
+			)
+		}
+	case config.ActionTag:
+		if !persistsState(h.cmd) {
+			return nil
+		}
+		if err := openTags().Add(state.TaggedWindow{
+			WindowID: window.WindowID,
+			AppName:  window.AppName,
+			Tag:      rule.Tag,
+		}); err != nil {
+			return h.fail(
+				fmt.Sprintf("Error: unable to tag window %d", window.WindowID),
+				err,
+				"HOOK: unable to tag new window",
+			)
+		}
+	case config.ActionIgnore:
+	}
+
+	return nil
+}
+
+func openTags() *state.Tags {
+	return state.NewTags(state.DefaultTagsPath())
+}
+
+func (h *hookHandler) moveWindowToWorkspace(
+	windowID int,
+	workspace string,
+	focusFollows bool,
+) error {
+	client := h.client.Connection()
+
+	markFocusing(h.cmd, windowID)
+	args := []string{
+		workspace,
+		"--window-id", strconv.Itoa(windowID),
+	}
+	followFlag := focusFollows &&
+		aerospace.CapabilitiesOf(h.client).Supports(aerospace.CapabilityFocusFollowsWindow)
+	if followFlag {
+		args = append(args, "--focus-follows-window")
+	}
+	response, err := client.SendCommand("move-node-to-workspace", args)
 	if err != nil {
 		return h.fail(
 			fmt.Sprintf("Error: unable to move window %d to workspace %s", windowID, workspace),
@@ -201,6 +831,36 @@ func (h *hookHandler) moveWindowToWorkspace(windowID int, workspace string) erro
 		)
 	}
 
+	// AeroSpace without --focus-follows-window gets the focus set afterwards.
+	if focusFollows && !followFlag {
+		if err = h.client.Focus().SetFocusByWindowID(windowID); err != nil {
+			return h.fail(
+				fmt.Sprintf("Error: unable to focus window %d", windowID),
+				err,
+				"HOOK: unable to focus moved window",
+			)
+		}
+	}
+
+	return nil
+}
+
+func (h *hookHandler) focusWorkspace(workspace string) error {
+	response, err := h.client.Connection().SendCommand("workspace", []string{workspace})
+	if err != nil {
+		return h.fail(
+			fmt.Sprintf("Error: unable to focus workspace %s", workspace),
+			err,
+			"HOOK: unable to focus workspace",
+		)
+	}
+	if response.ExitCode != 0 {
+		return h.fail(
+			fmt.Sprintf("Error: unable to focus workspace %s", workspace),
+			errors.New(response.StdErr),
+			"HOOK: unable to focus workspace - non-zero exit",
+		)
+	}
 	return nil
 }
 
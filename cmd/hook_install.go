@@ -0,0 +1,229 @@
+/*
+Copyright © 2025 Cristian Oliveira licence@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospacetoml"
+)
+
+const (
+	installSubcommand   = "install"
+	uninstallSubcommand = "uninstall"
+
+	hookConfigFlag        = "config"
+	hookHooksFlag         = "hooks"
+	hookNoKeybindingsFlag = "no-keybindings"
+
+	defaultConfigFileMode = 0o644
+	defaultConfigDirMode  = 0o755
+)
+
+func newInstallCmd() *cobra.Command {
+	command := &cobra.Command{
+		Use:   installSubcommand,
+		Short: "Add the hooks and suggested keybindings to aerospace.toml",
+		Long: `Add the hooks and the suggested keybindings to the AeroSpace config.
+
+The existing exec-on-workspace-change and on-focus-changed commands are kept,
+the hooks run after them. Keybindings whose key or command is bound already
+are skipped. The config is backed up next to it before being changed, and
+the changes are marked so "hook uninstall" can revert them.
+
+Use --dry-run to see the changes as a unified diff without writing them.
+
+Example:
+  aerospace-scratchpad hook install --config ~/.aerospace.toml --dry-run
+  aerospace-scratchpad hook install --hooks pull-window,focus-changed
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			handler := newConfigHookHandler(cmd)
+
+			rawHooks, _ := cmd.Flags().GetStringSlice(hookHooksFlag)
+			hooks := make([]aerospacetoml.Hook, 0, len(rawHooks))
+			for _, rawHook := range rawHooks {
+				hook := aerospacetoml.Hook(strings.TrimSpace(rawHook))
+				if !slices.Contains(aerospacetoml.Hooks, hook) {
+					return handler.fail(
+						fmt.Sprintf("Error: unknown hook %q, expected one of %v", rawHook, aerospacetoml.Hooks),
+						nil,
+						"HOOK: unknown hook to install",
+					)
+				}
+				hooks = append(hooks, hook)
+			}
+
+			opts := aerospacetoml.Options{
+				Program: programName,
+				Hooks:   hooks,
+			}
+			if noKeybindings, _ := cmd.Flags().GetBool(hookNoKeybindingsFlag); !noKeybindings {
+				opts.Keybindings = aerospacetoml.SuggestedKeybindings(programName)
+			}
+
+			return handler.patchAeroSpaceConfig(func(content string) (string, []string, error) {
+				result, err := aerospacetoml.Install(content, opts)
+				return result.Content, result.Notes, err
+			})
+		},
+	}
+
+	command.Flags().String(
+		hookConfigFlag, "",
+		"The AeroSpace config file (default ~/.aerospace.toml or ~/.config/aerospace/aerospace.toml)",
+	)
+	command.Flags().StringSlice(
+		hookHooksFlag, []string{string(aerospacetoml.HookPullWindow)},
+		"The hooks to install: pull-window, follow, focus-changed",
+	)
+	command.Flags().Bool(
+		hookNoKeybindingsFlag, false,
+		"Don't add the suggested keybindings",
+	)
+
+	return command
+}
+
+func newUninstallCmd() *cobra.Command {
+	command := &cobra.Command{
+		Use:   uninstallSubcommand,
+		Short: "Revert the changes of hook install in aerospace.toml",
+		Long: `Revert the changes of "hook install" in the AeroSpace config, restoring the
+lines it replaced. The config is backed up next to it before being changed.
+
+Use --dry-run to see the changes as a unified diff without writing them.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			handler := newConfigHookHandler(cmd)
+
+			return handler.patchAeroSpaceConfig(func(content string) (string, []string, error) {
+				reverted, found, err := aerospacetoml.Uninstall(content)
+				if err != nil || found {
+					return reverted, nil, err
+				}
+				return reverted, []string{"no changes of hook install found"}, nil
+			})
+		},
+	}
+
+	command.Flags().String(
+		hookConfigFlag, "",
+		"The AeroSpace config file (default ~/.aerospace.toml or ~/.config/aerospace/aerospace.toml)",
+	)
+
+	return command
+}
+
+// patchAeroSpaceConfig applies patch to the AeroSpace config, printing the
+// diff instead with --dry-run.
+func (h *hookHandler) patchAeroSpaceConfig(
+	patch func(content string) (string, []string, error),
+) error {
+	path, _ := h.cmd.Flags().GetString(hookConfigFlag)
+	if path == "" {
+		path = aerospacetoml.ConfigPath()
+	}
+	path = expandHome(path)
+
+	fileMode := os.FileMode(defaultConfigFileMode)
+	exists := true
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		exists = false
+	case err != nil:
+		return h.fail("Error: unable to read the AeroSpace config", err, "HOOK: unable to read config")
+	default:
+		if info, statErr := os.Stat(path); statErr == nil {
+			fileMode = info.Mode().Perm()
+		}
+	}
+	content := string(data)
+
+	patched, notes, err := patch(content)
+	if err != nil {
+		return h.fail(
+			fmt.Sprintf("Error: unable to update %s", path),
+			err,
+			"HOOK: unable to patch config",
+		)
+	}
+
+	out := h.cmd.OutOrStdout()
+	for _, note := range notes {
+		fmt.Fprintf(out, "Skipped: %s\n", note)
+	}
+
+	if patched == content {
+		fmt.Fprintf(out, "No changes to %s\n", path)
+		return nil
+	}
+
+	if dryRun, _ := h.cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Fprint(out, aerospacetoml.UnifiedDiff(path, path, content, patched))
+		return nil
+	}
+
+	if exists {
+		backupPath, backupErr := backupFile(path, data, fileMode)
+		if backupErr != nil {
+			return h.fail("Error: unable to back up the AeroSpace config", backupErr, "HOOK: unable to back up config")
+		}
+		fmt.Fprintf(out, "Backup: %s\n", backupPath)
+	} else if err = os.MkdirAll(filepath.Dir(path), defaultConfigDirMode); err != nil {
+		return h.fail("Error: unable to create the AeroSpace config", err, "HOOK: unable to create config dir")
+	}
+
+	// Written in place, so a config symlinked from a dotfiles repo stays a link.
+	if err = os.WriteFile(path, []byte(patched), fileMode); err != nil {
+		return h.fail("Error: unable to write the AeroSpace config", err, "HOOK: unable to write config")
+	}
+
+	h.logger.LogInfo("HOOK: updated AeroSpace config", "path", path)
+	fmt.Fprintf(out, "Updated %s\nReload it with: aerospace reload-config\n", path)
+	return nil
+}
+
+// backupFile writes data next to path, never replacing an older backup.
+func backupFile(path string, data []byte, fileMode os.FileMode) (string, error) {
+	base := fmt.Sprintf("%s.%s", path, time.Now().Format("20060102-150405"))
+	backupPath := base + ".bak"
+	for attempt := 1; ; attempt++ {
+		file, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileMode)
+		if errors.Is(err, os.ErrExist) {
+			backupPath = fmt.Sprintf("%s-%d.bak", base, attempt)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err = file.Write(data); err != nil {
+			_ = file.Close()
+			return "", err
+		}
+		return backupPath, file.Close()
+	}
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
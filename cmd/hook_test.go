@@ -3,7 +3,10 @@ package cmd_test
 import (
 	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"go.uber.org/mock/gomock"
 
@@ -12,14 +15,20 @@ import (
 	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
 )
 
 func cleanupMarkerFile(t *testing.T) {
 	t.Helper()
 
-	err := os.Remove(constants.TempScratchpadMovingFile)
+	path, err := state.MovingMarkerPath()
+	if err != nil {
+		t.Fatalf("failed to get marker path: %v", err)
+	}
+	err = os.Remove(path)
 	if err != nil && !os.IsNotExist(err) {
 		t.Fatalf("failed to clean marker file: %v", err)
 	}
@@ -111,7 +120,7 @@ func TestHookPullWindow(t *testing.T) {
 	t.Run("skips move when marker file exists", func(t *testing.T) {
 		cleanupMarkerFile(t)
 
-		err := os.WriteFile(constants.TempScratchpadMovingFile, []byte("moving"), 0o600)
+		err := state.WriteMovingMarker(124)
 		if err != nil {
 			t.Fatalf("failed to create marker file: %v", err)
 		}
@@ -311,3 +320,764 @@ func TestHookPullWindowPerMonitor(t *testing.T) {
 		}
 	})
 }
+
+func TestHookPullWindowCapabilities(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("focuses the window itself on AeroSpace without --focus-follows-window", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+		// The socket client refuses old versions, the in-process one doesn't.
+		world, err := fakeaerospace.ParseWorld([]byte(`
+server-version: 0.14.2-Beta old
+focused-workspace: .scratchpad
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+  - workspace: .scratchpad
+    focused-window-id: 2
+windows:
+  - window-id: 1
+    app-name: Ghostty
+    workspace: ws1
+  - window-id: 2
+    app-name: Finder
+    workspace: .scratchpad
+    window-layout: floating
+`))
+		if err != nil {
+			t.Fatalf("unable to parse world: %v", err)
+		}
+		client := aerospace.NewClientFromConnection(fakeaerospace.NewConnection(world))
+
+		sessionPath := filepath.Join(t.TempDir(), "session.jsonl")
+		_, err = testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"hook", "pull-window", "ws1", ".scratchpad", "--record", sessionPath,
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		snapshot := world.Snapshot()
+		if snapshot.FocusedWorkspace != "ws1" {
+			t.Fatalf("expected ws1 focused, got %q", snapshot.FocusedWorkspace)
+		}
+		for _, window := range snapshot.Windows {
+			if window.WindowID == 2 && window.Workspace != "ws1" {
+				t.Fatalf("expected Finder pulled to ws1, got %q", window.Workspace)
+			}
+		}
+
+		session, err := os.ReadFile(sessionPath)
+		if err != nil {
+			t.Fatalf("unable to read session: %v", err)
+		}
+		if strings.Contains(string(session), "--focus-follows-window") {
+			t.Fatalf("expected no --focus-follows-window sent, got:\n%s", session)
+		}
+	})
+}
+
+func TestHookWindowDetected(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	const detectedWorld = `
+workspaces:
+  - workspace: ws1
+    focused-window-id: 3
+windows:
+  - window-id: 1
+    app-name: zoom.us
+    window-title: Zoom Meeting
+    workspace: ws1
+  - window-id: 2
+    app-name: Finder
+    workspace: ws1
+  - window-id: 3
+    app-name: Slack
+    workspace: ws1
+  - window-id: 4
+    app-name: Ghostty
+    workspace: ws1
+`
+	const rules = `
+window-detected:
+  rules:
+    - filter: ["app-name=^zoom", "window-title=Meeting"]
+      action: scratchpad
+    - filter: ["app-name=^Finder$"]
+      action: float
+    - filter: ["app-name=^Slack$"]
+      action: tag
+      tag: chat
+    - filter: ["app-name=.*"]
+      action: ignore
+`
+
+	setup := func(t *testing.T) (aerospace.AeroSpaceWMClient, func(int) windows.Window) {
+		t.Helper()
+
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(rules), 0o600); err != nil {
+			t.Fatalf("unable to write config: %v", err)
+		}
+		t.Setenv(constants.EnvAeroSpaceScratchpadConfig, configPath)
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		client, world := testutils.StartFakeAeroSpace(t, detectedWorld)
+		return client, func(id int) windows.Window {
+			for _, window := range world.Snapshot().Windows {
+				if window.WindowID == id {
+					return windows.Window{
+						WindowID:     window.WindowID,
+						Workspace:    window.Workspace,
+						WindowLayout: window.WindowLayout,
+					}
+				}
+			}
+			t.Fatalf("window %d not found", id)
+			return windows.Window{}
+		}
+	}
+
+	t.Run("sends matching windows to the scratchpad", func(t *testing.T) {
+		client, windowByID := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "window-detected", "1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := windowByID(1).Workspace; workspace != ".scratchpad" {
+			t.Fatalf("expected zoom in the scratchpad, got %q", workspace)
+		}
+		// So pull-window leaves it there once it takes the focus.
+		if isNew, err := state.IsNewWindow(1); err != nil || !isNew {
+			t.Fatalf("expected zoom marked as new, got %v %v", isNew, err)
+		}
+	})
+
+	t.Run("floats matching windows", func(t *testing.T) {
+		client, windowByID := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "window-detected", "2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		window := windowByID(2)
+		if window.Workspace != "ws1" || window.WindowLayout != "floating" {
+			t.Fatalf("expected Finder floating in ws1, got %+v", window)
+		}
+	})
+
+	t.Run("tags the focused window by default", func(t *testing.T) {
+		client, windowByID := setup(t)
+		t.Setenv("AEROSPACE_WINDOW_ID", "")
+		_ = os.Unsetenv("AEROSPACE_WINDOW_ID")
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "window-detected"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if window := windowByID(3); window.Workspace != "ws1" || window.WindowLayout == "floating" {
+			t.Fatalf("expected Slack untouched, got %+v", window)
+		}
+
+		tagged, err := state.NewTags(state.DefaultTagsPath()).List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tagged) != 1 || tagged[0].WindowID != 3 || tagged[0].Tag != "chat" {
+			t.Fatalf("expected Slack tagged chat, got %+v", tagged)
+		}
+	})
+
+	t.Run("leaves ignored windows alone", func(t *testing.T) {
+		client, windowByID := setup(t)
+		t.Setenv("AEROSPACE_WINDOW_ID", "4")
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "window-detected"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if window := windowByID(4); window.Workspace != "ws1" || window.WindowLayout == "floating" {
+			t.Fatalf("expected Ghostty untouched, got %+v", window)
+		}
+	})
+
+	t.Run("fails for unknown windows", func(t *testing.T) {
+		client, _ := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "window-detected", "42"); err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+}
+
+func TestHookPullWindowOptions(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	// Finder was focused in the scratchpad, shown on the second monitor, while
+	// working in ws1 on the first one.
+	const pullWorld = `
+focused-workspace: .scratchpad
+monitors:
+  - monitor-id: 1
+  - monitor-id: 2
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+    monitor-id: 1
+  - workspace: ws3
+    monitor-id: 2
+  - workspace: .scratchpad
+    focused-window-id: 2
+    monitor-id: 2
+windows:
+  - window-id: 1
+    app-name: Ghostty
+    workspace: ws1
+  - window-id: 2
+    app-name: Finder
+    workspace: .scratchpad
+    window-layout: floating
+`
+
+	setup := func(t *testing.T) (aerospace.AeroSpaceWMClient, *fakeaerospace.World) {
+		t.Helper()
+
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, world := testutils.StartFakeAeroSpace(t, pullWorld)
+		return client, world
+	}
+	pull := func(t *testing.T, client aerospace.AeroSpaceWMClient, flags ...string) {
+		t.Helper()
+
+		args := append([]string{"hook", "pull-window", "ws1", ".scratchpad"}, flags...)
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), args...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("pulls the windows matching the filter", func(t *testing.T) {
+		client, world := setup(t)
+
+		pull(t, client, "--filter", "app-name=^Finder$")
+
+		if got := windowWorkspace(t, world, 2); got != "ws1" {
+			t.Fatalf("expected Finder pulled to ws1, got %q", got)
+		}
+	})
+
+	t.Run("leaves the windows not matching the filter and focuses the target", func(t *testing.T) {
+		client, world := setup(t)
+
+		pull(t, client, "--filter", "app-name=^Slack$")
+
+		if got := windowWorkspace(t, world, 2); got != ".scratchpad" {
+			t.Fatalf("expected Finder left in the scratchpad, got %q", got)
+		}
+		if got := world.Snapshot().FocusedWorkspace; got != "ws1" {
+			t.Fatalf("expected ws1 focused, got %q", got)
+		}
+	})
+
+	t.Run("leaves the pinned windows and focuses the target", func(t *testing.T) {
+		client, world := setup(t)
+		pins := state.NewPins(state.DefaultPinsPath())
+		if err := pins.Add(state.PinnedWindow{WindowID: 2, AppName: "Finder"}); err != nil {
+			t.Fatalf("unable to pin: %v", err)
+		}
+
+		pull(t, client)
+
+		if got := windowWorkspace(t, world, 2); got != ".scratchpad" {
+			t.Fatalf("expected Finder left in the scratchpad, got %q", got)
+		}
+		if got := world.Snapshot().FocusedWorkspace; got != "ws1" {
+			t.Fatalf("expected ws1 focused, got %q", got)
+		}
+	})
+
+	t.Run("pulls to the target workspace", func(t *testing.T) {
+		client, world := setup(t)
+
+		pull(t, client, "--target", "ws3")
+
+		if got := windowWorkspace(t, world, 2); got != "ws3" {
+			t.Fatalf("expected Finder pulled to ws3, got %q", got)
+		}
+	})
+
+	t.Run("pulls to the workspace last focused on the monitor", func(t *testing.T) {
+		client, world := setup(t)
+		world.Execute([]string{"workspace", "ws3"})
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"hook", "pull-window", "ws1", "ws3", "--target", "focused-monitor",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		world.Execute([]string{"workspace", ".scratchpad"})
+
+		pull(t, client, "--target", "focused-monitor")
+
+		if got := windowWorkspace(t, world, 2); got != "ws3" {
+			t.Fatalf("expected Finder pulled to ws3, got %q", got)
+		}
+	})
+
+	t.Run("falls back to the previous workspace without one for the monitor", func(t *testing.T) {
+		client, world := setup(t)
+
+		pull(t, client, "--target", "focused-monitor")
+
+		if got := windowWorkspace(t, world, 2); got != "ws1" {
+			t.Fatalf("expected Finder pulled to ws1, got %q", got)
+		}
+	})
+
+	t.Run("leaves brand-new windows and focuses the target", func(t *testing.T) {
+		client, world := setup(t)
+		if err := state.WriteNewWindow(2); err != nil {
+			t.Fatalf("unable to mark new window: %v", err)
+		}
+
+		pull(t, client)
+
+		if got := windowWorkspace(t, world, 2); got != ".scratchpad" {
+			t.Fatalf("expected Finder left in the scratchpad, got %q", got)
+		}
+		if got := world.Snapshot().FocusedWorkspace; got != "ws1" {
+			t.Fatalf("expected ws1 focused, got %q", got)
+		}
+	})
+
+	t.Run("rejects a scratchpad target", func(t *testing.T) {
+		client, _ := setup(t)
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"hook", "pull-window", "ws1", ".scratchpad", "--target", ".scratchpad.2",
+		)
+		if err == nil || !strings.Contains(err.Error(), "invalid --target") {
+			t.Fatalf("expected an invalid --target error, got %v", err)
+		}
+	})
+}
+
+func TestHookPullWindowMovingMarker(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	expectPull := func(mockClient *testutils.MockAeroSpaceWM, focusedWindow *windows.Window) {
+		gomock.InOrder(
+			mockClient.GetWindowsMock().
+				EXPECT().
+				GetFocusedWindow().
+				Return(focusedWindow, nil).
+				Times(1),
+			mockClient.GetWorkspacesMock().EXPECT().
+				MoveWindowToWorkspaceWithOpts(
+					workspaces.MoveWindowToWorkspaceArgs{
+						WorkspaceName: "prev-ws",
+					},
+					workspaces.MoveWindowToWorkspaceOpts{
+						WindowID: &focusedWindow.WindowID,
+					},
+				).
+				Return(nil).
+				Times(1),
+		)
+	}
+
+	t.Run("pulls windows the marker doesn't refer to", func(t *testing.T) {
+		cleanupMarkerFile(t)
+		t.Cleanup(func() {
+			cleanupMarkerFile(t)
+		})
+		if err := state.WriteMovingMarker(7); err != nil {
+			t.Fatalf("failed to create marker: %v", err)
+		}
+
+		ctrl := gomock.NewController(t)
+		mockClient := testutils.NewMockAeroSpaceWM(ctrl)
+		expectPull(mockClient, &windows.Window{
+			WindowID:  99,
+			Workspace: constants.DefaultScratchpadWorkspaceName,
+		})
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(mockClient),
+			"hook", "pull-window", "prev-ws", constants.DefaultScratchpadWorkspaceName,
+		)
+		if err != nil {
+			t.Fatalf("expected success, got error %v", err)
+		}
+
+		// The marker is still there for window 7.
+		marker, beingMoved, err := state.ConsumeMovingMarker(7)
+		if err != nil || !beingMoved || marker.PID != os.Getpid() {
+			t.Fatalf("expected the marker of window 7, got %+v %v %v", marker, beingMoved, err)
+		}
+	})
+
+	t.Run("ignores expired markers", func(t *testing.T) {
+		cleanupMarkerFile(t)
+		path, err := state.MovingMarkerPath()
+		if err != nil {
+			t.Fatalf("failed to get marker path: %v", err)
+		}
+		stale := `{"pid":1,"timestamp":"2020-01-01T00:00:00Z","window_id":99}`
+		if err = os.WriteFile(path, []byte(stale), 0o600); err != nil {
+			t.Fatalf("failed to create marker: %v", err)
+		}
+
+		ctrl := gomock.NewController(t)
+		mockClient := testutils.NewMockAeroSpaceWM(ctrl)
+		expectPull(mockClient, &windows.Window{
+			WindowID:  99,
+			Workspace: constants.DefaultScratchpadWorkspaceName,
+		})
+
+		_, err = testutils.CmdExecute(
+			cmd.RootCmd(mockClient),
+			"hook", "pull-window", "prev-ws", constants.DefaultScratchpadWorkspaceName,
+		)
+		if err != nil {
+			t.Fatalf("expected success, got error %v", err)
+		}
+		if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+			t.Fatalf("expected the expired marker to be removed, got %v", statErr)
+		}
+	})
+}
+
+func TestHookFocusChanged(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	setup := func(t *testing.T) (aerospace.AeroSpaceWMClient, func(int) string) {
+		t.Helper()
+
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		client, world := testutils.StartFakeAeroSpace(t, fakeWorld)
+		return client, func(id int) string {
+			for _, window := range world.Snapshot().Windows {
+				if window.WindowID == id {
+					return window.Workspace
+				}
+			}
+			t.Fatalf("window %d not found", id)
+			return ""
+		}
+	}
+
+	clearFocusMarker := func(t *testing.T) {
+		t.Helper()
+
+		path, err := state.FocusMarkerPath()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			t.Fatalf("unable to remove focus marker: %v", err)
+		}
+	}
+
+	t.Run("hides auto-hide windows once another window is focused", func(t *testing.T) {
+		client, workspaceOf := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "show", "Finder", "--auto-hide"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := workspaceOf(2); workspace != "ws1" {
+			t.Fatalf("expected Finder shown in ws1, got %q", workspace)
+		}
+		clearFocusMarker(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "focus-changed", "2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := workspaceOf(2); workspace != "ws1" {
+			t.Fatalf("expected Finder kept while focused, got %q", workspace)
+		}
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "focus-changed", "1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := workspaceOf(2); workspace != ".scratchpad" {
+			t.Fatalf("expected Finder back in the scratchpad, got %q", workspace)
+		}
+
+		tracked, err := state.NewAutoHide(state.DefaultAutoHidePath()).List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tracked) != 0 {
+			t.Fatalf("expected no auto-hide windows left, got %+v", tracked)
+		}
+	})
+
+	t.Run("keeps the windows aerospace-scratchpad is showing", func(t *testing.T) {
+		client, workspaceOf := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "show", "Finder", "--auto-hide"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "focus-changed", "1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := workspaceOf(2); workspace != "ws1" {
+			t.Fatalf("expected Finder kept in ws1, got %q", workspace)
+		}
+	})
+
+	t.Run("hides the windows the focus marker doesn't cover", func(t *testing.T) {
+		client, workspaceOf := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "show", "Finder", "--auto-hide"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clearFocusMarker(t)
+		// Another window is being moved, which says nothing about Finder.
+		if err := state.WriteFocusMarker(1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "focus-changed", "1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := workspaceOf(2); workspace != ".scratchpad" {
+			t.Fatalf("expected Finder back in the scratchpad, got %q", workspace)
+		}
+	})
+
+	t.Run("leaves windows shown without --auto-hide", func(t *testing.T) {
+		client, workspaceOf := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "show", "Finder"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clearFocusMarker(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "focus-changed", "1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := workspaceOf(2); workspace != "ws1" {
+			t.Fatalf("expected Finder kept in ws1, got %q", workspace)
+		}
+	})
+}
+
+func TestHookHideTimer(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	setup := func(t *testing.T) (aerospace.AeroSpaceWMClient, *fakeaerospace.World) {
+		t.Helper()
+
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		client, world := testutils.StartFakeAeroSpace(t, fakeWorld)
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "show", "Finder", "--hide-after", "100ms"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return client, world
+	}
+
+	workspaceOf := func(t *testing.T, world *fakeaerospace.World, id int) string {
+		t.Helper()
+		for _, window := range world.Snapshot().Windows {
+			if window.WindowID == id {
+				return window.Workspace
+			}
+		}
+		return ""
+	}
+
+	t.Run("hides the window once it has been without focus long enough", func(t *testing.T) {
+		client, world := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "focus-changed", "1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := workspaceOf(t, world, 2); workspace != "ws1" {
+			t.Fatalf("expected the focus-changed hook to leave Finder, got %q", workspace)
+		}
+
+		world.Execute([]string{"focus", "--window-id", "1"})
+		startedAt := time.Now()
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"hook", "hide-timer", "--interval", "10ms", "--timeout", "2s",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if time.Since(startedAt) > time.Second {
+			t.Fatalf("expected the timer to stop once nothing is left to hide")
+		}
+		if workspace := workspaceOf(t, world, 2); workspace != ".scratchpad" {
+			t.Fatalf("expected Finder back in the scratchpad, got %q", workspace)
+		}
+
+		tracked, err := state.NewAutoHide(state.DefaultAutoHidePath()).List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tracked) != 0 {
+			t.Fatalf("expected no auto-hide windows left, got %+v", tracked)
+		}
+	})
+
+	t.Run("tracks the windows summoned with --hide-after", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "summon", "Finder", "--hide-after", "5m")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		tracked, err := state.NewAutoHide(state.DefaultAutoHidePath()).List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tracked) != 1 || tracked[0].WindowID != 2 || tracked[0].HideAfter != 5*time.Minute {
+			t.Fatalf("expected Finder hidden after 5m, got %+v", tracked)
+		}
+	})
+
+	t.Run("keeps the window while it is focused", func(t *testing.T) {
+		client, world := setup(t)
+
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			world.Execute([]string{"focus", "--window-id", "1"})
+		}()
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"hook", "hide-timer", "--interval", "10ms", "--timeout", "2s",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := workspaceOf(t, world, 2); workspace != ".scratchpad" {
+			t.Fatalf("expected Finder hidden after losing focus, got %q", workspace)
+		}
+	})
+
+	t.Run("is cancelled when the window is moved", func(t *testing.T) {
+		client, world := setup(t)
+
+		world.Execute([]string{"move-node-to-workspace", "--window-id", "2", "ws2"})
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"hook", "hide-timer", "--interval", "10ms", "--timeout", "2s",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if workspace := workspaceOf(t, world, 2); workspace != "ws2" {
+			t.Fatalf("expected Finder left in ws2, got %q", workspace)
+		}
+	})
+}
+
+func TestHookInstall(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	const aerospaceConfig = `start-at-login = true
+
+[mode.main.binding]
+alt-1 = "workspace 1"
+`
+
+	setup := func(t *testing.T) (aerospace.AeroSpaceWMClient, string) {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "aerospace.toml")
+		if err := os.WriteFile(path, []byte(aerospaceConfig), 0o600); err != nil {
+			t.Fatalf("unable to write config: %v", err)
+		}
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+		return client, path
+	}
+
+	readConfig := func(t *testing.T, path string) string {
+		t.Helper()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unable to read config: %v", err)
+		}
+		return string(data)
+	}
+
+	t.Run("runs without AeroSpace", func(t *testing.T) {
+		_, path := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(nil), "hook", "install", "--config", path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(readConfig(t, path), "hook pull-window") {
+			t.Fatalf("expected the hook installed, got:\n%s", readConfig(t, path))
+		}
+		if _, err := testutils.CmdExecute(cmd.RootCmd(nil), "hook", "uninstall", "--config", path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if readConfig(t, path) != aerospaceConfig {
+			t.Fatalf("expected the config reverted, got:\n%s", readConfig(t, path))
+		}
+	})
+
+	t.Run("prints a diff with --dry-run", func(t *testing.T) {
+		client, path := setup(t)
+
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "install", "--config", path, "--dry-run")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out, "--- "+path) ||
+			!strings.Contains(out, `+exec-on-workspace-change = ['/bin/bash', '-c', 'aerospace-scratchpad hook pull-window`) {
+			t.Fatalf("expected a diff adding the hook, got:\n%s", out)
+		}
+		if config := readConfig(t, path); config != aerospaceConfig {
+			t.Fatalf("expected the config untouched, got:\n%s", config)
+		}
+	})
+
+	t.Run("installs with a backup and uninstalls", func(t *testing.T) {
+		client, path := setup(t)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "install", "--config", path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config := readConfig(t, path); !strings.Contains(config, "hook pull-window") {
+			t.Fatalf("expected the hook installed, got:\n%s", config)
+		}
+		backups, err := filepath.Glob(path + ".*.bak")
+		if err != nil || len(backups) != 1 || readConfig(t, backups[0]) != aerospaceConfig {
+			t.Fatalf("expected a backup of the config, got %v %v", backups, err)
+		}
+
+		if _, err = testutils.CmdExecute(cmd.RootCmd(client), "hook", "uninstall", "--config", path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config := readConfig(t, path); config != aerospaceConfig {
+			t.Fatalf("expected the original config, got:\n%s", config)
+		}
+		if backups, _ = filepath.Glob(path + ".*.bak"); len(backups) != 2 {
+			t.Fatalf("expected a backup for each change, got %v", backups)
+		}
+	})
+
+	t.Run("rejects unknown hooks", func(t *testing.T) {
+		client, path := setup(t)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "install", "--config", path, "--hooks", "nope")
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+}
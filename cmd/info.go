@@ -4,17 +4,62 @@ Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospacetoml"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
 )
 
+// reportTimeout bounds the queries of info and doctor, so a hung AeroSpace
+// is reported instead of hanging them.
+const reportTimeout = 2 * time.Second
+
+// infoReport is what info prints, in text or as JSON.
+type infoReport struct {
+	ClientVersion        string           `json:"client_version"`
+	ServerVersion        string           `json:"server_version"`
+	Socket               string           `json:"socket"`
+	Reachable            bool             `json:"reachable"`
+	Compatible           bool             `json:"compatible"`
+	Incompatibility      string           `json:"incompatibility,omitempty"`
+	ScratchpadWorkspaces []string         `json:"scratchpad_workspaces"`
+	Monitors             []infoMonitor    `json:"monitors"`
+	Config               string           `json:"config"`
+	Hooks                []infoHook       `json:"hooks"`
+	Capabilities         []infoCapability `json:"capabilities"`
+	Errors               []string         `json:"errors,omitempty"`
+}
+
+type infoMonitor struct {
+	MonitorID           int    `json:"monitor_id"`
+	MonitorName         string `json:"monitor_name"`
+	ScratchpadWorkspace string `json:"scratchpad_workspace"`
+}
+
+type infoHook struct {
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+}
+
+type infoCapability struct {
+	Name       string `json:"name"`
+	MinVersion string `json:"min_version"`
+	// Supported is nil when the AeroSpace version is unknown.
+	Supported *bool `json:"supported"`
+}
+
 // InfoCmd represents the info command.
 func InfoCmd(
-	aerospace aerospace.AeroSpaceWMClient,
+	aerospaceClient aerospace.AeroSpaceWMClient,
 ) *cobra.Command {
 	infoCmd := &cobra.Command{
 		Use:   "info",
@@ -22,48 +67,241 @@ func InfoCmd(
 		Long: `This command provides information about the aerospace-scratchpad and aerospace.
 
 Checks the compatibility of the installed version of Aerospace with the current version of aerospace-scratchpad.
-As well as other relevant information.
+As well as the scratchpad workspaces, the monitors, the hooks installed in the AeroSpace config
+and the capabilities of the running AeroSpace.
+
+Example:
+  aerospace-scratchpad info -o json | jq '.capabilities[] | select(.supported == false)'
 `,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			socketClient := aerospace.Connection()
-			var validationInfo string
-			if err := socketClient.CheckServerVersion(); err != nil {
-				validationInfo = "Incompatible. Reason: " + err.Error()
-			} else {
-				validationInfo = "Compatible."
+			outputFormat, err := reportOutputFormat(cmd)
+			if err != nil {
+				return err
 			}
 
-			socketPath, err := socketClient.GetSocketPath()
-			if err != nil && validationInfo == "" {
-				return fmt.Errorf("failed to get socket path: %w", err)
-			}
+			report := collectInfo(cmd, aerospaceClient)
 
-			serverVersion, err := socketClient.GetServerVersion()
-			if err != nil && validationInfo == "" {
-				return fmt.Errorf("failed to get server version: %w", err)
+			out := cmd.OutOrStdout()
+			if outputFormat == "json" {
+				err = json.NewEncoder(out).Encode(report)
+			} else {
+				err = printInfoReport(out, report)
 			}
+			if err != nil {
+				return fmt.Errorf("unable to print info: %w", err)
+			}
+			return nil
+		},
+	}
 
-			cmd.Println(fmt.Sprintf(`Aerospace Scratchpad
+	enableReportOutputFlag(infoCmd)
+	infoCmd.Flags().String(
+		hookConfigFlag, "",
+		"The AeroSpace config file (default ~/.aerospace.toml or ~/.config/aerospace/aerospace.toml)",
+	)
 
-[Aerospace]
-Version: %s
-Socket: %s
+	return infoCmd
+}
 
-[Aerospace scratchpad]
-Workspace: %s
+func collectInfo(cmd *cobra.Command, aerospaceClient aerospace.AeroSpaceWMClient) infoReport {
+	report := infoReport{ClientVersion: VERSION}
 
-[Compatibility]
-Status: %s
-			`,
-				serverVersion,
-				socketPath,
-				constants.DefaultScratchpadWorkspaceName,
-				validationInfo,
-			))
+	report.Reachable = reachesAeroSpace(aerospaceClient)
+	if report.Reachable {
+		collectAeroSpaceInfo(cmd, aerospaceClient, &report)
+	} else {
+		report.Errors = append(report.Errors, "socket: AeroSpace is not reachable")
+		report.ScratchpadWorkspaces = []string{constants.DefaultScratchpadWorkspaceName}
+		report.Capabilities = infoCapabilities(aerospace.Capabilities{})
+	}
 
-			return nil
-		},
+	report.Config, _ = cmd.Flags().GetString(hookConfigFlag)
+	if report.Config == "" {
+		report.Config = aerospacetoml.ConfigPath()
+	}
+	var err error
+	report.Hooks, err = installedHooks(expandHome(report.Config))
+	if err != nil {
+		report.Errors = append(report.Errors, "hooks: "+err.Error())
 	}
 
-	return infoCmd
+	return report
+}
+
+// reachesAeroSpace reports whether the client is connected to AeroSpace. A
+// client that failed to connect is nil, or panics on the first use.
+func reachesAeroSpace(aerospaceClient aerospace.AeroSpaceWMClient) (reached bool) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			reached = false
+		}
+	}()
+
+	if aerospaceClient == nil {
+		return false
+	}
+	if wrapper, ok := aerospaceClient.(*aerospace.AeroSpaceClient); ok &&
+		wrapper.GetUnderlyingClient() == nil {
+		return false
+	}
+	return aerospaceClient.Connection() != nil
+}
+
+func collectAeroSpaceInfo(
+	cmd *cobra.Command,
+	aerospaceClient aerospace.AeroSpaceWMClient,
+	report *infoReport,
+) {
+	socketPath, err := aerospaceClient.Connection().GetSocketPath()
+	if err != nil {
+		report.Errors = append(report.Errors, "socket: "+err.Error())
+	}
+	report.Socket = socketPath
+
+	capabilities := aerospace.CapabilitiesOf(aerospaceClient)
+	report.ServerVersion = capabilities.ServerVersion
+	report.Compatible = capabilities.Compatible == nil
+	if !report.Compatible {
+		report.Incompatibility = capabilities.Compatible.Error()
+	}
+	report.Capabilities = infoCapabilities(capabilities)
+
+	ctx, cancel := boundedContext(cmd, reportTimeout)
+	defer cancel()
+	wm := aerospace.WithContext(ctx, aerospaceClient)
+
+	report.ScratchpadWorkspaces, err = aerospace.ListScratchpadWorkspaceNames(wm)
+	if err != nil {
+		report.Errors = append(report.Errors, "scratchpad workspaces: "+err.Error())
+		report.ScratchpadWorkspaces = []string{constants.DefaultScratchpadWorkspaceName}
+	}
+
+	monitors, err := aerospace.ListMonitors(wm)
+	if err != nil {
+		report.Errors = append(report.Errors, "monitors: "+err.Error())
+	}
+	for _, monitor := range monitors {
+		workspace, resolveErr := aerospace.ResolveScratchpadWorkspaceNameForMonitor(wm, monitor.MonitorID)
+		if resolveErr != nil {
+			workspace = aerospace.ScratchpadWorkspaceNameForMonitor(monitor.MonitorID, len(monitors))
+		}
+		report.Monitors = append(report.Monitors, infoMonitor{
+			MonitorID:           monitor.MonitorID,
+			MonitorName:         monitor.MonitorName,
+			ScratchpadWorkspace: workspace,
+		})
+	}
+}
+
+// infoCapabilities lists the capabilities, unknown when the AeroSpace version
+// wasn't detected.
+func infoCapabilities(capabilities aerospace.Capabilities) []infoCapability {
+	rows := make([]infoCapability, 0, len(aerospace.CapabilityTable))
+	for _, requirement := range aerospace.CapabilityTable {
+		capability := infoCapability{
+			Name:       string(requirement.Capability),
+			MinVersion: requirement.MinVersion.String(),
+		}
+		if capabilities.Detected() {
+			supported := capabilities.Supports(requirement.Capability)
+			capability.Supported = &supported
+		}
+		rows = append(rows, capability)
+	}
+	return rows
+}
+
+// boundedContext returns the command context bounded by timeout.
+func boundedContext(cmd *cobra.Command, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// installedHooks reports which hooks are in the AeroSpace config, none when
+// there is no config.
+func installedHooks(configPath string) ([]infoHook, error) {
+	content := ""
+	data, err := os.ReadFile(configPath)
+	switch {
+	case err == nil:
+		content = string(data)
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	hooks := make([]infoHook, 0, len(aerospacetoml.Hooks))
+	for _, hook := range aerospacetoml.Hooks {
+		installed, hookErr := aerospacetoml.HasHook(content, hook)
+		if hookErr != nil {
+			return nil, hookErr
+		}
+		hooks = append(hooks, infoHook{Name: string(hook), Installed: installed})
+	}
+	return hooks, nil
+}
+
+func printInfoReport(out io.Writer, report infoReport) error {
+	var text strings.Builder
+	text.WriteString("Aerospace Scratchpad\n\n")
+
+	fmt.Fprintf(&text, "[Aerospace]\nVersion: %s\nSocket: %s\n\n", report.ServerVersion, report.Socket)
+
+	fmt.Fprintf(&text, "[Aerospace scratchpad]\nVersion: %s\n", report.ClientVersion)
+	fmt.Fprintf(&text, "Workspace: %s\n", strings.Join(report.ScratchpadWorkspaces, ", "))
+	text.WriteString("Monitors:\n")
+	for _, monitor := range report.Monitors {
+		fmt.Fprintf(&text, "  %d %s: %s\n", monitor.MonitorID, monitor.MonitorName, monitor.ScratchpadWorkspace)
+	}
+	fmt.Fprintf(&text, "Config: %s\n", report.Config)
+	text.WriteString("Hooks:\n")
+	for _, hook := range report.Hooks {
+		status := "not installed"
+		if hook.Installed {
+			status = "installed"
+		}
+		fmt.Fprintf(&text, "  %s: %s\n", hook.Name, status)
+	}
+
+	text.WriteString("\n[Compatibility]\n")
+	var missing []string
+	for _, capability := range report.Capabilities {
+		if capability.Supported != nil && !*capability.Supported {
+			missing = append(missing, capability.Name)
+		}
+	}
+	switch {
+	case !report.Reachable:
+		text.WriteString("Status: AeroSpace is not reachable.\n")
+	case report.Compatible && len(missing) > 0:
+		fmt.Fprintf(&text, "Status: Compatible, without %s.\n", strings.Join(missing, ", "))
+	case report.Compatible:
+		text.WriteString("Status: Compatible.\n")
+	default:
+		fmt.Fprintf(&text, "Status: Incompatible. Reason: %s\n", report.Incompatibility)
+	}
+	text.WriteString("Capabilities:\n")
+	for _, capability := range report.Capabilities {
+		status := "unknown"
+		if capability.Supported != nil {
+			status = "unsupported"
+			if *capability.Supported {
+				status = "supported"
+			}
+		}
+		fmt.Fprintf(&text, "  %s (>= %s): %s\n", capability.Name, capability.MinVersion, status)
+	}
+
+	if len(report.Errors) > 0 {
+		text.WriteString("\n[Errors]\n")
+		for _, reportErr := range report.Errors {
+			fmt.Fprintf(&text, "%s\n", reportErr)
+		}
+	}
+
+	_, err := io.WriteString(out, text.String())
+	return err
 }
@@ -2,22 +2,50 @@ package cmd_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"go.uber.org/mock/gomock"
 
+	aerospaceipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/focus"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/layout"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
 	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
 	client_mock "github.com/cristianoliveira/aerospace-scratchpad/internal/mocks/client"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
 )
 
+const multiMonitorWorld = `
+monitors:
+  - monitor-id: 1
+    monitor-name: Built-in Retina Display
+  - monitor-id: 2
+    monitor-name: DELL U2720Q
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+    monitor-id: 1
+  - workspace: .scratchpad.1
+    monitor-id: 1
+  - workspace: ws2
+    monitor-id: 2
+  - workspace: .scratchpad.2
+    monitor-id: 2
+windows:
+  - window-id: 1
+    app-name: Ghostty
+    workspace: ws1
+`
+
 type infoAeroSpaceClient struct {
 	conn client.AeroSpaceConnection
 }
@@ -43,6 +71,8 @@ func (m *infoAeroSpaceClient) Connection() client.AeroSpaceConnection {
 }
 
 func TestInfoCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
 	t.Run("reports compatibility information", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -61,7 +91,53 @@ func TestInfoCmd(t *testing.T) {
 			Return("0.4.0", nil).
 			Times(1)
 
-		args := []string{"info"}
+		socket.EXPECT().
+			SendCommand(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("mocked query failure")).
+			AnyTimes()
+
+		args := []string{"info", "--config", "missing-aerospace.toml"}
+		command := cmd.RootCmd(&infoAeroSpaceClient{conn: socket})
+		command.SetArgs(args)
+		output := &bytes.Buffer{}
+		command.SetOut(output)
+		command.SetErr(output)
+
+		err := command.Execute()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		cmdAsString := "aerospace-scratchpad " + strings.Join(args, " ")
+		// The client version changes with every release.
+		stdout := strings.ReplaceAll(output.String(), cmd.VERSION, "<version>")
+		testutils.MatchSnapshot(t, nil, cmdAsString, stdout, err)
+	})
+
+	t.Run("reports the capabilities of a recent version as supported", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		socket := client_mock.NewMockAeroSpaceConnection(ctrl)
+		socket.EXPECT().
+			CheckServerVersion().
+			Return(nil).
+			Times(1)
+		socket.EXPECT().
+			GetSocketPath().
+			Return("/tmp/aerospace.sock", nil).
+			Times(1)
+		socket.EXPECT().
+			GetServerVersion().
+			Return("0.19.2-Beta 1b5f3bd", nil).
+			Times(1)
+
+		socket.EXPECT().
+			SendCommand(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("mocked query failure")).
+			AnyTimes()
+
+		args := []string{"info", "--config", "missing-aerospace.toml"}
 		command := cmd.RootCmd(&infoAeroSpaceClient{conn: socket})
 		command.SetArgs(args)
 		output := &bytes.Buffer{}
@@ -74,7 +150,9 @@ func TestInfoCmd(t *testing.T) {
 		}
 
 		cmdAsString := "aerospace-scratchpad " + strings.Join(args, " ")
-		testutils.MatchSnapshot(t, nil, cmdAsString, output.String(), err)
+		// The client version changes with every release.
+		stdout := strings.ReplaceAll(output.String(), cmd.VERSION, "<version>")
+		testutils.MatchSnapshot(t, nil, cmdAsString, stdout, err)
 	})
 
 	t.Run("reports incompatibility when version check fails", func(t *testing.T) {
@@ -95,7 +173,12 @@ func TestInfoCmd(t *testing.T) {
 			Return("0.4.0", nil).
 			Times(1)
 
-		args := []string{"info"}
+		socket.EXPECT().
+			SendCommand(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("mocked query failure")).
+			AnyTimes()
+
+		args := []string{"info", "--config", "missing-aerospace.toml"}
 		command := cmd.RootCmd(&infoAeroSpaceClient{conn: socket})
 		command.SetArgs(args)
 		output := &bytes.Buffer{}
@@ -108,7 +191,9 @@ func TestInfoCmd(t *testing.T) {
 		}
 
 		cmdAsString := "aerospace-scratchpad " + strings.Join(args, " ")
-		testutils.MatchSnapshot(t, nil, cmdAsString, output.String(), err)
+		// The client version changes with every release.
+		stdout := strings.ReplaceAll(output.String(), cmd.VERSION, "<version>")
+		testutils.MatchSnapshot(t, nil, cmdAsString, stdout, err)
 	})
 
 	t.Run("still prints when compatibility fails but other calls error", func(t *testing.T) {
@@ -129,7 +214,12 @@ func TestInfoCmd(t *testing.T) {
 			Return("", errors.New("mocked server version failure")).
 			Times(1)
 
-		args := []string{"info"}
+		socket.EXPECT().
+			SendCommand(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("mocked query failure")).
+			AnyTimes()
+
+		args := []string{"info", "--config", "missing-aerospace.toml"}
 		command := cmd.RootCmd(&infoAeroSpaceClient{conn: socket})
 		command.SetArgs(args)
 		output := &bytes.Buffer{}
@@ -142,6 +232,86 @@ func TestInfoCmd(t *testing.T) {
 		}
 
 		cmdAsString := "aerospace-scratchpad " + strings.Join(args, " ")
-		testutils.MatchSnapshot(t, nil, cmdAsString, output.String(), err)
+		// The client version changes with every release.
+		stdout := strings.ReplaceAll(output.String(), cmd.VERSION, "<version>")
+		testutils.MatchSnapshot(t, nil, cmdAsString, stdout, err)
+	})
+
+	t.Run("reports AeroSpace not reachable instead of crashing", func(t *testing.T) {
+		for name, aerospaceClient := range map[string]aerospace.AeroSpaceWMClient{
+			"no client":    nil,
+			"nil client":   (*aerospaceipc.AeroSpaceWM)(nil),
+			"unset client": &aerospaceipc.AeroSpaceWM{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				out, err := testutils.CmdExecute(
+					cmd.RootCmd(aerospaceClient),
+					"info", "--config", "missing-aerospace.toml",
+				)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !strings.Contains(out, "Status: AeroSpace is not reachable.") ||
+					!strings.Contains(out, "focus-follows-window (>= 0.15.0): unknown") {
+					t.Fatalf("expected AeroSpace reported not reachable, got:\n%s", out)
+				}
+			})
+		}
+	})
+
+	t.Run("reports the scratchpad, monitors, hooks and capabilities as JSON", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, multiMonitorWorld)
+		configPath := filepath.Join(t.TempDir(), "aerospace.toml")
+		config := `exec-on-workspace-change = ['/bin/bash', '-c', 'aerospace-scratchpad hook pull-window "$AEROSPACE_PREV_WORKSPACE" "$AEROSPACE_FOCUSED_WORKSPACE"']` + "\n"
+		if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+			t.Fatalf("unable to write config: %v", err)
+		}
+
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "info", "--config", configPath, "-o", "json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var report struct {
+			ServerVersion        string   `json:"server_version"`
+			Compatible           bool     `json:"compatible"`
+			ScratchpadWorkspaces []string `json:"scratchpad_workspaces"`
+			Monitors             []struct {
+				MonitorID           int    `json:"monitor_id"`
+				ScratchpadWorkspace string `json:"scratchpad_workspace"`
+			} `json:"monitors"`
+			Hooks []struct {
+				Name      string `json:"name"`
+				Installed bool   `json:"installed"`
+			} `json:"hooks"`
+			Capabilities []struct {
+				Name      string `json:"name"`
+				Supported *bool  `json:"supported"`
+			} `json:"capabilities"`
+			Errors []string `json:"errors"`
+		}
+		if err = json.Unmarshal([]byte(out), &report); err != nil {
+			t.Fatalf("expected a JSON report, got %q: %v", out, err)
+		}
+
+		if !report.Compatible || !strings.HasPrefix(report.ServerVersion, "0.21.0") || len(report.Errors) > 0 {
+			t.Fatalf("expected a compatible AeroSpace, got %+v", report)
+		}
+		if strings.Join(report.ScratchpadWorkspaces, ",") != ".scratchpad.1,.scratchpad.2" {
+			t.Fatalf("unexpected scratchpad workspaces %v", report.ScratchpadWorkspaces)
+		}
+		if len(report.Monitors) != 2 || report.Monitors[1].ScratchpadWorkspace != ".scratchpad.2" {
+			t.Fatalf("unexpected monitors %+v", report.Monitors)
+		}
+		for _, hook := range report.Hooks {
+			if hook.Installed != (hook.Name == "pull-window") {
+				t.Fatalf("expected only pull-window installed, got %+v", report.Hooks)
+			}
+		}
+		for _, capability := range report.Capabilities {
+			if capability.Supported == nil || !*capability.Supported {
+				t.Fatalf("expected %s supported, got %+v", capability.Name, report.Capabilities)
+			}
+		}
 	})
 }
@@ -4,11 +4,13 @@ Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -51,6 +53,109 @@ func parseMonitorFlag(cmd *cobra.Command) (int, error) {
 	}
 }
 
+const (
+	listSortFlag    = "sort"
+	listColumnsFlag = "columns"
+	listStateFlag   = "state"
+
+	listColumnID        = "id"
+	listColumnApp       = "app"
+	listColumnTitle     = "title"
+	listColumnWorkspace = "workspace"
+	listColumnMonitor   = "monitor"
+	listColumnLayout    = "layout"
+	listColumnState     = "state"
+
+	windowStateHidden  = "hidden"
+	windowStateVisible = "visible"
+	windowStateFocused = "focused"
+)
+
+// listSortKeys are the --sort values, listColumns the --columns ones.
+func listSortKeys() []string {
+	return []string{
+		listColumnID,
+		listColumnApp,
+		listColumnTitle,
+		listColumnWorkspace,
+		listColumnMonitor,
+	}
+}
+
+func listColumns() []string {
+	return []string{
+		listColumnID,
+		listColumnApp,
+		listColumnTitle,
+		listColumnWorkspace,
+		listColumnMonitor,
+		listColumnLayout,
+		listColumnState,
+	}
+}
+
+// listWindow is a scratchpad window with what list knows beyond AeroSpace's
+// window fields.
+type listWindow struct {
+	windowsipc.Window
+	// MonitorID is -1 when unknown, it is only looked up when needed.
+	MonitorID int
+	// State is hidden, visible or focused, only looked up when needed.
+	State string
+}
+
+// listOptions are the flags of list shaping its output.
+type listOptions struct {
+	Sort    string
+	Columns []string
+	State   string
+}
+
+func (o listOptions) needsMonitor() bool {
+	return o.Sort == listColumnMonitor || slices.Contains(o.Columns, listColumnMonitor)
+}
+
+func (o listOptions) needsState() bool {
+	return o.State != "" || slices.Contains(o.Columns, listColumnState)
+}
+
+func parseListOptions(cmd *cobra.Command) (listOptions, error) {
+	sortKey, _ := cmd.Flags().GetString(listSortFlag)
+	columns, _ := cmd.Flags().GetStringSlice(listColumnsFlag)
+	windowState, _ := cmd.Flags().GetString(listStateFlag)
+
+	options := listOptions{
+		Sort:  strings.ToLower(strings.TrimSpace(sortKey)),
+		State: strings.ToLower(strings.TrimSpace(windowState)),
+	}
+	if !slices.Contains(listSortKeys(), options.Sort) {
+		return options, fmt.Errorf(
+			"invalid --sort value: %s, expected one of %s",
+			sortKey, strings.Join(listSortKeys(), "|"),
+		)
+	}
+	for _, column := range columns {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !slices.Contains(listColumns(), column) {
+			return options, fmt.Errorf(
+				"invalid --columns value: %s, expected any of %s",
+				column, strings.Join(listColumns(), ","),
+			)
+		}
+		options.Columns = append(options.Columns, column)
+	}
+	switch options.State {
+	case "", windowStateHidden, windowStateVisible, windowStateFocused:
+	default:
+		return options, fmt.Errorf(
+			"invalid --state value: %s, expected hidden, visible or focused",
+			windowState,
+		)
+	}
+
+	return options, nil
+}
+
 // ListCmd represents the list command.
 func ListCmd(aerospaceClient *aerospace.AeroSpaceClient) *cobra.Command {
 	command := &cobra.Command{
@@ -61,15 +166,47 @@ func ListCmd(aerospaceClient *aerospace.AeroSpaceClient) *cobra.Command {
 
 A scratchpad window is defined as:
 - A window in a scratchpad workspace (.scratchpad or .scratchpad.<monitor-id>), OR
-- A floating window (WindowLayout == "floating")
+- A floating window (WindowLayout == "floating"), see --scope for other definitions
 
 The output is scriptable and supports multiple formats (text, json, tsv, csv).
+
+Each window is in one of these states:
+  hidden   in a scratchpad workspace, or a workspace no monitor shows
+  visible  in a workspace shown on a monitor, e.g. a floating window
+  focused  visible and focused
+
+--state filters on it, "visible" including the focused window. --columns picks
+what is printed for each window instead of the default event, e.g.
+--columns id,app,state.
 `,
 		Run: func(cmd *cobra.Command, args []string) {
 			runListCommand(cmd, args, aerospaceClient)
 		},
 	}
 
+	command.Flags().String(
+		listSortFlag, listColumnApp,
+		"Sort the windows by: "+strings.Join(listSortKeys(), "|"),
+	)
+	_ = command.RegisterFlagCompletionFunc(listSortFlag, cobra.FixedCompletions(
+		listSortKeys(), cobra.ShellCompDirectiveNoFileComp,
+	))
+	command.Flags().StringSlice(
+		listColumnsFlag, nil,
+		"Print these columns for each window: "+strings.Join(listColumns(), ","),
+	)
+	_ = command.RegisterFlagCompletionFunc(listColumnsFlag, cobra.FixedCompletions(
+		listColumns(), cobra.ShellCompDirectiveNoFileComp,
+	))
+	command.Flags().String(
+		listStateFlag, "",
+		"Only list the windows in this state: hidden|visible|focused",
+	)
+	_ = command.RegisterFlagCompletionFunc(listStateFlag, cobra.FixedCompletions(
+		[]string{windowStateHidden, windowStateVisible, windowStateFocused},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+
 	return command
 }
 
@@ -96,8 +233,19 @@ func runListCommand(cmd *cobra.Command, args []string, aerospaceClient *aerospac
 		return
 	}
 
-	querier := aerospace.NewAerospaceQuerier(aerospaceClient.GetUnderlyingClient())
-	scratchpadWindows, err := querier.GetScratchpadWindowsForMonitor(monitorID)
+	options, err := parseListOptions(cmd)
+	if err != nil {
+		stderr.Printf("Error: %v\n", err)
+		return
+	}
+
+	querier, err := newScratchpadQuerier(cmd, aerospaceClient)
+	if err != nil {
+		logger.LogError("LIST: invalid scratchpad scope", "error", err)
+		stderr.Printf("Error: %v\n", err)
+		return
+	}
+	scratchpadWindows, err := querier.GetScratchpadWindowsForMonitor(cmd.Context(), monitorID)
 	if err != nil {
 		logger.LogError("LIST: unable to get scratchpad windows", "error", err)
 		stderr.Printf("Error: %v\n", err)
@@ -107,8 +255,172 @@ func runListCommand(cmd *cobra.Command, args []string, aerospaceClient *aerospac
 	logger.LogDebug("LIST: retrieved scratchpad windows", "count", len(scratchpadWindows))
 
 	filteredWindows := applyFiltersToList(scratchpadWindows, filterFlags)
-	sortWindowsByAppName(filteredWindows)
-	outputWindows(formatter, filteredWindows)
+	windows, err := describeListWindows(cmd.Context(), aerospaceClient, filteredWindows, options)
+	if err != nil {
+		logger.LogError("LIST: unable to describe windows", "error", err)
+		stderr.Printf("Error: %v\n", err)
+		return
+	}
+	windows = filterListState(windows, options.State)
+	sortListWindows(windows, options.Sort)
+
+	if len(options.Columns) > 0 {
+		outputListColumns(formatter, windows, options.Columns)
+		return
+	}
+	plain := make([]windowsipc.Window, 0, len(windows))
+	for _, window := range windows {
+		plain = append(plain, window.Window)
+	}
+	outputWindows(formatter, plain)
+}
+
+// describeListWindows adds the monitor and state to the windows, when the
+// options use them.
+func describeListWindows(
+	ctx context.Context,
+	aerospaceClient *aerospace.AeroSpaceClient,
+	windows []windowsipc.Window,
+	options listOptions,
+) ([]listWindow, error) {
+	wm := aerospace.WithContext(ctx, aerospaceClient)
+
+	monitorByWorkspace := map[string]int{}
+	if options.needsMonitor() {
+		workspaces, err := aerospace.ListWorkspacesWithMonitors(wm)
+		if err != nil {
+			return nil, err
+		}
+		for _, workspace := range workspaces {
+			monitorByWorkspace[workspace.Workspace] = workspace.MonitorID
+		}
+	}
+
+	focusedWindowID := 0
+	var visibleWorkspaces []string
+	if options.needsState() {
+		// No window may be focused, e.g. on an empty workspace.
+		if focusedWindow, err := wm.Windows().GetFocusedWindow(); err == nil {
+			focusedWindowID = focusedWindow.WindowID
+		}
+		var err error
+		if visibleWorkspaces, err = aerospace.ListVisibleWorkspaces(wm); err != nil {
+			return nil, err
+		}
+	}
+
+	described := make([]listWindow, 0, len(windows))
+	for _, window := range windows {
+		monitorID, ok := monitorByWorkspace[window.Workspace]
+		if !ok {
+			monitorID = -1
+		}
+		described = append(described, listWindow{
+			Window:    window,
+			MonitorID: monitorID,
+			State:     windowState(window, focusedWindowID, visibleWorkspaces),
+		})
+	}
+	return described, nil
+}
+
+// windowState returns hidden for the windows in a scratchpad workspace or a
+// workspace no monitor shows.
+func windowState(
+	window windowsipc.Window,
+	focusedWindowID int,
+	visibleWorkspaces []string,
+) string {
+	switch {
+	case aerospace.IsScratchpadWorkspace(window.Workspace),
+		!slices.Contains(visibleWorkspaces, window.Workspace):
+		return windowStateHidden
+	case window.WindowID == focusedWindowID:
+		return windowStateFocused
+	default:
+		return windowStateVisible
+	}
+}
+
+// filterListState keeps the windows in state, the focused one being visible
+// too. An empty state keeps them all.
+func filterListState(windows []listWindow, state string) []listWindow {
+	if state == "" {
+		return windows
+	}
+
+	var kept []listWindow
+	for _, window := range windows {
+		if window.State == state ||
+			(state == windowStateVisible && window.State == windowStateFocused) {
+			kept = append(kept, window)
+		}
+	}
+	return kept
+}
+
+func sortListWindows(windows []listWindow, key string) {
+	sort.SliceStable(windows, func(i, j int) bool {
+		left, right := windows[i], windows[j]
+		switch key {
+		case listColumnTitle:
+			if left.WindowTitle != right.WindowTitle {
+				return left.WindowTitle < right.WindowTitle
+			}
+		case listColumnWorkspace:
+			if left.Workspace != right.Workspace {
+				return left.Workspace < right.Workspace
+			}
+		case listColumnMonitor:
+			if left.MonitorID != right.MonitorID {
+				return left.MonitorID < right.MonitorID
+			}
+		case listColumnApp:
+			if left.AppName != right.AppName {
+				return left.AppName < right.AppName
+			}
+		}
+		// Sort by window ID for stable ordering
+		return left.WindowID < right.WindowID
+	})
+}
+
+func listColumnValue(window listWindow, column string) any {
+	switch column {
+	case listColumnID:
+		return window.WindowID
+	case listColumnApp:
+		return window.AppName
+	case listColumnTitle:
+		return window.WindowTitle
+	case listColumnWorkspace:
+		return window.Workspace
+	case listColumnMonitor:
+		if window.MonitorID < 0 {
+			return nil
+		}
+		return window.MonitorID
+	case listColumnLayout:
+		return window.WindowLayout
+	case listColumnState:
+		return window.State
+	default:
+		return nil
+	}
+}
+
+func outputListColumns(formatter *cli.OutputFormatter, windows []listWindow, columns []string) {
+	logger := logger.GetDefaultLogger()
+
+	for _, window := range windows {
+		values := make([]any, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, listColumnValue(window, column))
+		}
+		if printErr := formatter.PrintColumns(columns, values); printErr != nil {
+			logger.LogError("LIST: unable to write output", "error", printErr)
+		}
+	}
 }
 
 func getOutputFormatter(cmd *cobra.Command) (*cli.OutputFormatter, error) {
@@ -120,7 +432,7 @@ func getOutputFormatter(cmd *cobra.Command) (*cli.OutputFormatter, error) {
 		return nil, err
 	}
 
-	formatter, err := cli.NewOutputFormatter(os.Stdout, outputFormat)
+	formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
 	if err != nil {
 		logger.LogError("LIST: invalid output format", "error", err)
 		stderr.Println("Error: unsupported output format")
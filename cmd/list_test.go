@@ -2,6 +2,8 @@ package cmd_test
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -482,3 +484,308 @@ func TestListCmd(t *testing.T) { //nolint:gocognit
 		},
 	)
 }
+
+const listStateWorld = `
+focused-workspace: ws1
+monitors:
+  - monitor-id: 1
+  - monitor-id: 2
+workspaces:
+  - workspace: ws1
+    focused-window-id: 3
+    monitor-id: 2
+  - workspace: ws2
+    monitor-id: 1
+  - workspace: .scratchpad
+    monitor-id: 1
+windows:
+  - window-id: 1
+    window-title: Terminal
+    app-name: Ghostty
+    workspace: ws1
+  - window-id: 2
+    window-title: Files
+    app-name: Finder
+    workspace: .scratchpad
+    window-layout: floating
+  - window-id: 3
+    window-title: Scratch
+    app-name: Notes
+    workspace: ws1
+    window-layout: floating
+  - window-id: 4
+    window-title: Accounts
+    app-name: Calculator
+    workspace: ws2
+    window-layout: floating
+`
+
+func TestListColumns(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name: "prints the state column",
+			args: []string{"list", "--columns", "id,app,state"},
+			expected: "id=4 app=Calculator state=visible\n" +
+				"id=2 app=Finder state=hidden\n" +
+				"id=3 app=Notes state=focused\n",
+		},
+		{
+			name:     "filters the hidden windows",
+			args:     []string{"list", "--columns", "id", "--state", "hidden"},
+			expected: "id=2\n",
+		},
+		{
+			name:     "filters the visible windows, the focused one included",
+			args:     []string{"list", "--columns", "id", "--state", "visible", "--sort", "id"},
+			expected: "id=3\nid=4\n",
+		},
+		{
+			name:     "sorts by title",
+			args:     []string{"list", "--columns", "title", "--sort", "title"},
+			expected: "title=Accounts\ntitle=Files\ntitle=Scratch\n",
+		},
+		{
+			name:     "sorts by monitor",
+			args:     []string{"list", "--columns", "id,monitor,workspace", "--sort", "monitor"},
+			expected: "id=2 monitor=1 workspace=.scratchpad\nid=4 monitor=1 workspace=ws2\nid=3 monitor=2 workspace=ws1\n",
+		},
+		{
+			name:     "prints the columns as json",
+			args:     []string{"list", "--columns", "id,layout", "--state", "focused", "-o", "json"},
+			expected: `{"id":3,"layout":"floating"}` + "\n",
+		},
+		{
+			name:     "keeps the default output with a state filter",
+			args:     []string{"list", "--state", "hidden"},
+			expected: "command=list action=list window_id=2 app_name=Finder workspace=.scratchpad target_workspace=\"\" result=ok message=\"\"\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := testutils.StartFakeAeroSpace(t, listStateWorld)
+
+			args := append([]string{}, tc.args...)
+			args = append(args, "--monitor", "all")
+			out, err := testutils.CmdExecute(cmd.RootCmd(client), args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out != tc.expected {
+				t.Fatalf("output mismatch:\nwant: %q\ngot:  %q", tc.expected, out)
+			}
+		})
+	}
+
+	t.Run("hides the windows of a workspace no monitor shows", func(t *testing.T) {
+		// ws3 shares the second monitor with the focused ws1.
+		world := listStateWorld + `  - window-id: 5
+    window-title: Picture
+    app-name: Preview
+    workspace: ws3
+    window-layout: floating
+`
+		client, _ := testutils.StartFakeAeroSpace(t, world)
+
+		out, err := testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"list", "--columns", "id,state", "--sort", "id", "--monitor", "all",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "id=2 state=hidden\nid=3 state=focused\nid=4 state=visible\nid=5 state=hidden\n"
+		if out != expected {
+			t.Fatalf("output mismatch:\nwant: %q\ngot:  %q", expected, out)
+		}
+	})
+
+	for _, args := range [][]string{
+		{"list", "--sort", "size"},
+		{"list", "--columns", "id,size"},
+		{"list", "--state", "minimized"},
+	} {
+		t.Run("rejects "+strings.Join(args[1:], " "), func(t *testing.T) {
+			client, _ := testutils.StartFakeAeroSpace(t, listStateWorld)
+
+			_, err := testutils.CmdExecute(cmd.RootCmd(client), args...)
+			if err == nil || !strings.Contains(err.Error(), "invalid --") {
+				t.Fatalf("expected an invalid flag error, got %v", err)
+			}
+		})
+	}
+}
+
+const listScopeWorld = `
+focused-workspace: ws1
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+windows:
+  - window-id: 1
+    window-title: Terminal
+    app-name: Ghostty
+    workspace: ws1
+  - window-id: 2
+    window-title: Files
+    app-name: Finder
+    workspace: .scratchpad
+    window-layout: floating
+  - window-id: 3
+    window-title: Picture-in-Picture
+    app-name: Firefox
+    workspace: ws1
+    window-layout: floating
+  - window-id: 4
+    window-title: Scratch
+    app-name: Notes
+    workspace: ws1
+    window-layout: floating
+  - window-id: 5
+    window-title: Save As
+    app-name: Preview
+    workspace: ws1
+    window-layout: floating
+`
+
+func TestListScope(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	// Notes goes through the scratchpad, so strict counts it once shown.
+	setup := func(t *testing.T, configContent string) aerospace.AeroSpaceWMClient {
+		t.Helper()
+
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+			t.Fatalf("unable to write config: %v", err)
+		}
+		t.Setenv(constants.EnvAeroSpaceScratchpadConfig, configPath)
+
+		client, _ := testutils.StartFakeAeroSpace(t, listScopeWorld)
+		for _, args := range [][]string{{"move", "Notes"}, {"summon", "Notes"}} {
+			if _, err := testutils.CmdExecute(cmd.RootCmd(client), args...); err != nil {
+				t.Fatalf("unexpected error running %v: %v", args, err)
+			}
+		}
+		return client
+	}
+	listIDs := func(t *testing.T, client aerospace.AeroSpaceWMClient, args ...string) string {
+		t.Helper()
+
+		args = append([]string{"list", "--columns", "id", "--sort", "id", "--monitor", "all"}, args...)
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), args...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return strings.Join(strings.Fields(out), " ")
+	}
+
+	excludePiP := `
+scratchpad-windows:
+  exclude:
+    - filter: ["window-title=^Picture-in-Picture$"]
+`
+
+	tests := []struct {
+		name     string
+		config   string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "floating is the default",
+			expected: "id=2 id=3 id=4 id=5",
+		},
+		{
+			name:     "strict only counts the windows moved to the scratchpad",
+			args:     []string{"--scope", "strict"},
+			expected: "id=2 id=4",
+		},
+		{
+			name:     "all counts every window",
+			args:     []string{"--scope", "all"},
+			expected: "id=1 id=2 id=3 id=4 id=5",
+		},
+		{
+			name:     "the config sets the default scope",
+			config:   "scratchpad-windows:\n  scope: strict\n",
+			expected: "id=2 id=4",
+		},
+		{
+			name:     "the flag overrides the config scope",
+			config:   "scratchpad-windows:\n  scope: strict\n",
+			args:     []string{"--scope", "floating"},
+			expected: "id=2 id=3 id=4 id=5",
+		},
+		{
+			name:     "excluded windows never count",
+			config:   excludePiP,
+			args:     []string{"--scope", "all"},
+			expected: "id=1 id=2 id=4 id=5",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := setup(t, tc.config)
+
+			if got := listIDs(t, client, tc.args...); got != tc.expected {
+				t.Fatalf("windows mismatch:\nwant: %s\ngot:  %s", tc.expected, got)
+			}
+		})
+	}
+
+	t.Run("move --all-floating keeps the excluded windows", func(t *testing.T) {
+		client := setup(t, excludePiP)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "move", "--all-floating"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := listIDs(t, client, "--state", "visible", "--scope", "all"); got != "id=1" {
+			t.Fatalf("expected only Ghostty visible, got %s", got)
+		}
+	})
+
+	t.Run("move --all-floating in strict scope", func(t *testing.T) {
+		client := setup(t, "")
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client), "move", "--all-floating", "--scope", "strict",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := listIDs(t, client, "--state", "hidden"); got != "id=2 id=4" {
+			t.Fatalf("expected only Finder and Notes hidden, got %s", got)
+		}
+	})
+
+	t.Run("strict keeps counting the windows once undone", func(t *testing.T) {
+		client := setup(t, "")
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "undo", "2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := listIDs(t, client, "--scope", "strict"); got != "id=2 id=4" {
+			t.Fatalf("expected Finder and Notes, got %s", got)
+		}
+	})
+
+	t.Run("rejects an unknown scope", func(t *testing.T) {
+		client := setup(t, "")
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "list", "--scope", "tiling")
+		if err == nil || !strings.Contains(err.Error(), "invalid scope") {
+			t.Fatalf("expected an invalid scope error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package cmd_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+)
+
+// TestMain keeps the state written by the commands, e.g. the journal or the
+// moving marker, away from the user's directories.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "aerospace-scratchpad-state")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv(constants.EnvAeroSpaceScratchpadStateDir, dir)
+	_ = os.Setenv("XDG_RUNTIME_DIR", dir)
+	// The test binary must not be started as the hide timer.
+	_ = os.Setenv("AEROSPACE_SCRATCHPAD_NO_HIDE_TIMER", "1")
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -4,9 +4,9 @@ Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -33,6 +33,7 @@ If no pattern is provided, it moves the currently focused window.
 To move all windows that match the focused window's app name to the scratchpad, use the --all-matching flag.
 To move all floating windows (scratchpad windows) to the scratchpad, use the --all-floating flag.
 `,
+		ValidArgsFunction: completeAppNames(aerospaceClient),
 		Run: func(cmd *cobra.Command, args []string) {
 			logger := logger.GetDefaultLogger()
 			logger.LogDebug("MOVE: start command", "args", args)
@@ -44,7 +45,7 @@ To move all floating windows (scratchpad windows) to the scratchpad, use the --a
 				return
 			}
 
-			formatter, err := cli.NewOutputFormatter(os.Stdout, outputFormat)
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
 			if err != nil {
 				logger.LogError("MOVE: invalid output format", "error", err)
 				stderr.Println("Error: unsupported output format")
@@ -69,6 +70,7 @@ To move all floating windows (scratchpad windows) to the scratchpad, use the --a
 			// Skip pattern logic when --all-floating is used
 			if !allFloatingFlag {
 				windowNamePattern, focusedWindowID, err = getWindowPattern(
+					cmd.Context(),
 					args,
 					aerospaceClient,
 					logger,
@@ -103,8 +105,13 @@ To move all floating windows (scratchpad windows) to the scratchpad, use the --a
 			}
 
 			// Query windows matching pattern and filters
-			querier := aerospace.NewAerospaceQuerier(aerospaceClient.GetUnderlyingClient())
-			mover := aerospace.NewAeroSpaceMover(aerospaceClient)
+			querier, err := newScratchpadQuerier(cmd, aerospaceClient)
+			if err != nil {
+				logger.LogError("MOVE: invalid scratchpad scope", "error", err)
+				stderr.Println("Error: %v", err)
+				return
+			}
+			mover := newMover(cmd, aerospaceClient)
 
 			// Get the current monitor ID before any focus changes
 			currentMonitorID := 0
@@ -129,7 +136,7 @@ To move all floating windows (scratchpad windows) to the scratchpad, use the --a
 			if allFloatingFlag {
 				// Get all floating windows when --all-floating is set
 				logger.LogDebug("MOVE: using --all-floating flag, getting all floating windows")
-				windows, err = querier.GetAllFloatingWindows()
+				windows, err = querier.GetAllFloatingWindows(cmd.Context())
 				if err != nil {
 					logger.LogError(
 						"MOVE: error retrieving floating windows",
@@ -141,6 +148,7 @@ To move all floating windows (scratchpad windows) to the scratchpad, use the --a
 			} else {
 				// Normal pattern-based filtering
 				windows, err = querier.GetFilteredWindows(
+					cmd.Context(),
 					windowNamePattern,
 					filterFlags,
 				)
@@ -189,7 +197,9 @@ To move all floating windows (scratchpad windows) to the scratchpad, use the --a
 					continue
 				}
 
+				markFocusing(cmd, window.WindowID)
 				targetWorkspace, moveErr := mover.MoveWindowToScratchpadForMonitor(
+					cmd.Context(),
 					window, currentMonitorID,
 				)
 				if moveErr != nil {
@@ -251,6 +261,7 @@ To move all floating windows (scratchpad windows) to the scratchpad, use the --a
 // getWindowPattern determines the window pattern and focused window ID from args.
 // Returns pattern, focusedWindowID, and error.
 func getWindowPattern(
+	ctx context.Context,
 	args []string,
 	aerospaceClient *aerospace.AeroSpaceClient,
 	log logger.Logger,
@@ -265,7 +276,7 @@ func getWindowPattern(
 	}
 
 	if windowNamePattern == "" {
-		focusedWindow, err := aerospaceClient.GetFocusedWindow()
+		focusedWindow, err := aerospaceClient.GetFocusedWindow(ctx)
 		log.LogDebug(
 			"MOVE: retrieving focused window",
 			"focusedWindow", focusedWindow,
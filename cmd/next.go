@@ -4,10 +4,9 @@ Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
 package cmd
 
 import (
-	"os"
-
 	"github.com/spf13/cobra"
 
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/cli"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
@@ -22,6 +21,11 @@ func NextCmd(aerospaceClient *aerospace.AeroSpaceClient) *cobra.Command {
 
 This command cycles through the scratchpad windows, displaying them in the current workspace.
 It does not send the windows back to the scratchpad, but rather focuses the next available scratchpad window.
+
+With --workspace, the window goes to that workspace instead, "prev" being the
+workspace focused before the current one. It is focused only with --focus.
+
+With --focus-policy keep, the window is shown without the focus.
 		`,
 		Run: func(cmd *cobra.Command, args []string) {
 			outputFormat, err := cmd.Flags().GetString("output")
@@ -29,7 +33,7 @@ It does not send the windows back to the scratchpad, but rather focuses the next
 				stderr.Println("Error: unable to get output format")
 				return
 			}
-			formatter, err := cli.NewOutputFormatter(os.Stdout, outputFormat)
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
 			if err != nil {
 				stderr.Println("Error: unsupported output format")
 				return
@@ -41,7 +45,7 @@ It does not send the windows back to the scratchpad, but rather focuses the next
 				return
 			}
 
-			focusedWorkspace, err := aerospaceClient.GetFocusedWorkspace()
+			focusedWorkspace, err := aerospaceClient.GetFocusedWorkspace(cmd.Context())
 			if err != nil {
 				stderr.Println(
 					"Error: unable to get focused workspace\n%s",
@@ -50,20 +54,32 @@ It does not send the windows back to the scratchpad, but rather focuses the next
 				return
 			}
 
-			querier := aerospace.NewAerospaceQuerier(aerospaceClient.GetUnderlyingClient())
-			mover := aerospace.NewAeroSpaceMover(aerospaceClient)
+			target, err := resolveTargetWorkspace(cmd, focusedWorkspace)
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return
+			}
 
-			window, err := querier.GetNextScratchpadWindowForMonitor(monitorID)
+			querier, err := newScratchpadQuerier(cmd, aerospaceClient)
 			if err != nil {
 				stderr.Println("Error: %v", err)
 				return
 			}
+			mover := newMover(cmd, aerospaceClient)
 
-			setFocus := true
+			window, err := querier.GetNextScratchpadWindowForMonitor(cmd.Context(), monitorID)
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return
+			}
+
+			rememberFocus(cmd, aerospaceClient, target, []windowsipc.Window{*window})
+			markFocusing(cmd, window.WindowID)
 			if moveErr := mover.MoveWindowToWorkspace(
+				cmd.Context(),
 				window,
-				focusedWorkspace,
-				setFocus,
+				target.Workspace,
+				target.Focus,
 			); moveErr != nil {
 				stderr.Println("Error: %v", moveErr)
 				return
@@ -75,7 +91,7 @@ It does not send the windows back to the scratchpad, but rather focuses the next
 				WindowID:        window.WindowID,
 				AppName:         window.AppName,
 				Workspace:       window.Workspace,
-				TargetWorkspace: focusedWorkspace.Workspace,
+				TargetWorkspace: target.Workspace.Workspace,
 				Result:          "ok",
 			}); printErr != nil {
 				stderr.Println("Error: %v", printErr)
@@ -83,5 +99,7 @@ It does not send the windows back to the scratchpad, but rather focuses the next
 		},
 	}
 
+	enableTargetFlags(nextCmd, aerospaceClient)
+
 	return nextCmd
 }
@@ -83,6 +83,10 @@ func TestNextCmd(t *testing.T) {
 				GetAllWindowsByWorkspace(constants.DefaultScratchpadWorkspaceName).
 				Return(scratchpadWindows.Windows, nil).
 				Times(1),
+			aerospaceClient.GetWindowsMock().EXPECT().
+				GetFocusedWindow().
+				Return(testutils.ExtractFocusedWindow(tree), nil).
+				Times(1),
 			aerospaceClient.GetWorkspacesMock().EXPECT().
 				MoveWindowToWorkspaceWithOpts(
 					workspaces.MoveWindowToWorkspaceArgs{
@@ -224,6 +228,10 @@ func TestNextCmd(t *testing.T) {
 					GetAllWindowsByWorkspace(constants.DefaultScratchpadWorkspaceName).
 					Return(scratchpadWindows, nil).
 					Times(1),
+				aerospaceClient.GetWindowsMock().EXPECT().
+					GetFocusedWindow().
+					Return(nil, errors.New("no window is focused")).
+					Times(1),
 				aerospaceClient.GetWorkspacesMock().EXPECT().
 					MoveWindowToWorkspaceWithOpts(
 						workspaces.MoveWindowToWorkspaceArgs{
@@ -1,12 +1,27 @@
 package cmd
 
 const (
-	commandList   = "list"
-	commandMove   = "move"
-	commandNext   = "next"
-	commandShow   = "show"
-	commandSummon = "summon"
+	commandBatch   = "batch"
+	commandHistory = "history"
+	commandList    = "list"
+	commandMove    = "move"
+	commandNext    = "next"
+	commandPin     = "pin"
+	commandShow    = "show"
+	commandStatus  = "status"
+	commandSummon  = "summon"
+	commandSwap    = "swap"
+	commandUndo    = "undo"
+	commandUnpin   = "unpin"
+	commandWatch   = "watch"
 
 	actionToWorkspace  = "to-workspace"
 	actionToScratchpad = "to-scratchpad"
+
+	actionAdded   = "added"
+	actionRemoved = "removed"
+	actionShown   = "shown"
+	actionHidden  = "hidden"
+	actionMoved   = "moved"
+	actionFocused = "focused"
 )
@@ -0,0 +1,184 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/cli"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+// PinCmd represents the pin command.
+func PinCmd(aerospaceClient *aerospace.AeroSpaceClient) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "pin <pattern>",
+		Short: "Pin the matching windows so they follow the workspace switches",
+		Long: `Pin the matching windows so they follow the workspace switches.
+
+Pinned windows are moved to the focused workspace every time it changes, like
+sticky windows in i3. A pinned window hidden in the scratchpad stays there.
+
+It requires the follow hook, add this snippet in your aerospace.toml config:
+
+'''toml
+exec-on-workspace-change = ["/bin/bash", "-c",
+  "aerospace-scratchpad hook follow $AEROSPACE_FOCUSED_WORKSPACE"
+]
+'''
+
+See also: unpin
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateAllNonEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+			windowNamePattern := strings.TrimSpace(args[0])
+
+			outputFormat, _ := cmd.Flags().GetString("output")
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
+			if err != nil {
+				stderr.Println("Error: unsupported output format")
+				return
+			}
+			filterFlags, err := cmd.Flags().GetStringArray("filter")
+			if err != nil {
+				stderr.Println("Error: unable to get filter flags")
+				return
+			}
+
+			querier := aerospace.NewAerospaceQuerier(aerospaceClient)
+			windows, err := querier.GetFilteredWindows(
+				cmd.Context(),
+				windowNamePattern,
+				filterFlags,
+			)
+			if err != nil {
+				logger.LogError("PIN: unable to get filtered windows", "error", err)
+				stderr.Println("Error: %v", err)
+				return
+			}
+
+			pinned := make([]state.PinnedWindow, 0, len(windows))
+			for _, window := range windows {
+				pinned = append(pinned, state.PinnedWindow{
+					WindowID: window.WindowID,
+					AppName:  window.AppName,
+				})
+			}
+			if persistsState(cmd) {
+				if err = openPins().Add(pinned...); err != nil {
+					stderr.Println("Error: %v", err)
+					return
+				}
+			}
+
+			for _, window := range windows {
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command:   commandPin,
+					Action:    "pin",
+					WindowID:  window.WindowID,
+					AppName:   window.AppName,
+					Workspace: window.Workspace,
+					Result:    "ok",
+				}); printErr != nil {
+					logger.LogError("PIN: unable to write output", "error", printErr)
+				}
+			}
+		},
+	}
+
+	return command
+}
+
+// UnpinCmd represents the unpin command.
+func UnpinCmd(_ *aerospace.AeroSpaceClient) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "unpin [pattern]",
+		Short: "Unpin the windows matching the pattern, or all of them",
+		Long: `Unpin the pinned windows whose app name matches the pattern, so they
+stop following the workspace switches. Without a pattern every window is unpinned.
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+
+			outputFormat, _ := cmd.Flags().GetString("output")
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
+			if err != nil {
+				stderr.Println("Error: unsupported output format")
+				return
+			}
+
+			pattern := regexp.MustCompile("")
+			if len(args) > 0 {
+				pattern, err = regexp.Compile(strings.TrimSpace(args[0]))
+				if err != nil {
+					stderr.Println("Error: invalid pattern: %v", err)
+					return
+				}
+			}
+
+			pins := openPins()
+			pinned, err := pins.List()
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return
+			}
+
+			var unpinned []state.PinnedWindow
+			var unpinnedIDs []int
+			for _, window := range pinned {
+				if pattern.MatchString(window.AppName) {
+					unpinned = append(unpinned, window)
+					unpinnedIDs = append(unpinnedIDs, window.WindowID)
+				}
+			}
+			if len(unpinned) == 0 {
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command: commandUnpin,
+					Action:  "unpin",
+					Result:  "none",
+					Message: "no pinned windows matched",
+				}); printErr != nil {
+					logger.LogError("UNPIN: unable to write output", "error", printErr)
+				}
+				return
+			}
+
+			if persistsState(cmd) {
+				if err = pins.Remove(unpinnedIDs...); err != nil {
+					stderr.Println("Error: %v", err)
+					return
+				}
+			}
+
+			for _, window := range unpinned {
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command:  commandUnpin,
+					Action:   "unpin",
+					WindowID: window.WindowID,
+					AppName:  window.AppName,
+					Result:   "ok",
+				}); printErr != nil {
+					logger.LogError("UNPIN: unable to write output", "error", printErr)
+				}
+			}
+		},
+	}
+
+	return command
+}
+
+func openPins() *state.Pins {
+	return state.NewPins(state.DefaultPinsPath())
+}
@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+const pinWorld = `
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+  - workspace: ws2
+windows:
+  - window-id: 1
+    window-title: Player
+    app-name: Music
+    workspace: ws1
+  - window-id: 2
+    window-title: Call
+    app-name: Zoom
+    workspace: .scratchpad
+    window-layout: floating
+  - window-id: 3
+    window-title: Terminal
+    app-name: Ghostty
+    workspace: ws2
+`
+
+func TestPinAndFollow(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	t.Run("pinned windows follow the focused workspace", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, world := testutils.StartFakeAeroSpace(t, pinWorld)
+
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "pin", "Music|Zoom")
+		testutils.MatchSnapshot(t, "pin Music|Zoom", out, err)
+
+		if _, err = testutils.CmdExecute(cmd.RootCmd(client), "hook", "follow", "ws2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, window := range world.Snapshot().Windows {
+			switch window.WindowID {
+			case 1:
+				if window.Workspace != "ws2" {
+					t.Fatalf("expected Music to follow to ws2, got %q", window.Workspace)
+				}
+			case 2:
+				if window.Workspace != ".scratchpad" {
+					t.Fatalf("expected Zoom to stay hidden, got %q", window.Workspace)
+				}
+			}
+		}
+	})
+
+	t.Run("skips scratchpad workspaces", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, world := testutils.StartFakeAeroSpace(t, pinWorld)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "pin", "Music"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		t.Setenv("AEROSPACE_FOCUSED_WORKSPACE", ".scratchpad")
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "hook", "follow"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, window := range world.Snapshot().Windows {
+			if window.WindowID == 1 && window.Workspace != "ws1" {
+				t.Fatalf("expected Music to stay in ws1, got %q", window.Workspace)
+			}
+		}
+	})
+
+	t.Run("unpinned windows stay", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, world := testutils.StartFakeAeroSpace(t, pinWorld)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "pin", "Music"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "unpin")
+		if err != nil || !strings.Contains(out, "app_name=Music") {
+			t.Fatalf("expected Music unpinned, got %q %v", out, err)
+		}
+		if _, err = testutils.CmdExecute(cmd.RootCmd(client), "hook", "follow", "ws2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, window := range world.Snapshot().Windows {
+			if window.WindowID == 1 && window.Workspace != "ws1" {
+				t.Fatalf("expected Music to stay in ws1, got %q", window.Workspace)
+			}
+		}
+	})
+}
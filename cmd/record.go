@@ -0,0 +1,52 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/ipcrecord"
+)
+
+const recordFlag = "record"
+
+func enableRecordFlag(command *cobra.Command) *cobra.Command {
+	command.PersistentFlags().String(
+		recordFlag, "",
+		`Record every request sent to AeroSpace and its response, with timing, into a JSON lines file.
+The session can be replayed in tests (see internal/ipcrecord).`,
+	)
+	return command
+}
+
+// setupRecording wraps the client connection with a recorder when --record
+// is set. It returns nil when nothing is recorded.
+func setupRecording(
+	cmd *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+) (*ipcrecord.RecordingConnection, error) {
+	sessionPath, err := cmd.Flags().GetString(recordFlag)
+	if err != nil || sessionPath == "" {
+		return nil, nil //nolint:nilerr // the flag is not available or not set
+	}
+
+	// #nosec G304 -- the session path is provided by the user on purpose.
+	session, err := os.OpenFile(sessionPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open record session: %w", err)
+	}
+
+	// Record the raw traffic, the interceptors apply on top of the recorder.
+	recorder := ipcrecord.NewRecordingConnection(
+		aerospaceClient.GetUnderlyingClient().Connection(),
+		session,
+	)
+	aerospaceClient.SetClient(aerospace.NewClientFromConnection(recorder))
+
+	return recorder, nil
+}
@@ -0,0 +1,43 @@
+package cmd_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/ipcrecord"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+func TestRecord(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("records the requests sent to AeroSpace", func(t *testing.T) {
+		worldPath := writeWorldFile(t, fakeWorld)
+		sessionPath := filepath.Join(t.TempDir(), "session.jsonl")
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(nil),
+			"--simulate", worldPath, "--record", sessionPath, "summon", "Finder",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		entries, err := ipcrecord.LoadSession(sessionPath)
+		if err != nil {
+			t.Fatalf("unable to load session: %v", err)
+		}
+
+		var commands []string
+		for _, entry := range entries {
+			commands = append(commands, entry.Command)
+		}
+		expected := "list-workspaces list-windows move-node-to-workspace focus"
+		if strings.Join(commands, " ") != expected {
+			t.Fatalf("expected %q, got %q", expected, strings.Join(commands, " "))
+		}
+	})
+}
@@ -4,11 +4,15 @@ Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/ipcrecord"
 )
 
 // RootCmd represents the base command when called without any subcommands.
@@ -33,19 +37,61 @@ https://i3wm.org/docs/userguide.html#_scratchpad
 	rootCmd.PersistentFlags().
 		BoolP("dry-run", "n", false, "Run the command without moving windows (dry run mode)")
 
+	enableSimulateFlag(rootCmd)
+	enableRecordFlag(rootCmd)
+	enableTimingsFlag(rootCmd)
+	enableTimeoutFlag(rootCmd)
+
 	// Create custom client wrapper - now works with interface
 	customClient := aerospace.NewAeroSpaceClient(aerospaceClient)
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+	var simulatedWorld *fakeaerospace.World
+	var recorder *ipcrecord.RecordingConnection
+	var timings *timingsReport
+	cancelTimeout := func() {}
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		dry, _ := cmd.Flags().GetBool("dry-run")
 		customClient.SetOptions(aerospace.ClientOpts{
 			DryRun: dry,
+			Out:    cmd.OutOrStdout(),
 		})
+		cancelTimeout = setupTimeout(cmd, customClient)
+
+		world, err := setupSimulation(cmd, customClient)
+		if err != nil {
+			return err
+		}
+		simulatedWorld = world
+
+		recorder, err = setupRecording(cmd, customClient)
+		if err != nil {
+			return err
+		}
+
+		timings = setupTimings(cmd, customClient)
+
+		return nil
+	}
+	rootCmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		defer cancelTimeout()
+		if timings != nil {
+			timings.printTotal()
+		}
+		if recorder != nil {
+			if err := recorder.CloseConnection(); err != nil {
+				return fmt.Errorf("unable to close record session: %w", err)
+			}
+		}
+		if simulatedWorld == nil {
+			return nil
+		}
+		return printSimulatedWorld(cmd, simulatedWorld)
 	}
 
 	// Commands
 	rootCmd.AddCommand(compose([]flagsFn{
 		enableOutputFlag,
 		enableFilterFlag,
+		enableScopeFlag,
 	}, MoveCmd(customClient)))
 	rootCmd.AddCommand(compose([]flagsFn{
 		enableOutputFlag,
@@ -55,17 +101,51 @@ https://i3wm.org/docs/userguide.html#_scratchpad
 		enableOutputFlag,
 		enableFilterFlag,
 	}, SummonCmd(customClient)))
+	rootCmd.AddCommand(compose([]flagsFn{
+		enableOutputFlag,
+		enableFilterFlag,
+		enableScopeFlag,
+	}, SwapCmd(customClient)))
 	rootCmd.AddCommand(compose([]flagsFn{
 		enableOutputFlag,
 		enableMonitorFlag,
+		enableScopeFlag,
 	}, NextCmd(customClient)))
 	rootCmd.AddCommand(compose([]flagsFn{
 		enableOutputFlag,
 		enableFilterFlag,
 		enableMonitorFlag,
+		enableScopeFlag,
 	}, ListCmd(customClient)))
-	rootCmd.AddCommand(InfoCmd(aerospaceClient))
-	rootCmd.AddCommand(HookCmd(aerospaceClient))
+	rootCmd.AddCommand(compose([]flagsFn{
+		enableOutputFlag,
+	}, BatchCmd(customClient)))
+	rootCmd.AddCommand(compose([]flagsFn{
+		enableOutputFlag,
+		enableFilterFlag,
+	}, PinCmd(customClient)))
+	rootCmd.AddCommand(compose([]flagsFn{
+		enableOutputFlag,
+	}, UnpinCmd(customClient)))
+	rootCmd.AddCommand(compose([]flagsFn{
+		enableOutputFlag,
+	}, HistoryCmd(customClient)))
+	rootCmd.AddCommand(compose([]flagsFn{
+		enableOutputFlag,
+	}, UndoCmd(customClient)))
+	rootCmd.AddCommand(compose([]flagsFn{
+		enableFilterFlag,
+		enableScopeFlag,
+	}, WatchCmd(customClient)))
+	rootCmd.AddCommand(compose([]flagsFn{
+		enableScopeFlag,
+	}, StatusCmd(customClient)))
+	rootCmd.AddCommand(InfoCmd(customClient))
+	rootCmd.AddCommand(DoctorCmd(customClient))
+	rootCmd.AddCommand(HookCmd(customClient))
+	rootCmd.AddCommand(DaemonCmd(customClient))
+
+	registerMonitorCompletions(rootCmd, customClient)
 
 	return rootCmd
 }
@@ -86,6 +166,7 @@ func enableFilterFlag(command *cobra.Command) *cobra.Command {
 		`Filter windows by a specific property (e.g. window-title=^foo).
 Requires a key=value format. Can be used multiple times. `,
 	)
+	_ = command.RegisterFlagCompletionFunc("filter", completeFilterProperties)
 	return command
 }
 
@@ -93,9 +174,29 @@ func enableOutputFlag(command *cobra.Command) *cobra.Command {
 	command.Flags().StringP(
 		"output", "o", "text", "Output format: text|json|tsv|csv",
 	)
+	_ = command.RegisterFlagCompletionFunc("output", completeOutputFormats)
+	return command
+}
+
+// enableReportOutputFlag adds --output to the commands printing a report
+// instead of windows, e.g. info and doctor.
+func enableReportOutputFlag(command *cobra.Command) *cobra.Command {
+	command.Flags().StringP("output", "o", "text", "Output format: text|json")
+	_ = command.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(
+		[]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp,
+	))
 	return command
 }
 
+// reportOutputFormat returns the --output of enableReportOutputFlag.
+func reportOutputFormat(cmd *cobra.Command) (string, error) {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat != "text" && outputFormat != "json" {
+		return "", fmt.Errorf("unsupported output format %q, expected text or json", outputFormat)
+	}
+	return outputFormat, nil
+}
+
 func enableMonitorFlag(command *cobra.Command) *cobra.Command {
 	command.Flags().StringP(
 		"monitor", "m", "current",
@@ -110,8 +211,16 @@ func Execute(
 	rootCmd := RootCmd(aerospaceClient)
 
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCodeOf(err))
+	}
+}
+
+// exitCodeOf returns the exit code for an error returned by a command.
+func exitCodeOf(err error) int {
+	if errors.Is(err, aerospace.ErrTimeout) {
+		return aerospace.ExitCodeTimeout
 	}
+	return 1
 }
 
 // VERSION The CLI current version
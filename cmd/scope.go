@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/config"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+)
+
+const scopeFlag = "scope"
+
+func enableScopeFlag(command *cobra.Command) *cobra.Command {
+	command.Flags().String(
+		scopeFlag, "",
+		`Windows counted as scratchpad windows besides the ones in the scratchpad: `+
+			`"strict" the ones moved there by aerospace-scratchpad, "floating" (default) or "all"`,
+	)
+	scopes := make([]string, 0, len(aerospace.Scopes()))
+	for _, scope := range aerospace.Scopes() {
+		scopes = append(scopes, string(scope))
+	}
+	_ = command.RegisterFlagCompletionFunc(scopeFlag, cobra.FixedCompletions(
+		scopes, cobra.ShellCompDirectiveNoFileComp,
+	))
+	return command
+}
+
+// scratchpadScope returns the definition of a scratchpad window: --scope,
+// else the config scope, and the config exclusions.
+func scratchpadScope(cmd *cobra.Command) (aerospace.ScratchpadScope, error) {
+	scope := aerospace.ScratchpadScope{Scope: aerospace.ScopeFloating}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return scope, err
+	}
+
+	value, _ := cmd.Flags().GetString(scopeFlag)
+	if value == "" {
+		value = cfg.ScratchpadWindows.Scope
+	}
+	if value != "" {
+		if scope.Scope, err = aerospace.ParseScope(value); err != nil {
+			return scope, err
+		}
+	}
+
+	for index, matcher := range cfg.ScratchpadWindows.Exclude {
+		filters, parseErr := aerospace.ParseFilters(matcher.Filter)
+		if parseErr != nil {
+			return scope, fmt.Errorf("scratchpad-windows exclude %d: %w", index+1, parseErr)
+		}
+		scope.Exclude = append(scope.Exclude, filters)
+	}
+
+	if scope.Scope == aerospace.ScopeStrict {
+		scope.Managed = managedWindows()
+	}
+
+	return scope, nil
+}
+
+// newScratchpadQuerier returns a querier counting the scratchpad windows of
+// scratchpadScope.
+func newScratchpadQuerier(
+	cmd *cobra.Command,
+	aerospaceClient aerospace.AeroSpaceWMClient,
+) (aerospace.Querier, error) {
+	scope, err := scratchpadScope(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return aerospace.NewAerospaceQuerierWithScope(aerospaceClient, scope), nil
+}
+
+// managedWindows returns the windows aerospace-scratchpad moved to a
+// scratchpad workspace.
+func managedWindows() map[int]bool {
+	managed := map[int]bool{}
+
+	windowIDs, err := openManaged().List()
+	if err != nil {
+		logger.GetDefaultLogger().LogError("SCOPE: unable to read managed windows", "error", err)
+		return managed
+	}
+	for _, windowID := range windowIDs {
+		managed[windowID] = true
+	}
+	return managed
+}
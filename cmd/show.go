@@ -4,7 +4,7 @@ Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
 package cmd
 
 import (
-	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -29,8 +29,25 @@ func ShowCmd(
 By default, it will set the window to floating and focus on it.
 
 Similar to I3/Sway WM, it will toggle show/hide the window if called multiple times.
+
+With --workspace, the windows are shown in that workspace instead, "prev" being
+the workspace focused before the current one. They are focused only with
+--focus, and the toggle hides the windows already there unless --focus is given
+and none of them is focused.
+
+With --focus-policy, "restore" (default) focuses the window focused before the
+shown ones once the toggle hides them, "keep" shows them without the focus and
+toggles on visibility, "none" leaves the focus to AeroSpace once hidden.
+
+With --auto-hide, the shown windows go back to the scratchpad once another
+window gains focus. It requires the focus-changed hook (see hook focus-changed).
+
+With --hide-after, the shown windows go back to the scratchpad once they have
+been without focus for that long. The daemon hides them when it runs, a
+background process otherwise. Moving or closing a window cancels it.
 `,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeAppNames(aerospaceClient),
 		Run: func(cmd *cobra.Command, args []string) {
 			logger := logger.GetDefaultLogger()
 			logger.LogDebug("SHOW: start command", "args", args)
@@ -47,7 +64,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 				stderr.Println("Error: unable to get output format")
 				return
 			}
-			formatter, err := cli.NewOutputFormatter(os.Stdout, outputFormat)
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
 			if err != nil {
 				logger.LogError("SHOW: invalid output format", "error", err)
 				stderr.Println("Error: unsupported output format")
@@ -66,7 +83,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 				return
 			}
 
-			focusedWorkspace, err := aerospaceClient.GetFocusedWorkspace()
+			focusedWorkspace, err := aerospaceClient.GetFocusedWorkspace(cmd.Context())
 			if err != nil {
 				logger.LogError(
 					"SHOW: unable to get focused workspace",
@@ -76,15 +93,23 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 				stderr.Println("Error: unable to get focused workspace")
 				return
 			}
+
+			target, err := resolveTargetWorkspace(cmd, focusedWorkspace)
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return
+			}
 			logger.LogDebug(
-				"SHOW: retrieved focused workspace",
+				"SHOW: retrieved target workspace",
 				"workspace",
-				focusedWorkspace,
+				target.Workspace,
+				"focus",
+				target.Focus,
 			)
 
 			// Get the current monitor ID before any focus changes
 			currentMonitorID := 0
-			monitor, err := aerospace.GetFocusedMonitor(aerospaceClient.GetUnderlyingClient())
+			monitor, err := aerospace.GetFocusedMonitor(aerospaceClient)
 			if err != nil {
 				logger.LogError(
 					"SHOW: unable to get focused monitor, defaulting to 0",
@@ -94,16 +119,33 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 			} else {
 				currentMonitorID = monitor.MonitorID
 			}
+			if !target.Focused {
+				// The windows toggled off go to the scratchpad of the
+				// monitor showing them.
+				currentMonitorID = monitorOfWorkspace(
+					cmd.Context(), aerospaceClient, target.Workspace.Workspace, currentMonitorID,
+				)
+			}
 			logger.LogDebug(
 				"SHOW: retrieved focused monitor",
 				"monitorID",
 				currentMonitorID,
 			)
 
-			querier := aerospace.NewAerospaceQuerier(aerospaceClient.GetUnderlyingClient())
-			mover := aerospace.NewAeroSpaceMover(aerospaceClient)
+			querier := aerospace.NewAerospaceQuerier(aerospaceClient)
+			mover := newMover(cmd, aerospaceClient)
+
+			autoHide, _ := cmd.Flags().GetBool(autoHideFlag)
+			hideAfter, _ := cmd.Flags().GetDuration(hideAfterFlag)
+			var shownWindows []windowsipc.Window
+			if autoHide || hideAfter > 0 {
+				defer func() {
+					trackAutoHide(cmd, shownWindows, currentMonitorID, target.Workspace.Workspace, hideAfter)
+				}()
+			}
 
 			windows, err := querier.GetFilteredWindows(
+				cmd.Context(),
 				windowNamePattern,
 				filterFlags,
 			)
@@ -116,7 +158,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 			var windowsInFocusedWorkspace []windowsipc.Window
 			var hasAtLeastOneWindowFocused bool
 			for _, window := range windows {
-				isWindowInFocusedWorkspace := window.Workspace == focusedWorkspace.Workspace
+				isWindowInFocusedWorkspace := window.Workspace == target.Workspace.Workspace
 				if isWindowInFocusedWorkspace {
 					windowsInFocusedWorkspace = append(
 						windowsInFocusedWorkspace,
@@ -124,6 +166,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 					)
 
 					isWindowFocused, focusErr := querier.IsWindowFocused(
+						cmd.Context(),
 						window.WindowID,
 					)
 					if focusErr != nil {
@@ -156,11 +199,18 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 				"hasAtLeastOneWindowFocused", hasAtLeastOneWindowFocused,
 			)
 
+			if target.Focus && !hasAtLeastOneWindowFocused {
+				rememberFocus(cmd, aerospaceClient, target, windows)
+			}
+			matchedWindows := slices.Concat(windowsOutsideView, windowsInFocusedWorkspace)
+			markFocusing(cmd, windowIDsOf(matchedWindows)...)
+
 			for _, window := range windowsOutsideView {
 				moveErr := mover.MoveWindowToWorkspace(
+					cmd.Context(),
 					&window,
-					focusedWorkspace,
-					!hasAtLeastOneWindowFocused,
+					target.Workspace,
+					target.Focus && !hasAtLeastOneWindowFocused,
 				)
 				if moveErr != nil {
 					stderr.Printf(
@@ -170,6 +220,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 					)
 					return
 				}
+				shownWindows = append(shownWindows, window)
 
 				if printErr := formatter.Print(cli.OutputEvent{
 					Command:         commandShow,
@@ -177,7 +228,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 					WindowID:        window.WindowID,
 					AppName:         window.AppName,
 					Workspace:       window.Workspace,
-					TargetWorkspace: focusedWorkspace.Workspace,
+					TargetWorkspace: target.Workspace.Workspace,
 					Result:          "ok",
 				}); printErr != nil {
 					logger.LogError("SHOW: unable to write output", "error", printErr)
@@ -187,9 +238,12 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 			// NOTE: To avoid the ping pong of windows, so priority is
 			// for bringing windows to the focused workspace
 			if len(windowsOutsideView) > 0 {
+				if !target.Focus {
+					return
+				}
 				// Make sure to bring the remaining matched windows to the front
 				for _, window := range windowsInFocusedWorkspace {
-					err = aerospaceClient.SetFocusByWindowID(window.WindowID)
+					err = aerospaceClient.SetFocusByWindowID(cmd.Context(), window.WindowID)
 					if err != nil {
 						stderr.Printf(
 							"Error: unable to set focus to window '%+v'\n%s",
@@ -203,6 +257,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 						"window",
 						window,
 					)
+					shownWindows = append(shownWindows, window)
 					if printErr := formatter.Print(cli.OutputEvent{
 						Command:   commandShow,
 						Action:    "focus",
@@ -218,14 +273,19 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 				return
 			}
 
+			// Without focus to give, the windows already in the target
+			// workspace are toggled off.
+			hideWindows := hasAtLeastOneWindowFocused || !target.Focus
+			var hiddenWindows []windowsipc.Window
 			for _, window := range windowsInFocusedWorkspace {
 				logger.LogDebug(
 					"SHOW: processing window in focused workspace",
 					"window", window,
 					"hasAtLeastOneWindowFocused", hasAtLeastOneWindowFocused,
 				)
-				if hasAtLeastOneWindowFocused { // conditional flow mirrors show toggle behavior
+				if hideWindows { // conditional flow mirrors show toggle behavior
 					targetWorkspace, moveErr := mover.MoveWindowToScratchpadForMonitor(
+						cmd.Context(),
 						window, currentMonitorID,
 					)
 					if moveErr != nil {
@@ -250,6 +310,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 						}
 						continue
 					}
+					hiddenWindows = append(hiddenWindows, window)
 
 					if printErr := formatter.Print(cli.OutputEvent{
 						Command:         commandShow,
@@ -265,7 +326,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 					continue
 				}
 
-				err = aerospaceClient.SetFocusByWindowID(window.WindowID)
+				err = aerospaceClient.SetFocusByWindowID(cmd.Context(), window.WindowID)
 				if err != nil {
 					stderr.Printf(
 						"Error: unable to set focus to window '%+v'\n%s",
@@ -274,6 +335,7 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 					)
 					return
 				}
+				shownWindows = append(shownWindows, window)
 				if printErr := formatter.Print(cli.OutputEvent{
 					Command:   commandShow,
 					Action:    "focus",
@@ -285,7 +347,18 @@ Similar to I3/Sway WM, it will toggle show/hide the window if called multiple ti
 					logger.LogError("SHOW: unable to write output", "error", printErr)
 				}
 			}
+
+			restoreFocus(cmd, aerospaceClient, target, hiddenWindows, hasAtLeastOneWindowFocused)
 		},
 	}
+
+	command.Flags().Bool(
+		autoHideFlag, false,
+		"Hide the shown windows again once another window gains focus",
+	)
+	enableHideAfterFlag(command)
+	command.MarkFlagsMutuallyExclusive(autoHideFlag, hideAfterFlag)
+	enableTargetFlags(command, aerospaceClient)
+
 	return command
 }
@@ -21,6 +21,8 @@ import (
 //nolint:gocognit // Integration-style test exercises multiple window scenarios for coverage
 func TestShowCmd(t *testing.T) {
 	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	// The focus remembered by a show would be restored by the next one.
+	t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
 
 	t.Run("fails when pattern is empty", func(t *testing.T) {
 		command := "show"
@@ -167,7 +169,7 @@ func TestShowCmd(t *testing.T) {
 				aerospaceClient.GetWindowsMock().EXPECT().
 					GetFocusedWindow().
 					Return(focusedWindow, nil).
-					Times(1),
+					Times(2), // checked, then remembered to be focused again on hide
 
 				aerospaceClient.GetFocusMock().EXPECT().
 					SetFocusByWindowID(focusedTree.Windows[1].WindowID).
@@ -213,6 +215,9 @@ func TestShowCmd(t *testing.T) {
 		command := "show"
 		args := []string{command, "Finder"}
 
+		// Without the focus remembered by the previous tests to restore.
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
@@ -361,6 +366,12 @@ func TestShowCmd(t *testing.T) {
 				Times(1)
 
 			gomock.InOrder(
+				// The focused window is remembered to be focused again on hide
+				aerospaceClient.GetWindowsMock().EXPECT().
+					GetFocusedWindow().
+					Return(focusedWindow, nil).
+					Times(1),
+
 				aerospaceClient.GetWorkspacesMock().EXPECT().
 					MoveWindowToWorkspaceWithOpts(
 						workspaces.MoveWindowToWorkspaceArgs{
@@ -462,7 +473,7 @@ func TestShowCmd(t *testing.T) {
 
 			allWindows := testutils.ExtractAllWindows(tree)
 			focusedTree := testutils.ExtractFocusedTree(tree)
-			// focusedWindow := testutils.ExtractFocusedWindow(tree)
+			focusedWindow := testutils.ExtractFocusedWindow(tree)
 
 			aerospaceClient := testutils.NewMockAeroSpaceWM(ctrl)
 
@@ -477,6 +488,12 @@ func TestShowCmd(t *testing.T) {
 				Times(1)
 
 			gomock.InOrder(
+				// The focused window is remembered to be focused again on hide
+				aerospaceClient.GetWindowsMock().EXPECT().
+					GetFocusedWindow().
+					Return(focusedWindow, nil).
+					Times(1),
+
 				// Send first window
 				aerospaceClient.GetWorkspacesMock().EXPECT().
 					MoveWindowToWorkspaceWithOpts(
@@ -545,6 +562,9 @@ func TestShowCmd(t *testing.T) {
 				command := "show"
 				args := []string{command, "Finder"}
 
+				// Without the focus remembered by the previous tests to restore.
+				t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+
 				ctrl := gomock.NewController(tt)
 				defer ctrl.Finish()
 
@@ -743,7 +763,7 @@ func TestShowCmd(t *testing.T) {
 					aerospaceClient.GetWindowsMock().EXPECT().
 						GetFocusedWindow().
 						Return(focusedWindow, nil).
-						Times(1),
+						Times(2), // checked, then remembered to be focused again on hide
 
 					aerospaceClient.GetWorkspacesMock().EXPECT().
 						MoveWindowToWorkspaceWithOpts(
@@ -962,7 +982,7 @@ func TestShowCmd(t *testing.T) {
 
 				allWindows := testutils.ExtractAllWindows(tree)
 				focusedTree := testutils.ExtractFocusedTree(tree)
-				// focusedWindow := testutils.ExtractFocusedWindow(tree)
+				focusedWindow := testutils.ExtractFocusedWindow(tree)
 
 				aerospaceClient := testutils.NewMockAeroSpaceWM(ctrl)
 				aerospaceClient.GetWindowsMock().EXPECT().
@@ -976,6 +996,12 @@ func TestShowCmd(t *testing.T) {
 					Times(1)
 
 				gomock.InOrder(
+					// The focused window is remembered to be focused again on hide
+					aerospaceClient.GetWindowsMock().EXPECT().
+						GetFocusedWindow().
+						Return(focusedWindow, nil).
+						Times(1),
+
 					// Send first window
 					aerospaceClient.GetWorkspacesMock().EXPECT().
 						MoveWindowToWorkspaceWithOpts(
@@ -1082,7 +1108,7 @@ func TestShowCmd(t *testing.T) {
 
 				allWindows := testutils.ExtractAllWindows(tree)
 				focusedTree := testutils.ExtractFocusedTree(tree)
-				// focusedWindow := testutils.ExtractFocusedWindow(tree)
+				focusedWindow := testutils.ExtractFocusedWindow(tree)
 
 				aerospaceClient := testutils.NewMockAeroSpaceWM(ctrl)
 				aerospaceClient.GetWindowsMock().EXPECT().
@@ -1096,6 +1122,12 @@ func TestShowCmd(t *testing.T) {
 					Times(1)
 
 				gomock.InOrder(
+					// The focused window is remembered to be focused again on hide
+					aerospaceClient.GetWindowsMock().EXPECT().
+						GetFocusedWindow().
+						Return(focusedWindow, nil).
+						Times(1),
+
 					// Send first window
 					aerospaceClient.GetWorkspacesMock().EXPECT().
 						MoveWindowToWorkspaceWithOpts(
@@ -0,0 +1,95 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+)
+
+const simulateFlag = "simulate"
+
+func enableSimulateFlag(command *cobra.Command) *cobra.Command {
+	command.PersistentFlags().String(
+		simulateFlag, "",
+		`Run the command against a world described in a yaml file instead of AeroSpace.
+The world uses the same shape as the test snapshots and is printed after the command.`,
+	)
+	return command
+}
+
+// RequiresConnection reports whether the given CLI arguments need a
+// connection to AeroSpace. Simulated runs never touch the real socket, and
+// hook install and uninstall only edit the AeroSpace config.
+func RequiresConnection(args []string) bool {
+	if hasFlagArg(args, simulateFlag) {
+		return false
+	}
+
+	var positional []string
+	for _, arg := range args {
+		if arg == "--" || len(positional) == 2 {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+	editsConfig := len(positional) == 2 && positional[0] == hookCommand &&
+		(positional[1] == installSubcommand || positional[1] == uninstallSubcommand)
+	return !editsConfig
+}
+
+// hasFlagArg reports whether the long flag is set in the raw CLI arguments,
+// before the flags are parsed.
+func hasFlagArg(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--"+name || strings.HasPrefix(arg, "--"+name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// setupSimulation points the client to an in-process world when --simulate
+// is set. It returns nil when the command runs against AeroSpace.
+func setupSimulation(
+	cmd *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+) (*fakeaerospace.World, error) {
+	worldPath, err := cmd.Flags().GetString(simulateFlag)
+	if err != nil || worldPath == "" {
+		return nil, nil //nolint:nilerr // the flag is not available or not set
+	}
+
+	world, err := fakeaerospace.LoadWorld(worldPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load simulated world: %w", err)
+	}
+
+	aerospaceClient.SetClient(
+		aerospace.NewClientFromConnection(fakeaerospace.NewConnection(world)),
+	)
+
+	return world, nil
+}
+
+// printSimulatedWorld prints the world as it is after the command ran.
+func printSimulatedWorld(cmd *cobra.Command, world *fakeaerospace.World) error {
+	data, err := world.Marshal()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "# simulated world\n%s", data)
+	return err
+}
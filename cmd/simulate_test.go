@@ -0,0 +1,109 @@
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+func writeWorldFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "world.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write world file: %v", err)
+	}
+	return path
+}
+
+func TestSimulate(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("shows a scratchpad window and prints the resulting world", func(t *testing.T) {
+		worldPath := writeWorldFile(t, fakeWorld)
+
+		out, err := testutils.CmdExecute(
+			cmd.RootCmd(nil),
+			"show", "Finder", "--simulate", worldPath,
+		)
+
+		testutils.MatchSnapshot(t, "show Finder --simulate world.yaml", out, err)
+	})
+
+	t.Run("hides a focused window back to the scratchpad", func(t *testing.T) {
+		worldPath := writeWorldFile(t, fakeWorld)
+
+		out, err := testutils.CmdExecute(
+			cmd.RootCmd(nil),
+			"--simulate", worldPath, "show", "Ghostty", "-o", "json",
+		)
+
+		testutils.MatchSnapshot(t, "--simulate world.yaml show Ghostty -o json", out, err)
+	})
+
+	t.Run("fails when the world file is missing", func(t *testing.T) {
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(nil),
+			"list", "--simulate", filepath.Join(t.TempDir(), "missing.yaml"),
+		)
+		if err == nil {
+			t.Fatalf("expected error for missing world file")
+		}
+	})
+}
+
+func TestTimings(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("prints each request and the total to stderr", func(t *testing.T) {
+		worldPath := writeWorldFile(t, fakeWorld)
+
+		var timings bytes.Buffer
+		rootCmd := cmd.RootCmd(nil)
+		rootCmd.SetErr(&timings)
+
+		_, err := testutils.CmdExecute(
+			rootCmd,
+			"--simulate", worldPath, "--timings", "summon", "Finder",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(timings.String()), "\n")
+		if len(lines) != 5 ||
+			!strings.Contains(lines[2], "move-node-to-workspace ws1 --window-id 2") ||
+			!strings.HasSuffix(lines[4], "total (4 requests)") {
+			t.Fatalf("unexpected timings:\n%s", timings.String())
+		}
+	})
+}
+
+func TestRequiresConnection(t *testing.T) {
+	cases := map[string]struct {
+		args     []string
+		expected bool
+	}{
+		"plain command":   {args: []string{"show", "Finder"}, expected: true},
+		"simulate":        {args: []string{"show", "--simulate", "w.yaml"}, expected: false},
+		"simulate equals": {args: []string{"--simulate=w.yaml", "list"}, expected: false},
+		"after dashes":    {args: []string{"show", "--", "--simulate"}, expected: true},
+		"hook install":    {args: []string{"hook", "install", "--dry-run"}, expected: false},
+		"hook uninstall":  {args: []string{"--dry-run", "hook", "uninstall"}, expected: false},
+		"other hook":      {args: []string{"hook", "pull-window", "1", "install"}, expected: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := cmd.RequiresConnection(tc.args); got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
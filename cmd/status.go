@@ -0,0 +1,365 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+const (
+	statusFormatFlag = "format"
+	statusWatchFlag  = "watch"
+)
+
+// statusWindow is a scratchpad window in the status summary.
+type statusWindow struct {
+	WindowID  int    `json:"window_id"`
+	AppName   string `json:"app_name"`
+	Workspace string `json:"workspace"`
+}
+
+// statusCounts summarizes a set of scratchpad windows.
+type statusCounts struct {
+	// Hidden is how many windows are in a scratchpad workspace.
+	Hidden int `json:"hidden"`
+	// Visible are the scratchpad windows out of the scratchpad workspaces.
+	Visible []statusWindow `json:"visible"`
+	// FocusedWindow is the focused scratchpad window, if any.
+	FocusedWindow *statusWindow `json:"focused_window"`
+}
+
+// statusGroup summarizes the windows sharing a tag, see hook window-detected.
+type statusGroup struct {
+	Group string `json:"group"`
+	statusCounts
+}
+
+// statusMonitor summarizes the scratchpad windows of a monitor.
+type statusMonitor struct {
+	MonitorID   int    `json:"monitor_id"`
+	MonitorName string `json:"monitor_name"`
+	// Focused reports whether it is the focused monitor.
+	Focused bool `json:"focused"`
+	statusCounts
+	Groups []statusGroup `json:"groups"`
+}
+
+type statusSummary struct {
+	Monitors []statusMonitor `json:"monitors"`
+}
+
+// StatusCmd represents the status command.
+//
+//nolint:funlen,gocognit // command wiring and the watch loop keep this function long
+func StatusCmd(aerospaceClient *aerospace.AeroSpaceClient) *cobra.Command {
+	command := &cobra.Command{
+		Use:   commandStatus,
+		Short: "Summarize the scratchpad windows of each monitor, e.g. for a status bar",
+		Long: `Summarize the scratchpad windows of each monitor and of each group: how many
+are hidden in the scratchpad, the ones visible and the focused one. The groups
+are the tags set by the window-detected rules.
+
+--format renders each monitor with a Go template, having the fields:
+  .MonitorID .MonitorName .Focused .Hidden .Visible .FocusedWindow .Groups
+where the windows have .WindowID .AppName .Workspace and the groups .Group,
+.Hidden, .Visible and .FocusedWindow.
+
+--watch queries AeroSpace every interval and prints the summary again only
+when it changes, until interrupted.
+
+Example:
+  aerospace-scratchpad status
+  aerospace-scratchpad status -o json
+  aerospace-scratchpad status --monitor current \
+    --format '{{.Hidden}}{{with .FocusedWindow}} {{.AppName}}{{end}}' --watch 1s
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := logger.GetDefaultLogger()
+
+			render, err := statusRenderer(cmd)
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return nil
+			}
+
+			monitorID, err := parseMonitorFlag(cmd)
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return nil
+			}
+
+			interval, _ := cmd.Flags().GetDuration(statusWatchFlag)
+			if interval < 0 {
+				stderr.Println("Error: --watch interval must be positive")
+				return nil
+			}
+
+			if _, err = scratchpadScope(cmd); err != nil {
+				stderr.Println("Error: %v", err)
+				return nil
+			}
+
+			out := cmd.OutOrStdout()
+			if interval == 0 {
+				summary, summaryErr := summarizeStatus(cmd.Context(), cmd, aerospaceClient, monitorID)
+				if summaryErr != nil {
+					logger.LogError("STATUS: unable to summarize", "error", summaryErr)
+					stderr.Println("Error: unable to summarize the scratchpad: %v", summaryErr)
+					return nil
+				}
+				rendered, renderErr := render(summary)
+				if renderErr != nil {
+					stderr.Println("Error: %v", renderErr)
+					return nil
+				}
+				_, err = fmt.Fprint(out, rendered)
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			last := ""
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				// AeroSpace may be restarting, the next tick tries again.
+				summary, summaryErr := summarizeStatus(ctx, cmd, aerospaceClient, monitorID)
+				if summaryErr != nil {
+					logger.LogError("STATUS: unable to summarize", "error", summaryErr)
+				} else if rendered, renderErr := render(summary); renderErr != nil {
+					stderr.Println("Error: %v", renderErr)
+					return nil
+				} else if rendered != last {
+					if _, err = fmt.Fprint(out, rendered); err != nil {
+						return err
+					}
+					last = rendered
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	enableReportOutputFlag(command)
+	command.Flags().StringP(
+		"monitor", "m", "all",
+		`Monitor filter: "all" (default) for all monitors, "current" for current monitor, or a monitor ID (e.g., 1)`,
+	)
+	command.Flags().String(
+		statusFormatFlag, "",
+		"Go template rendering each monitor, see the fields above",
+	)
+	command.Flags().Duration(
+		statusWatchFlag, 0,
+		"Print the summary again whenever it changes, checking every interval (e.g. 1s)",
+	)
+
+	return command
+}
+
+// statusRenderer returns how the summary is printed: the --format template
+// for each monitor, JSON or the default text.
+func statusRenderer(cmd *cobra.Command) (func(statusSummary) (string, error), error) {
+	outputFormat, err := reportOutputFormat(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	format, _ := cmd.Flags().GetString(statusFormatFlag)
+	if format != "" {
+		tmpl, parseErr := template.New(commandStatus).Option("missingkey=error").Parse(format)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid --format: %w", parseErr)
+		}
+		return func(summary statusSummary) (string, error) {
+			var out bytes.Buffer
+			for _, monitor := range summary.Monitors {
+				if execErr := tmpl.Execute(&out, monitor); execErr != nil {
+					return "", fmt.Errorf("invalid --format: %w", execErr)
+				}
+				out.WriteByte('\n')
+			}
+			return out.String(), nil
+		}, nil
+	}
+
+	if outputFormat == "json" {
+		return func(summary statusSummary) (string, error) {
+			data, marshalErr := json.Marshal(summary)
+			if marshalErr != nil {
+				return "", marshalErr
+			}
+			return string(data) + "\n", nil
+		}, nil
+	}
+
+	return func(summary statusSummary) (string, error) {
+		var out strings.Builder
+		for _, monitor := range summary.Monitors {
+			fmt.Fprintf(&out, "%d %s: %s\n", monitor.MonitorID, monitor.MonitorName, monitor.statusCounts)
+			for _, group := range monitor.Groups {
+				fmt.Fprintf(&out, "  %s: %s\n", group.Group, group.statusCounts)
+			}
+		}
+		return out.String(), nil
+	}, nil
+}
+
+func (c statusCounts) String() string {
+	text := fmt.Sprintf("%d hidden, %d visible", c.Hidden, len(c.Visible))
+	if c.FocusedWindow != nil {
+		text += ", focused " + c.FocusedWindow.AppName
+	}
+	return text
+}
+
+// summarizeStatus summarizes the scratchpad windows of the monitors matching
+// monitorID, as parsed by parseMonitorFlag.
+func summarizeStatus(
+	ctx context.Context,
+	cmd *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+	monitorID int,
+) (statusSummary, error) {
+	wm := aerospace.WithContext(ctx, aerospaceClient)
+
+	// Resolved on every call, strict windows come and go while watching.
+	querier, err := newScratchpadQuerier(cmd, aerospaceClient)
+	if err != nil {
+		return statusSummary{}, err
+	}
+
+	monitors, err := aerospace.ListMonitors(wm)
+	if err != nil {
+		return statusSummary{}, err
+	}
+
+	focusedMonitorID := 0
+	if focusedMonitor, focusErr := aerospace.GetFocusedMonitor(wm); focusErr == nil {
+		focusedMonitorID = focusedMonitor.MonitorID
+	}
+
+	focusedWindowID := 0
+	// No window may be focused, e.g. on an empty workspace.
+	if focusedWindow, focusErr := wm.Windows().GetFocusedWindow(); focusErr == nil {
+		focusedWindowID = focusedWindow.WindowID
+	}
+
+	tags := map[int]string{}
+	tagged, err := openTags().List()
+	if err != nil {
+		logger.GetDefaultLogger().LogError("STATUS: unable to read tags", "error", err)
+	}
+	for _, window := range tagged {
+		tags[window.WindowID] = window.Tag
+	}
+
+	summary := statusSummary{Monitors: []statusMonitor{}}
+	for _, monitor := range monitors {
+		switch {
+		case monitorID == -2 && monitor.MonitorID != focusedMonitorID && focusedMonitorID != 0:
+			continue
+		case monitorID >= 0 && monitor.MonitorID != monitorID:
+			continue
+		}
+
+		windows, windowsErr := querier.GetScratchpadWindowsForMonitor(ctx, monitor.MonitorID)
+		if windowsErr != nil {
+			return statusSummary{}, windowsErr
+		}
+		summary.Monitors = append(summary.Monitors, summarizeMonitor(
+			monitor,
+			monitor.MonitorID == focusedMonitorID,
+			windows,
+			focusedWindowID,
+			tags,
+		))
+	}
+
+	if monitorID >= 0 && len(summary.Monitors) == 0 {
+		return statusSummary{}, errors.New("monitor not found")
+	}
+	return summary, nil
+}
+
+func summarizeMonitor(
+	monitor aerospace.MonitorInfo,
+	focused bool,
+	windows []windowsipc.Window,
+	focusedWindowID int,
+	tags map[int]string,
+) statusMonitor {
+	sortWindowsByAppName(windows)
+
+	summary := statusMonitor{
+		MonitorID:    monitor.MonitorID,
+		MonitorName:  monitor.MonitorName,
+		Focused:      focused,
+		statusCounts: statusCounts{Visible: []statusWindow{}},
+		Groups:       []statusGroup{},
+	}
+
+	groups := map[string]*statusGroup{}
+	for _, window := range windows {
+		counts := []*statusCounts{&summary.statusCounts}
+		if tag, ok := tags[window.WindowID]; ok {
+			group, exists := groups[tag]
+			if !exists {
+				group = &statusGroup{Group: tag, statusCounts: statusCounts{Visible: []statusWindow{}}}
+				groups[tag] = group
+			}
+			counts = append(counts, &group.statusCounts)
+		}
+
+		current := statusWindow{
+			WindowID:  window.WindowID,
+			AppName:   window.AppName,
+			Workspace: window.Workspace,
+		}
+		for _, count := range counts {
+			if aerospace.IsScratchpadWorkspace(window.Workspace) {
+				count.Hidden++
+			} else {
+				count.Visible = append(count.Visible, current)
+			}
+			if window.WindowID == focusedWindowID {
+				count.FocusedWindow = &current
+			}
+		}
+	}
+
+	for _, group := range groups {
+		summary.Groups = append(summary.Groups, *group)
+	}
+	sort.Slice(summary.Groups, func(i, j int) bool {
+		return summary.Groups[i].Group < summary.Groups[j].Group
+	})
+
+	return summary
+}
@@ -0,0 +1,169 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+const statusWorld = `
+focused-workspace: ws1
+monitors:
+  - monitor-id: 1
+    monitor-name: Built-in Retina Display
+  - monitor-id: 2
+    monitor-name: DELL U2720Q
+workspaces:
+  - workspace: ws1
+    focused-window-id: 3
+    monitor-id: 1
+  - workspace: .scratchpad.1
+    monitor-id: 1
+  - workspace: ws2
+    monitor-id: 2
+  - workspace: .scratchpad.2
+    monitor-id: 2
+windows:
+  - window-id: 1
+    app-name: Ghostty
+    workspace: ws1
+  - window-id: 2
+    app-name: Slack
+    workspace: .scratchpad.1
+    window-layout: floating
+  - window-id: 3
+    app-name: Finder
+    workspace: ws1
+    window-layout: floating
+  - window-id: 4
+    app-name: Notes
+    workspace: .scratchpad.2
+    window-layout: floating
+`
+
+func TestStatusCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	setupStatus := func(t *testing.T) {
+		t.Helper()
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		if err := state.NewTags(state.DefaultTagsPath()).Add(state.TaggedWindow{
+			WindowID: 2,
+			AppName:  "Slack",
+			Tag:      "chat",
+		}); err != nil {
+			t.Fatalf("unable to tag: %v", err)
+		}
+	}
+
+	t.Run("summarizes each monitor and group", func(t *testing.T) {
+		setupStatus(t)
+		client, _ := testutils.StartFakeAeroSpace(t, statusWorld)
+
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "status")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "1 Built-in Retina Display: 1 hidden, 1 visible, focused Finder\n" +
+			"  chat: 1 hidden, 0 visible\n" +
+			"2 DELL U2720Q: 1 hidden, 0 visible\n"
+		if out != expected {
+			t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+		}
+	})
+
+	t.Run("prints the summary as JSON", func(t *testing.T) {
+		setupStatus(t)
+		client, _ := testutils.StartFakeAeroSpace(t, statusWorld)
+
+		out, err := testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"status", "--monitor", "current", "-o", "json",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var summary struct {
+			Monitors []struct {
+				MonitorID     int  `json:"monitor_id"`
+				Focused       bool `json:"focused"`
+				Hidden        int  `json:"hidden"`
+				FocusedWindow *struct {
+					WindowID int `json:"window_id"`
+				} `json:"focused_window"`
+				Groups []struct {
+					Group string `json:"group"`
+				} `json:"groups"`
+			} `json:"monitors"`
+		}
+		if err = json.Unmarshal([]byte(out), &summary); err != nil {
+			t.Fatalf("expected JSON, got %q: %v", out, err)
+		}
+		if len(summary.Monitors) != 1 {
+			t.Fatalf("expected only the current monitor, got %s", out)
+		}
+		monitor := summary.Monitors[0]
+		if monitor.MonitorID != 1 || !monitor.Focused || monitor.Hidden != 1 {
+			t.Fatalf("unexpected monitor summary: %s", out)
+		}
+		if monitor.FocusedWindow == nil || monitor.FocusedWindow.WindowID != 3 {
+			t.Fatalf("expected Finder focused, got %s", out)
+		}
+		if len(monitor.Groups) != 1 || monitor.Groups[0].Group != "chat" {
+			t.Fatalf("expected the chat group, got %s", out)
+		}
+	})
+
+	t.Run("renders the format template for each monitor", func(t *testing.T) {
+		setupStatus(t)
+		client, _ := testutils.StartFakeAeroSpace(t, statusWorld)
+
+		out, err := testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"status",
+			"--format", "{{.MonitorID}}:{{.Hidden}}{{with .FocusedWindow}} {{.AppName}}{{end}}",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "1:1 Finder\n2:1\n" {
+			t.Fatalf("unexpected output %q", out)
+		}
+	})
+
+	t.Run("fails on an invalid format", func(t *testing.T) {
+		setupStatus(t)
+		client, _ := testutils.StartFakeAeroSpace(t, statusWorld)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "status", "--format", "{{.Missing}}")
+		if err == nil || !strings.Contains(err.Error(), "invalid --format") {
+			t.Fatalf("expected an invalid format error, got %v", err)
+		}
+	})
+
+	t.Run("prints only the changes while watching", func(t *testing.T) {
+		setupStatus(t)
+		client, _ := testutils.StartFakeAeroSpace(t, statusWorld)
+
+		out, err := testutils.CmdExecute(
+			cmd.RootCmd(client),
+			"status", "--monitor", "2", "--watch", "10ms", "--timeout", "100ms",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "2 DELL U2720Q: 1 hidden, 0 visible\n" {
+			t.Fatalf("expected the summary printed once, got %q", out)
+		}
+	})
+}
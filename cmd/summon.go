@@ -4,11 +4,11 @@ Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
 package cmd
 
 import (
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/cli"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
@@ -28,12 +28,21 @@ func SummonCmd(
 
 This command brings windows matching the regex pattern to the current workspace and focuses them.
 Use "next" to cycle through scratchpad windows without specifying a pattern.
+
+With --workspace, the windows go to that workspace instead, "prev" being the
+workspace focused before the current one. They are focused only with --focus.
+
+With --focus-policy keep, the windows are summoned without the focus.
+
+With --hide-after, the summoned windows go back to the scratchpad once they have
+been without focus for that long. Moving or closing a window cancels it.
 `,
 
 		Args: cobra.MatchAll(
 			cobra.ExactArgs(1),
 			cli.ValidateAllNonEmpty,
 		),
+		ValidArgsFunction: completeAppNames(aerospaceClient),
 
 		Run: func(cmd *cobra.Command, args []string) {
 			logger := logger.GetDefaultLogger()
@@ -45,14 +54,14 @@ Use "next" to cycle through scratchpad windows without specifying a pattern.
 				stderr.Println("Error: unable to get output format")
 				return
 			}
-			formatter, err := cli.NewOutputFormatter(os.Stdout, outputFormat)
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
 			if err != nil {
 				logger.LogError("SUMMON: invalid output format", "error", err)
 				stderr.Println("Error: unsupported output format")
 				return
 			}
 
-			focusedWorkspace, err := aerospaceClient.GetFocusedWorkspace()
+			focusedWorkspace, err := aerospaceClient.GetFocusedWorkspace(cmd.Context())
 			if err != nil {
 				logger.LogError(
 					"SUMMON: unable to get focused workspace",
@@ -63,6 +72,12 @@ Use "next" to cycle through scratchpad windows without specifying a pattern.
 				return
 			}
 
+			target, err := resolveTargetWorkspace(cmd, focusedWorkspace)
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return
+			}
+
 			// Parse filter flags
 			filterFlags, err := cmd.Flags().GetStringArray("filter")
 			if err != nil {
@@ -76,10 +91,11 @@ Use "next" to cycle through scratchpad windows without specifying a pattern.
 			}
 
 			// Filter windows using the shared querier
-			querier := aerospace.NewAerospaceQuerier(aerospaceClient.GetUnderlyingClient())
-			mover := aerospace.NewAeroSpaceMover(aerospaceClient)
+			querier := aerospace.NewAerospaceQuerier(aerospaceClient)
+			mover := newMover(cmd, aerospaceClient)
 
 			windows, err := querier.GetFilteredWindows(
+				cmd.Context(),
 				windowNamePattern,
 				filterFlags,
 			)
@@ -93,12 +109,31 @@ Use "next" to cycle through scratchpad windows without specifying a pattern.
 				return
 			}
 
+			hideAfter, _ := cmd.Flags().GetDuration(hideAfterFlag)
+			var summonedWindows []windowsipc.Window
+			if hideAfter > 0 {
+				currentMonitorID := 0
+				if monitor, monitorErr := aerospace.GetFocusedMonitor(aerospaceClient); monitorErr == nil {
+					currentMonitorID = monitor.MonitorID
+				}
+				if !target.Focused {
+					currentMonitorID = monitorOfWorkspace(
+						cmd.Context(), aerospaceClient, target.Workspace.Workspace, currentMonitorID,
+					)
+				}
+				defer func() {
+					trackAutoHide(cmd, summonedWindows, currentMonitorID, target.Workspace.Workspace, hideAfter)
+				}()
+			}
+
+			rememberFocus(cmd, aerospaceClient, target, windows)
+			markFocusing(cmd, windowIDsOf(windows)...)
 			for _, window := range windows {
-				setFocus := true
 				moveErr := mover.MoveWindowToWorkspace(
+					cmd.Context(),
 					&window,
-					focusedWorkspace,
-					setFocus,
+					target.Workspace,
+					target.Focus,
 				)
 				if moveErr != nil {
 					if strings.Contains(
@@ -110,11 +145,14 @@ Use "next" to cycle through scratchpad windows without specifying a pattern.
 							"window",
 							window,
 							"workspace",
-							focusedWorkspace,
+							target.Workspace,
 							"error",
 							moveErr,
 						)
-						focusErr := aerospaceClient.SetFocusByWindowID(window.WindowID)
+						var focusErr error
+						if target.Focus {
+							focusErr = aerospaceClient.SetFocusByWindowID(cmd.Context(), window.WindowID)
+						}
 						if focusErr != nil {
 							logger.LogError(
 								"SUMMON: unable to set focus to window",
@@ -131,13 +169,14 @@ Use "next" to cycle through scratchpad windows without specifying a pattern.
 							return
 						}
 
+						summonedWindows = append(summonedWindows, window)
 						if printErr := formatter.Print(cli.OutputEvent{
 							Command:         commandSummon,
 							Action:          actionToWorkspace,
 							WindowID:        window.WindowID,
 							AppName:         window.AppName,
 							Workspace:       window.Workspace,
-							TargetWorkspace: focusedWorkspace.Workspace,
+							TargetWorkspace: target.Workspace.Workspace,
 							Result:          "skipped",
 							Message:         "already in target workspace",
 						}); printErr != nil {
@@ -151,7 +190,7 @@ Use "next" to cycle through scratchpad windows without specifying a pattern.
 						"window",
 						window,
 						"workspace",
-						focusedWorkspace,
+						target.Workspace,
 						"error",
 						moveErr,
 					)
@@ -159,13 +198,14 @@ Use "next" to cycle through scratchpad windows without specifying a pattern.
 					return
 				}
 
+				summonedWindows = append(summonedWindows, window)
 				if printErr := formatter.Print(cli.OutputEvent{
 					Command:         commandSummon,
 					Action:          actionToWorkspace,
 					WindowID:        window.WindowID,
 					AppName:         window.AppName,
 					Workspace:       window.Workspace,
-					TargetWorkspace: focusedWorkspace.Workspace,
+					TargetWorkspace: target.Workspace.Workspace,
 					Result:          "ok",
 				}); printErr != nil {
 					logger.LogError("SUMMON: unable to write output", "error", printErr)
@@ -173,5 +213,9 @@ Use "next" to cycle through scratchpad windows without specifying a pattern.
 			}
 		},
 	}
+
+	enableHideAfterFlag(command)
+	enableTargetFlags(command, aerospaceClient)
+
 	return command
 }
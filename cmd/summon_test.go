@@ -67,6 +67,12 @@ func TestSummonCmd(t *testing.T) {
 				Return(allWindows, nil).
 				Times(1),
 
+			// The focused window is remembered to be focused again on hide
+			aerospaceClient.GetWindowsMock().EXPECT().
+				GetFocusedWindow().
+				Return(testutils.ExtractFocusedWindow(tree), nil).
+				Times(1),
+
 			aerospaceClient.GetWorkspacesMock().EXPECT().
 				MoveWindowToWorkspaceWithOpts(
 					workspaces.MoveWindowToWorkspaceArgs{
@@ -330,6 +336,12 @@ func TestSummonCmd(t *testing.T) {
 					Return(allWindows, nil).
 					Times(1),
 
+				// The focused window is remembered to be focused again on hide
+				aerospaceClient.GetWindowsMock().EXPECT().
+					GetFocusedWindow().
+					Return(testutils.ExtractFocusedWindow(tree), nil).
+					Times(1),
+
 				aerospaceClient.GetWorkspacesMock().EXPECT().
 					MoveWindowToWorkspaceWithOpts(
 						workspaces.MoveWindowToWorkspaceArgs{
@@ -401,6 +413,12 @@ func TestSummonCmd(t *testing.T) {
 				Return(allWindows, nil).
 				Times(1),
 
+			// The focused window is remembered to be focused again on hide
+			aerospaceClient.GetWindowsMock().EXPECT().
+				GetFocusedWindow().
+				Return(testutils.ExtractFocusedWindow(tree), nil).
+				Times(1),
+
 			aerospaceClient.GetWorkspacesMock().EXPECT().
 				MoveWindowToWorkspaceWithOpts(
 					workspaces.MoveWindowToWorkspaceArgs{
@@ -489,6 +507,12 @@ func TestSummonCmd(t *testing.T) {
 				Return(allWindows, nil).
 				Times(1),
 
+			// The focused window is remembered to be focused again on hide
+			aerospaceClient.GetWindowsMock().EXPECT().
+				GetFocusedWindow().
+				Return(testutils.ExtractFocusedWindow(tree), nil).
+				Times(1),
+
 			aerospaceClient.GetWorkspacesMock().EXPECT().
 				MoveWindowToWorkspaceWithOpts(
 					workspaces.MoveWindowToWorkspaceArgs{
@@ -693,6 +717,12 @@ func TestSummonCmd(t *testing.T) {
 				Return(allWindows, nil).
 				Times(1),
 
+			// The focused window is remembered to be focused again on hide
+			aerospaceClient.GetWindowsMock().EXPECT().
+				GetFocusedWindow().
+				Return(testutils.ExtractFocusedWindow(tree), nil).
+				Times(1),
+
 			aerospaceClient.GetWorkspacesMock().EXPECT().
 				MoveWindowToWorkspaceWithOpts(
 					workspaces.MoveWindowToWorkspaceArgs{
@@ -0,0 +1,213 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/cli"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+// SwapCmd represents the swap command.
+//
+//nolint:funlen,gocognit // command wiring keeps this function long
+func SwapCmd(
+	aerospaceClient *aerospace.AeroSpaceClient,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "swap <pattern>",
+		Short: "Replace the visible scratchpad window with a matching one",
+		Long: `Replace the visible scratchpad window with a matching one.
+
+The scratchpad windows in the focused workspace are sent back to the
+scratchpad, then the windows matching the pattern are brought in and the
+first one is focused. Same as calling show twice, without the flicker.
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateAllNonEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+			logger.LogDebug("SWAP: start command", "args", args)
+			windowNamePattern := strings.TrimSpace(args[0])
+
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				logger.LogError("SWAP: unable to get output flag", "error", err)
+				stderr.Println("Error: unable to get output format")
+				return
+			}
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
+			if err != nil {
+				logger.LogError("SWAP: invalid output format", "error", err)
+				stderr.Println("Error: unsupported output format")
+				return
+			}
+
+			filterFlags, err := cmd.Flags().GetStringArray("filter")
+			if err != nil {
+				logger.LogError("SWAP: unable to get filter flags", "error", err)
+				stderr.Println("Error: unable to get filter flags")
+				return
+			}
+
+			focusedWorkspace, err := aerospaceClient.GetFocusedWorkspace(cmd.Context())
+			if err != nil {
+				logger.LogError("SWAP: unable to get focused workspace", "error", err)
+				stderr.Println("Error: unable to get focused workspace")
+				return
+			}
+
+			currentMonitorID := 0
+			monitor, err := aerospace.GetFocusedMonitor(aerospaceClient)
+			if err != nil {
+				logger.LogError(
+					"SWAP: unable to get focused monitor, defaulting to 0",
+					"error",
+					err,
+				)
+			} else {
+				currentMonitorID = monitor.MonitorID
+			}
+
+			querier, err := newScratchpadQuerier(cmd, aerospaceClient)
+			if err != nil {
+				logger.LogError("SWAP: invalid scratchpad scope", "error", err)
+				stderr.Println("Error: %v", err)
+				return
+			}
+			mover := newMover(cmd, aerospaceClient)
+
+			matchedWindows, err := querier.GetFilteredWindows(
+				cmd.Context(),
+				windowNamePattern,
+				filterFlags,
+			)
+			if err != nil {
+				logger.LogError("SWAP: unable to get filtered windows", "error", err)
+				stderr.Println("Error: %v", err)
+				return
+			}
+
+			incoming := map[int]bool{}
+			var windowsToShow []windowsipc.Window
+			for _, window := range matchedWindows {
+				incoming[window.WindowID] = true
+				if window.Workspace != focusedWorkspace.Workspace {
+					windowsToShow = append(windowsToShow, window)
+				}
+			}
+
+			scratchpadWindows, err := querier.GetScratchpadWindows(cmd.Context())
+			if err != nil {
+				logger.LogError("SWAP: unable to get scratchpad windows", "error", err)
+				stderr.Println("Error: %v", err)
+				return
+			}
+			var windowsToHide []windowsipc.Window
+			for _, window := range scratchpadWindows {
+				if window.Workspace == focusedWorkspace.Workspace && !incoming[window.WindowID] {
+					windowsToHide = append(windowsToHide, window)
+				}
+			}
+			logger.LogDebug(
+				"SWAP: planned",
+				"windowsToHide", windowsToHide,
+				"windowsToShow", windowsToShow,
+			)
+
+			markFocusing(cmd, windowIDsOf(slices.Concat(windowsToHide, matchedWindows))...)
+			// Hide first, so the new window doesn't land behind the old one.
+			for _, window := range windowsToHide {
+				targetWorkspace, moveErr := mover.MoveWindowToScratchpadForMonitor(
+					cmd.Context(),
+					window,
+					currentMonitorID,
+				)
+				if moveErr != nil {
+					stderr.Printf(
+						"Error: unable to move window '%+v' to scratchpad\n%s",
+						window,
+						moveErr,
+					)
+					return
+				}
+
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command:         commandSwap,
+					Action:          actionToScratchpad,
+					WindowID:        window.WindowID,
+					AppName:         window.AppName,
+					Workspace:       window.Workspace,
+					TargetWorkspace: targetWorkspace,
+					Result:          "ok",
+				}); printErr != nil {
+					logger.LogError("SWAP: unable to write output", "error", printErr)
+				}
+			}
+
+			for _, window := range windowsToShow {
+				moveErr := mover.MoveWindowToWorkspace(
+					cmd.Context(),
+					&window,
+					focusedWorkspace,
+					false,
+				)
+				if moveErr != nil {
+					stderr.Printf(
+						"Error: unable to move window '%+v' to workspace\n%s",
+						window,
+						moveErr,
+					)
+					return
+				}
+
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command:         commandSwap,
+					Action:          actionToWorkspace,
+					WindowID:        window.WindowID,
+					AppName:         window.AppName,
+					Workspace:       window.Workspace,
+					TargetWorkspace: focusedWorkspace.Workspace,
+					Result:          "ok",
+				}); printErr != nil {
+					logger.LogError("SWAP: unable to write output", "error", printErr)
+				}
+			}
+
+			focused := matchedWindows[0]
+			if len(windowsToShow) > 0 {
+				focused = windowsToShow[0]
+			}
+			if err = aerospaceClient.SetFocusByWindowID(cmd.Context(), focused.WindowID); err != nil {
+				stderr.Printf(
+					"Error: unable to set focus to window '%+v'\n%s",
+					focused,
+					err,
+				)
+				return
+			}
+			if printErr := formatter.Print(cli.OutputEvent{
+				Command:   commandSwap,
+				Action:    "focus",
+				WindowID:  focused.WindowID,
+				AppName:   focused.AppName,
+				Workspace: focusedWorkspace.Workspace,
+				Result:    "ok",
+			}); printErr != nil {
+				logger.LogError("SWAP: unable to write output", "error", printErr)
+			}
+		},
+	}
+
+	return command
+}
@@ -0,0 +1,70 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+const swapWorld = `
+workspaces:
+  - workspace: ws1
+    focused-window-id: 3
+windows:
+  - window-id: 1
+    window-title: Code
+    app-name: Editor
+    workspace: ws1
+  - window-id: 2
+    window-title: Terminal
+    app-name: Ghostty
+    workspace: .scratchpad
+    window-layout: floating
+  - window-id: 3
+    window-title: Notes
+    app-name: Notes
+    workspace: ws1
+    window-layout: floating
+`
+
+func TestSwap(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	t.Run("hides the visible scratchpad window and shows the matching one", func(t *testing.T) {
+		worldPath := writeWorldFile(t, swapWorld)
+
+		out, err := testutils.CmdExecute(
+			cmd.RootCmd(nil),
+			"swap", "Ghostty", "--simulate", worldPath,
+		)
+
+		testutils.MatchSnapshot(t, "swap Ghostty --simulate world.yaml", out, err)
+	})
+
+	t.Run("only focuses the window when it is already visible", func(t *testing.T) {
+		worldPath := writeWorldFile(t, swapWorld)
+
+		out, err := testutils.CmdExecute(
+			cmd.RootCmd(nil),
+			"swap", "Notes", "-o", "json", "--simulate", worldPath,
+		)
+
+		testutils.MatchSnapshot(t, "swap Notes -o json --simulate world.yaml", out, err)
+	})
+
+	t.Run("fails when no window matches", func(t *testing.T) {
+		worldPath := writeWorldFile(t, swapWorld)
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(nil),
+			"swap", "Missing", "--simulate", worldPath,
+		)
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+}
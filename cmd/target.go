@@ -0,0 +1,266 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+)
+
+const (
+	workspaceFlag   = "workspace"
+	focusFlag       = "focus"
+	focusPolicyFlag = "focus-policy"
+
+	// focusPolicyRestore focuses the brought windows and, once they are
+	// hidden, the window focused before them.
+	focusPolicyRestore = "restore"
+	// focusPolicyKeep leaves the focus where it is, the windows are brought
+	// without it.
+	focusPolicyKeep = "keep"
+	// focusPolicyNone focuses the brought windows and leaves the focus to
+	// AeroSpace once they are hidden.
+	focusPolicyNone = "none"
+	// previousWorkspace is the --workspace value for the workspace focused
+	// before the current one.
+	previousWorkspace = "prev"
+	prevWorkspaceEnv  = "AEROSPACE_PREV_WORKSPACE"
+)
+
+// targetWorkspace is where summon, show and next bring the windows.
+type targetWorkspace struct {
+	Workspace *workspaces.Workspace
+	// Focused reports whether it is the focused workspace, the default.
+	Focused bool
+	// Focus reports whether the windows brought there get the focus, always
+	// for the focused workspace unless the focus policy keeps it.
+	Focus bool
+	// FocusPolicy is the --focus-policy.
+	FocusPolicy string
+}
+
+// Restores reports whether the focus goes back to the window focused before,
+// once the brought windows are hidden.
+func (t *targetWorkspace) Restores() bool {
+	return t.Focus && t.FocusPolicy == focusPolicyRestore
+}
+
+func enableTargetFlags(
+	command *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+) *cobra.Command {
+	command.Flags().String(
+		workspaceFlag, "",
+		`Bring the windows to this workspace instead of the focused one, "prev" for the previous workspace`,
+	)
+	command.Flags().Bool(
+		focusFlag, false,
+		"Focus the windows brought to --workspace, they are focused without --workspace",
+	)
+	_ = command.RegisterFlagCompletionFunc(workspaceFlag, completeWorkspaces(aerospaceClient))
+	command.Flags().String(
+		focusPolicyFlag, focusPolicyRestore,
+		`"restore" focuses the windows then the previous window once hidden, `+
+			`"keep" leaves the focus where it is, "none" focuses the windows only`,
+	)
+	_ = command.RegisterFlagCompletionFunc(focusPolicyFlag, cobra.FixedCompletions(
+		[]string{focusPolicyRestore, focusPolicyKeep, focusPolicyNone},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+	return command
+}
+
+// resolveTargetWorkspace returns the workspace of --workspace, the focused
+// workspace without it.
+func resolveTargetWorkspace(
+	cmd *cobra.Command,
+	focusedWorkspace *workspaces.Workspace,
+) (*targetWorkspace, error) {
+	focus, _ := cmd.Flags().GetBool(focusFlag)
+	policy, _ := cmd.Flags().GetString(focusPolicyFlag)
+	switch policy {
+	case focusPolicyRestore, focusPolicyNone:
+	case focusPolicyKeep:
+		if focus {
+			return nil, errors.New("--focus can't be used with --focus-policy keep")
+		}
+	default:
+		return nil, fmt.Errorf(
+			"invalid --focus-policy value: %s, expected restore, keep or none",
+			policy,
+		)
+	}
+	keepFocus := policy == focusPolicyKeep
+
+	name, _ := cmd.Flags().GetString(workspaceFlag)
+	if name == "" {
+		return &targetWorkspace{
+			Workspace:   focusedWorkspace,
+			Focused:     true,
+			Focus:       !keepFocus,
+			FocusPolicy: policy,
+		}, nil
+	}
+
+	if name == previousWorkspace {
+		var err error
+		if name, err = resolvePreviousWorkspace(cmd); err != nil {
+			return nil, err
+		}
+	}
+	if aerospace.IsScratchpadWorkspace(name) {
+		return nil, fmt.Errorf("--workspace can't be the scratchpad workspace %s", name)
+	}
+
+	focused := name == focusedWorkspace.Workspace
+	return &targetWorkspace{
+		Workspace:   &workspaces.Workspace{Workspace: name},
+		Focused:     focused,
+		Focus:       (focus || focused) && !keepFocus,
+		FocusPolicy: policy,
+	}, nil
+}
+
+func openFocusReturns() *state.FocusReturns {
+	return state.NewFocusReturns(state.DefaultFocusReturnsPath())
+}
+
+// rememberFocus records the focused window, focused again by restoreFocus once
+// the windows brought with the focus are hidden.
+func rememberFocus(
+	cmd *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+	target *targetWorkspace,
+	windows []windowsipc.Window,
+) {
+	if len(windows) == 0 || !target.Restores() || !persistsState(cmd) {
+		return
+	}
+	log := logger.GetDefaultLogger()
+
+	focusedWindow, err := aerospaceClient.GetFocusedWindow(cmd.Context())
+	if err != nil {
+		// No window may be focused, e.g. on an empty workspace.
+		log.LogDebug("FOCUS: no focused window to remember", "error", err)
+		return
+	}
+
+	returns := make([]state.FocusReturn, 0, len(windows))
+	for _, window := range windows {
+		if window.WindowID == focusedWindow.WindowID {
+			continue
+		}
+		returns = append(returns, state.FocusReturn{
+			WindowID:         window.WindowID,
+			PreviousWindowID: focusedWindow.WindowID,
+		})
+	}
+	if len(returns) == 0 {
+		return
+	}
+	if err = openFocusReturns().Set(returns...); err != nil {
+		log.LogError("FOCUS: unable to remember the focused window", "error", err)
+	}
+}
+
+// restoreFocus focuses the window remembered for the hidden windows, when they
+// had the focus and it is still in the workspace they were hidden from.
+func restoreFocus(
+	cmd *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+	target *targetWorkspace,
+	hidden []windowsipc.Window,
+	hadFocus bool,
+) {
+	if len(hidden) == 0 || !persistsState(cmd) {
+		return
+	}
+	log := logger.GetDefaultLogger()
+
+	hiddenFrom := make(map[int]string, len(hidden))
+	ids := make([]int, 0, len(hidden))
+	for _, window := range hidden {
+		hiddenFrom[window.WindowID] = window.Workspace
+		ids = append(ids, window.WindowID)
+	}
+	// Taken whatever the policy, the returns of hidden windows are stale.
+	returns, err := openFocusReturns().Take(ids...)
+	if err != nil {
+		log.LogError("FOCUS: unable to read the focus returns", "error", err)
+		return
+	}
+	if len(returns) == 0 || !hadFocus || target.FocusPolicy != focusPolicyRestore {
+		return
+	}
+
+	allWindows, err := aerospaceClient.GetAllWindows(cmd.Context())
+	if err != nil {
+		log.LogError("FOCUS: unable to get windows", "error", err)
+		return
+	}
+	for _, focusReturn := range returns {
+		for _, window := range allWindows {
+			if window.WindowID != focusReturn.PreviousWindowID ||
+				window.Workspace != hiddenFrom[focusReturn.WindowID] {
+				continue
+			}
+			markFocusing(cmd, window.WindowID)
+			if err = aerospaceClient.SetFocusByWindowID(cmd.Context(), window.WindowID); err != nil {
+				log.LogError("FOCUS: unable to restore the focus", "window", window, "error", err)
+			}
+			return
+		}
+	}
+	log.LogDebug("FOCUS: no previous window left to focus", "returns", returns)
+}
+
+// resolvePreviousWorkspace returns the workspace AeroSpace reports as the
+// previous one to its callbacks, or the one remembered by hook pull-window.
+func resolvePreviousWorkspace(cmd *cobra.Command) (string, error) {
+	if workspace := getenv(cmd, prevWorkspaceEnv); workspace != "" {
+		return workspace, nil
+	}
+
+	workspace, err := state.ReadPreviousWorkspace()
+	if err != nil {
+		return "", err
+	}
+	if workspace == "" {
+		return "", errors.New(
+			"unknown previous workspace, it is remembered by the pull-window hook (see hook install)",
+		)
+	}
+	return workspace, nil
+}
+
+// monitorOfWorkspace returns the monitor of workspace, fallback when it
+// can't be told.
+func monitorOfWorkspace(
+	ctx context.Context,
+	aerospaceClient *aerospace.AeroSpaceClient,
+	workspace string,
+	fallback int,
+) int {
+	workspacesWithMonitors, err := aerospace.ListWorkspacesWithMonitors(
+		aerospace.WithContext(ctx, aerospaceClient),
+	)
+	if err != nil {
+		return fallback
+	}
+	for _, current := range workspacesWithMonitors {
+		if current.Workspace == workspace {
+			return current.MonitorID
+		}
+	}
+	return fallback
+}
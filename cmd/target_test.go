@@ -0,0 +1,327 @@
+package cmd_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+const targetWorld = `
+focused-workspace: ws1
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+  - workspace: ws2
+windows:
+  - window-id: 1
+    window-title: Terminal
+    app-name: Ghostty
+    workspace: ws1
+  - window-id: 2
+    window-title: Finder
+    app-name: Finder
+    workspace: .scratchpad
+    window-layout: floating
+  - window-id: 3
+    window-title: Notes
+    app-name: Notes
+    workspace: .scratchpad
+    window-layout: floating
+`
+
+func windowWorkspace(t *testing.T, world *fakeaerospace.World, windowID int) string {
+	t.Helper()
+
+	for _, window := range world.Snapshot().Windows {
+		if window.WindowID == windowID {
+			return window.Workspace
+		}
+	}
+	t.Fatalf("window %d not found", windowID)
+	return ""
+}
+
+func TestWorkspaceFlag(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	t.Run("summons to another workspace keeping the focus", func(t *testing.T) {
+		client, world := testutils.StartFakeAeroSpace(t, targetWorld)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "summon", "Finder", "--workspace", "ws2")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := windowWorkspace(t, world, 2); got != "ws2" {
+			t.Fatalf("expected Finder in ws2, got %q", got)
+		}
+		if got := world.Snapshot().FocusedWorkspace; got != "ws1" {
+			t.Fatalf("expected the focus to stay in ws1, got %q", got)
+		}
+	})
+
+	t.Run("summons to another workspace with the focus", func(t *testing.T) {
+		client, world := testutils.StartFakeAeroSpace(t, targetWorld)
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client), "summon", "Finder", "--workspace", "ws2", "--focus",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := world.Snapshot().FocusedWorkspace; got != "ws2" {
+			t.Fatalf("expected the focus to follow to ws2, got %q", got)
+		}
+	})
+
+	t.Run("next goes to the previous workspace", func(t *testing.T) {
+		t.Setenv("AEROSPACE_PREV_WORKSPACE", "ws2")
+		client, world := testutils.StartFakeAeroSpace(t, targetWorld)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "next", "--workspace", "prev")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		moved := 0
+		for _, window := range world.Snapshot().Windows {
+			if window.Workspace == "ws2" {
+				moved++
+			}
+		}
+		if moved != 1 {
+			t.Fatalf("expected one window in ws2, got %d", moved)
+		}
+		if got := world.Snapshot().FocusedWorkspace; got != "ws1" {
+			t.Fatalf("expected the focus to stay in ws1, got %q", got)
+		}
+	})
+
+	t.Run("uses the previous workspace remembered by the pull-window hook", func(t *testing.T) {
+		if err := state.WritePreviousWorkspace("ws2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = os.Remove(state.PreviousWorkspacePath()) })
+		client, world := testutils.StartFakeAeroSpace(t, targetWorld)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "summon", "Notes", "--workspace", "prev")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := windowWorkspace(t, world, 3); got != "ws2" {
+			t.Fatalf("expected Notes in ws2, got %q", got)
+		}
+	})
+
+	t.Run("fails without a previous workspace", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, targetWorld)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "summon", "Notes", "--workspace", "prev")
+		if err == nil || !strings.Contains(err.Error(), "unknown previous workspace") {
+			t.Fatalf("expected an unknown previous workspace error, got %v", err)
+		}
+	})
+
+	t.Run("rejects the scratchpad workspace", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, targetWorld)
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client), "summon", "Notes", "--workspace", ".scratchpad",
+		)
+		if err == nil || !strings.Contains(err.Error(), "scratchpad workspace") {
+			t.Fatalf("expected a scratchpad workspace error, got %v", err)
+		}
+	})
+
+	t.Run("show toggles the windows in the named workspace", func(t *testing.T) {
+		client, world := testutils.StartFakeAeroSpace(t, targetWorld)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "show", "Finder", "--workspace", "ws2")
+		if err != nil {
+			t.Fatalf("unexpected error showing: %v", err)
+		}
+		if got := windowWorkspace(t, world, 2); got != "ws2" {
+			t.Fatalf("expected Finder in ws2, got %q", got)
+		}
+		if got := world.Snapshot().FocusedWorkspace; got != "ws1" {
+			t.Fatalf("expected the focus to stay in ws1, got %q", got)
+		}
+
+		_, err = testutils.CmdExecute(cmd.RootCmd(client), "show", "Finder", "--workspace", "ws2")
+		if err != nil {
+			t.Fatalf("unexpected error hiding: %v", err)
+		}
+		if got := windowWorkspace(t, world, 2); got != ".scratchpad" {
+			t.Fatalf("expected Finder back in the scratchpad, got %q", got)
+		}
+	})
+
+	t.Run("show with focus focuses the windows in the named workspace", func(t *testing.T) {
+		client, world := testutils.StartFakeAeroSpace(t, targetWorld)
+		world.Execute([]string{"move-node-to-workspace", "ws2", "--window-id", "2"})
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client), "show", "Finder", "--workspace", "ws2", "--focus",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := windowWorkspace(t, world, 2); got != "ws2" {
+			t.Fatalf("expected Finder to stay in ws2, got %q", got)
+		}
+		if got := world.Snapshot().FocusedWorkspace; got != "ws2" {
+			t.Fatalf("expected the focus in ws2, got %q", got)
+		}
+	})
+}
+
+const focusPolicyWorld = `
+focused-workspace: ws1
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+windows:
+  - window-id: 1
+    window-title: Terminal
+    app-name: Ghostty
+    workspace: ws1
+  - window-id: 2
+    window-title: Finder
+    app-name: Finder
+    workspace: .scratchpad
+    window-layout: floating
+  - window-id: 4
+    window-title: Browser
+    app-name: Firefox
+    workspace: ws1
+`
+
+func focusedWindowID(world *fakeaerospace.World) int {
+	snapshot := world.Snapshot()
+	for _, workspace := range snapshot.Workspaces {
+		if workspace.Workspace == snapshot.FocusedWorkspace {
+			return workspace.FocusedWindowID
+		}
+	}
+	return 0
+}
+
+func TestFocusPolicy(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	t.Run("show restores the focus once the window is hidden", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, world := testutils.StartFakeAeroSpace(t, focusPolicyWorld)
+
+		_, err := testutils.CmdExecute(cmd.RootCmd(client), "show", "Finder")
+		if err != nil {
+			t.Fatalf("unexpected error showing: %v", err)
+		}
+		if got := focusedWindowID(world); got != 2 {
+			t.Fatalf("expected Finder focused, got window %d", got)
+		}
+
+		_, err = testutils.CmdExecute(cmd.RootCmd(client), "show", "Finder")
+		if err != nil {
+			t.Fatalf("unexpected error hiding: %v", err)
+		}
+		if got := windowWorkspace(t, world, 2); got != ".scratchpad" {
+			t.Fatalf("expected Finder back in the scratchpad, got %q", got)
+		}
+		if got := focusedWindowID(world); got != 1 {
+			t.Fatalf("expected the focus back on Terminal, got window %d", got)
+		}
+	})
+
+	t.Run("none leaves the focus to AeroSpace once hidden", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, world := testutils.StartFakeAeroSpace(t, focusPolicyWorld)
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client), "show", "Finder", "--focus-policy", "none",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error showing: %v", err)
+		}
+		_, err = testutils.CmdExecute(
+			cmd.RootCmd(client), "show", "Finder", "--focus-policy", "none",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error hiding: %v", err)
+		}
+		if got := focusedWindowID(world); got != 4 {
+			t.Fatalf("expected AeroSpace to focus Browser, got window %d", got)
+		}
+	})
+
+	t.Run("summon with keep leaves the focus where it is", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, world := testutils.StartFakeAeroSpace(t, focusPolicyWorld)
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client), "summon", "Finder", "--focus-policy", "keep",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := windowWorkspace(t, world, 2); got != "ws1" {
+			t.Fatalf("expected Finder in ws1, got %q", got)
+		}
+		if got := focusedWindowID(world); got != 1 {
+			t.Fatalf("expected the focus to stay on Terminal, got window %d", got)
+		}
+	})
+
+	t.Run("next with keep leaves the focus where it is", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, world := testutils.StartFakeAeroSpace(t, focusPolicyWorld)
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client), "next", "--monitor", "all", "--focus-policy", "keep",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := windowWorkspace(t, world, 2); got != "ws1" {
+			t.Fatalf("expected Finder in ws1, got %q", got)
+		}
+		if got := focusedWindowID(world); got != 1 {
+			t.Fatalf("expected the focus to stay on Terminal, got window %d", got)
+		}
+	})
+
+	t.Run("rejects --focus with keep", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, focusPolicyWorld)
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client), "summon", "Finder",
+			"--workspace", "ws2", "--focus", "--focus-policy", "keep",
+		)
+		if err == nil || !strings.Contains(err.Error(), "--focus-policy keep") {
+			t.Fatalf("expected a --focus-policy keep error, got %v", err)
+		}
+	})
+
+	t.Run("rejects an unknown policy", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, focusPolicyWorld)
+
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(client), "show", "Finder", "--focus-policy", "always",
+		)
+		if err == nil || !strings.Contains(err.Error(), "invalid --focus-policy") {
+			t.Fatalf("expected an invalid --focus-policy error, got %v", err)
+		}
+	})
+}
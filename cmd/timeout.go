@@ -0,0 +1,53 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+const timeoutFlag = "timeout"
+
+func enableTimeoutFlag(command *cobra.Command) *cobra.Command {
+	command.PersistentFlags().Duration(
+		timeoutFlag, 0,
+		`Overall deadline for the command, e.g. 2s (default: no deadline).
+Exits with code 124 when AeroSpace doesn't answer in time`,
+	)
+	return command
+}
+
+// setupTimeout bounds the whole command run to --timeout. Errors printed
+// after the deadline exit with aerospace.ExitCodeTimeout instead of 1.
+// The returned function releases the deadline.
+func setupTimeout(
+	cmd *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+) context.CancelFunc {
+	timeout, _ := cmd.Flags().GetDuration(timeoutFlag)
+	if timeout <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithTimeoutCause(cmd.Context(), timeout, aerospace.ErrTimeout)
+	cmd.SetContext(ctx)
+	aerospaceClient.SetContext(ctx)
+	stderr.SetExitCodeFunc(func() int {
+		if errors.Is(context.Cause(ctx), aerospace.ErrTimeout) {
+			return aerospace.ExitCodeTimeout
+		}
+		return 1
+	})
+
+	return func() {
+		cancel()
+		stderr.SetExitCodeFunc(nil)
+	}
+}
@@ -0,0 +1,49 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+func TestTimeout(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	t.Run("reports a timeout when AeroSpace does not answer", func(t *testing.T) {
+		conn := testutils.StartUnresponsiveAeroSpace(t)
+
+		startedAt := time.Now()
+		_, err := testutils.CmdExecute(
+			cmd.RootCmd(aerospace.NewClientFromConnection(conn)),
+			"list", "--timeout", "50ms",
+		)
+		if err == nil || !strings.Contains(err.Error(), "timed out waiting for AeroSpace") {
+			t.Fatalf("expected a timeout error, got %v", err)
+		}
+		if elapsed := time.Since(startedAt); elapsed > time.Second {
+			t.Fatalf("expected to give up at the deadline, took %s", elapsed)
+		}
+	})
+
+	t.Run("does not affect commands answered in time", func(t *testing.T) {
+		worldPath := writeWorldFile(t, fakeWorld)
+
+		out, err := testutils.CmdExecute(
+			cmd.RootCmd(nil),
+			"list", "--timeout", "5s", "--simulate", worldPath, "-o", "json",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out, "Finder") {
+			t.Fatalf("expected Finder in the output, got %s", out)
+		}
+	})
+}
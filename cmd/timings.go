@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+)
+
+const timingsFlag = "timings"
+
+func enableTimingsFlag(command *cobra.Command) *cobra.Command {
+	command.PersistentFlags().Bool(
+		timingsFlag, false,
+		"Print each request sent to AeroSpace, its arguments and its latency to stderr",
+	)
+	return command
+}
+
+// timingsReport prints the per-call timings and the total of a command run.
+type timingsReport struct {
+	out       io.Writer
+	startedAt time.Time
+	requests  atomic.Int64
+}
+
+// setupTimings hooks the timings interceptor into the client when --timings
+// is set. It returns nil otherwise.
+func setupTimings(
+	cmd *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+) *timingsReport {
+	enabled, err := cmd.Flags().GetBool(timingsFlag)
+	if err != nil || !enabled {
+		return nil
+	}
+
+	report := &timingsReport{
+		out:       cmd.ErrOrStderr(),
+		startedAt: time.Now(),
+	}
+	aerospaceClient.Use(
+		report.count,
+		aerospace.TimingInterceptor(report.out),
+	)
+
+	return report
+}
+
+func (r *timingsReport) count(next aerospace.SendFunc) aerospace.SendFunc {
+	return func(command string, args []string) (*client.Response, error) {
+		r.requests.Add(1)
+		return next(command, args)
+	}
+}
+
+// printTotal prints how long the whole command took.
+func (r *timingsReport) printTotal() {
+	fmt.Fprintf(
+		r.out,
+		"[timings] %8.3fms total (%d requests)\n",
+		float64(time.Since(r.startedAt))/float64(time.Millisecond),
+		r.requests.Load(),
+	)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/cli"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+const floatingLayout = "floating"
+
+// UndoCmd represents the undo command.
+func UndoCmd(aerospaceClient *aerospace.AeroSpaceClient) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "undo [N]",
+		Short: "Revert the last N window moves (default 1)",
+		Long: `Revert the last N window moves recorded in the journal (default 1).
+
+Each window is moved back to the workspace it came from and gets its previous
+layout (tiling or floating) back. Windows that were closed since then are
+skipped and don't count.
+
+See also: history
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+
+			outputFormat, _ := cmd.Flags().GetString("output")
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
+			if err != nil {
+				stderr.Println("Error: unsupported output format")
+				return
+			}
+
+			count := 1
+			if len(args) > 0 {
+				count, err = strconv.Atoi(args[0])
+				if err != nil || count <= 0 {
+					stderr.Println("Error: N must be a positive number, got %q", args[0])
+					return
+				}
+			}
+
+			journal := openJournal()
+			entries, err := journal.Entries()
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return
+			}
+			if len(entries) == 0 {
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command: commandUndo,
+					Action:  "undo",
+					Result:  "none",
+					Message: "nothing to undo",
+				}); printErr != nil {
+					logger.LogError("UNDO: unable to write output", "error", printErr)
+				}
+				return
+			}
+
+			allWindows, err := aerospaceClient.GetAllWindows(cmd.Context())
+			if err != nil {
+				stderr.Println("Error: unable to get windows\n%s", err)
+				return
+			}
+			windowsByID := map[int]windowsipc.Window{}
+			for _, window := range allWindows {
+				windowsByID[window.WindowID] = window
+			}
+
+			var undoErr error
+			var reverted []state.JournalEntry
+			undone := 0
+			for undone < count && len(entries) > 0 {
+				entry := entries[len(entries)-1]
+				window, exists := windowsByID[entry.WindowID]
+				if !exists || entry.FromWorkspace == "" {
+					entries = entries[:len(entries)-1]
+					reverted = append(reverted, entry)
+					message := "window no longer exists"
+					if exists {
+						message = "original workspace unknown"
+					}
+					if printErr := formatter.Print(cli.OutputEvent{
+						Command:         commandUndo,
+						Action:          actionToWorkspace,
+						WindowID:        entry.WindowID,
+						AppName:         entry.AppName,
+						TargetWorkspace: entry.FromWorkspace,
+						Result:          "skipped",
+						Message:         message,
+					}); printErr != nil {
+						logger.LogError("UNDO: unable to write output", "error", printErr)
+					}
+					continue
+				}
+
+				markFocusing(cmd, window.WindowID)
+				if undoErr = revertOperation(cmd.Context(), aerospaceClient, window, entry); undoErr != nil {
+					break
+				}
+				entries = entries[:len(entries)-1]
+				reverted = append(reverted, entry)
+				undone++
+
+				window.Workspace = entry.FromWorkspace
+				windowsByID[window.WindowID] = window
+				if printErr := formatter.Print(cli.OutputEvent{
+					Command:         commandUndo,
+					Action:          actionToWorkspace,
+					WindowID:        window.WindowID,
+					AppName:         window.AppName,
+					Workspace:       entry.ToWorkspace,
+					TargetWorkspace: entry.FromWorkspace,
+					Result:          "ok",
+				}); printErr != nil {
+					logger.LogError("UNDO: unable to write output", "error", printErr)
+				}
+			}
+
+			if persistsState(cmd) {
+				if err = journal.Remove(reverted...); err != nil {
+					logger.LogError("UNDO: unable to update journal", "error", err)
+				}
+			}
+			if undoErr != nil {
+				stderr.Println("Error: %v", undoErr)
+			}
+		},
+	}
+
+	return command
+}
+
+// revertOperation moves the window back where the journal entry says it was.
+func revertOperation(
+	ctx context.Context,
+	aerospaceClient *aerospace.AeroSpaceClient,
+	window windowsipc.Window,
+	entry state.JournalEntry,
+) error {
+	if window.Workspace != entry.FromWorkspace {
+		if err := aerospaceClient.MoveWindowToWorkspace(
+			ctx,
+			window.WindowID,
+			entry.FromWorkspace,
+		); err != nil {
+			return fmt.Errorf(
+				"unable to move window '%d' back to workspace '%s': %w",
+				window.WindowID,
+				entry.FromWorkspace,
+				err,
+			)
+		}
+	}
+
+	previousLayout := layoutKind(entry.PreviousLayout)
+	if previousLayout == "" || previousLayout == layoutKind(window.WindowLayout) {
+		return nil
+	}
+	if err := aerospaceClient.SetLayout(ctx, window.WindowID, previousLayout); err != nil {
+		return fmt.Errorf(
+			"unable to restore layout '%s' of window '%d': %w",
+			previousLayout,
+			window.WindowID,
+			err,
+		)
+	}
+	return nil
+}
+
+// layoutKind tells floating windows from the tiled ones, whatever the
+// container layout is (tiles, accordion).
+func layoutKind(windowLayout string) string {
+	switch windowLayout {
+	case "":
+		return ""
+	case floatingLayout:
+		return floatingLayout
+	default:
+		return "tiling"
+	}
+}
@@ -0,0 +1,94 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+func TestHistoryAndUndo(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	t.Run("records the moves and reverts the last ones", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, world := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		for _, args := range [][]string{{"summon", "Finder"}, {"move", "Ghostty"}} {
+			if _, err := testutils.CmdExecute(cmd.RootCmd(client), args...); err != nil {
+				t.Fatalf("unexpected error running %v: %v", args, err)
+			}
+		}
+
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "history", "-o", "tsv")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 3 ||
+			!strings.Contains(lines[1], "summon") ||
+			!strings.Contains(lines[2], "move") {
+			t.Fatalf("unexpected history:\n%s", out)
+		}
+
+		out, err = testutils.CmdExecute(cmd.RootCmd(client), "undo", "2")
+		testutils.MatchSnapshot(t, "undo 2", out, err)
+
+		for _, window := range world.Snapshot().Windows {
+			switch window.WindowID {
+			case 1:
+				if window.Workspace != "ws1" {
+					t.Fatalf("expected Ghostty back in ws1, got %q", window.Workspace)
+				}
+			case 2:
+				if window.Workspace != ".scratchpad" {
+					t.Fatalf("expected Finder back in .scratchpad, got %q", window.Workspace)
+				}
+			}
+		}
+
+		out, err = testutils.CmdExecute(cmd.RootCmd(client), "history")
+		if err != nil || !strings.Contains(out, "no operations recorded") {
+			t.Fatalf("expected an empty history, got %q %v", out, err)
+		}
+	})
+
+	t.Run("skips the windows that no longer exist", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "summon", "Finder"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		otherClient, _ := testutils.StartFakeAeroSpace(t, `
+workspaces:
+  - workspace: ws1
+windows:
+  - window-id: 1
+    app-name: Ghostty
+    workspace: ws1
+`)
+		out, err := testutils.CmdExecute(cmd.RootCmd(otherClient), "undo")
+		testutils.MatchSnapshot(t, "undo with closed window", out, err)
+	})
+
+	t.Run("does not record dry runs", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceScratchpadStateDir, t.TempDir())
+		client, _ := testutils.StartFakeAeroSpace(t, fakeWorld)
+
+		if _, err := testutils.CmdExecute(cmd.RootCmd(client), "summon", "Finder", "--dry-run"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		out, err := testutils.CmdExecute(cmd.RootCmd(client), "undo")
+		if err != nil || !strings.Contains(out, "nothing to undo") {
+			t.Fatalf("expected nothing to undo, got %q %v", out, err)
+		}
+	})
+}
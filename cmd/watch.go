@@ -0,0 +1,359 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	windowsipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/cli"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/daemon"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+)
+
+const (
+	watchIntervalFlag = "interval"
+	// watchSnapshotFlag prints a single snapshot, it is how watch asks the
+	// daemon for the state of AeroSpace.
+	watchSnapshotFlag = "snapshot"
+
+	defaultWatchInterval = 500 * time.Millisecond
+)
+
+// watchSnapshot is the state of AeroSpace compared by watch between polls.
+type watchSnapshot struct {
+	// Windows are the scratchpad windows.
+	Windows []windowsipc.Window `json:"windows"`
+	// Workspaces has the workspace of every window, to tell a closed window
+	// from one that is no longer a scratchpad window.
+	Workspaces      map[int]string `json:"workspaces"`
+	FocusedWindowID int            `json:"focused_window_id"`
+}
+
+// WatchCmd represents the watch command.
+//
+//nolint:funlen,gocognit // command wiring and the poll loop keep this function long
+func WatchCmd(aerospaceClient *aerospace.AeroSpaceClient) *cobra.Command {
+	command := &cobra.Command{
+		Use:   commandWatch,
+		Short: "Print an event whenever a scratchpad window changes",
+		Long: `Print an event whenever a scratchpad window changes, until interrupted.
+
+It checks AeroSpace every interval, through the daemon when one is running, and
+prints one event per change:
+  added    a window became a scratchpad window, e.g. a new floating window
+  removed  a scratchpad window was closed or is no longer a scratchpad window
+  shown    a window left the scratchpad workspace
+  hidden   a window went to the scratchpad workspace
+  moved    a scratchpad window moved between other workspaces
+  focused  a scratchpad window got the focus
+
+The event workspace is where the window was before the change and the target
+workspace where it is after. While AeroSpace restarts the checks fail and are
+retried, no event is printed for them.
+
+Example:
+  aerospace-scratchpad watch | jq -c 'select(.action == "shown")'
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := logger.GetDefaultLogger()
+
+			monitorFlag, _ := cmd.Flags().GetString("monitor")
+			monitorID, err := parseMonitorFlag(cmd)
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return nil
+			}
+
+			if _, err = scratchpadScope(cmd); err != nil {
+				stderr.Println("Error: %v", err)
+				return nil
+			}
+			scope, _ := cmd.Flags().GetString(scopeFlag)
+
+			if snapshotOnly, _ := cmd.Flags().GetBool(watchSnapshotFlag); snapshotOnly {
+				snapshot, snapshotErr := takeWatchSnapshot(cmd.Context(), cmd, aerospaceClient, monitorID)
+				if snapshotErr != nil {
+					stderr.Println("Error: unable to read the windows: %v", snapshotErr)
+					return nil
+				}
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(snapshot)
+			}
+
+			outputFormat, _ := cmd.Flags().GetString("output")
+			formatter, err := cli.NewOutputFormatter(cmd.OutOrStdout(), outputFormat)
+			if err != nil {
+				stderr.Println("Error: unsupported output format")
+				return nil
+			}
+
+			filterFlags, _ := cmd.Flags().GetStringArray("filter")
+			filters, err := aerospace.ParseFilters(filterFlags)
+			if err != nil {
+				stderr.Println("Error: %v", err)
+				return nil
+			}
+
+			interval, _ := cmd.Flags().GetDuration(watchIntervalFlag)
+			if interval <= 0 {
+				stderr.Println("Error: --interval must be positive")
+				return nil
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			poll := func() (watchSnapshot, error) {
+				snapshot, daemonErr := watchSnapshotFromDaemon(monitorFlag, scope)
+				if daemonErr == nil {
+					return snapshot, nil
+				}
+				if !errors.Is(daemonErr, daemon.ErrNotRunning) {
+					logger.LogDebug("WATCH: daemon snapshot failed", "error", daemonErr)
+				}
+				return takeWatchSnapshot(ctx, cmd, aerospaceClient, monitorID)
+			}
+
+			var last *watchSnapshot
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				snapshot, pollErr := poll()
+				switch {
+				case pollErr != nil:
+					// AeroSpace may be restarting, keep the last snapshot so
+					// its windows don't come back as added.
+					logger.LogError("WATCH: unable to read the windows", "error", pollErr)
+				case last == nil:
+					last = &snapshot
+				default:
+					for _, event := range diffWatchSnapshots(*last, snapshot) {
+						if !watchEventMatches(event, *last, snapshot, filters) {
+							continue
+						}
+						if printErr := formatter.Print(event); printErr != nil {
+							return printErr
+						}
+					}
+					last = &snapshot
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	command.Flags().StringP(
+		"output", "o", "json", "Output format: text|json|tsv|csv",
+	)
+	_ = command.RegisterFlagCompletionFunc("output", completeOutputFormats)
+	command.Flags().StringP(
+		"monitor", "m", "all",
+		`Monitor filter: "all" (default) for all monitors, "current" for current monitor, or a monitor ID (e.g., 1)`,
+	)
+	command.Flags().Duration(
+		watchIntervalFlag, defaultWatchInterval,
+		"How often AeroSpace is checked",
+	)
+	command.Flags().Bool(watchSnapshotFlag, false, "Print the windows compared by watch once")
+	_ = command.Flags().MarkHidden(watchSnapshotFlag)
+
+	return command
+}
+
+// takeWatchSnapshot reads the scratchpad windows of the monitors matching
+// monitorID, as parsed by parseMonitorFlag.
+func takeWatchSnapshot(
+	ctx context.Context,
+	cmd *cobra.Command,
+	aerospaceClient *aerospace.AeroSpaceClient,
+	monitorID int,
+) (watchSnapshot, error) {
+	wm := aerospace.WithContext(ctx, aerospaceClient)
+
+	// Resolved on every poll, strict windows come and go while watching.
+	querier, err := newScratchpadQuerier(cmd, aerospaceClient)
+	if err != nil {
+		return watchSnapshot{}, err
+	}
+	scratchpadWindows, err := querier.GetScratchpadWindowsForMonitor(ctx, monitorID)
+	if err != nil {
+		return watchSnapshot{}, err
+	}
+
+	allWindows, err := wm.Windows().GetAllWindows()
+	if err != nil {
+		return watchSnapshot{}, fmt.Errorf("unable to get windows: %w", err)
+	}
+
+	snapshot := watchSnapshot{
+		Windows:    scratchpadWindows,
+		Workspaces: make(map[int]string, len(allWindows)),
+	}
+	for _, window := range allWindows {
+		snapshot.Workspaces[window.WindowID] = window.Workspace
+	}
+	// No window may be focused, e.g. on an empty workspace.
+	if focusedWindow, focusErr := wm.Windows().GetFocusedWindow(); focusErr == nil {
+		snapshot.FocusedWindowID = focusedWindow.WindowID
+	}
+
+	return snapshot, nil
+}
+
+// watchSnapshotFromDaemon takes the snapshot in the daemon, which keeps the
+// connection to AeroSpace. It returns daemon.ErrNotRunning without one.
+func watchSnapshotFromDaemon(monitorFlag, scope string) (watchSnapshot, error) {
+	var snapshot watchSnapshot
+
+	args := []string{commandWatch, "--" + watchSnapshotFlag, "--monitor", monitorFlag}
+	if scope != "" {
+		args = append(args, "--"+scopeFlag, scope)
+	}
+	if !shouldForward(args) {
+		return snapshot, daemon.ErrNotRunning
+	}
+
+	cwd, _ := os.Getwd()
+	response, err := daemon.Send(daemon.DefaultSocketPath(), daemon.Request{
+		Version: VERSION,
+		Args:    args,
+		Cwd:     cwd,
+		Env:     aerospaceEnv(),
+	})
+	if err != nil {
+		return snapshot, err
+	}
+	if response.ExitCode != 0 {
+		return snapshot, fmt.Errorf("daemon snapshot failed: %s", response.Stderr)
+	}
+
+	if err = json.Unmarshal([]byte(response.Stdout), &snapshot); err != nil {
+		return snapshot, fmt.Errorf("unable to parse the daemon snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// diffWatchSnapshots returns the events that lead from before to after.
+func diffWatchSnapshots(before, after watchSnapshot) []cli.OutputEvent {
+	beforeWindows := make(map[int]windowsipc.Window, len(before.Windows))
+	for _, window := range before.Windows {
+		beforeWindows[window.WindowID] = window
+	}
+	afterWindows := make(map[int]windowsipc.Window, len(after.Windows))
+	for _, window := range after.Windows {
+		afterWindows[window.WindowID] = window
+	}
+
+	var events []cli.OutputEvent
+	newEvent := func(action string, window windowsipc.Window, from, to string) {
+		events = append(events, cli.OutputEvent{
+			Command:         commandWatch,
+			Action:          action,
+			WindowID:        window.WindowID,
+			AppName:         window.AppName,
+			Workspace:       from,
+			TargetWorkspace: to,
+			Result:          "ok",
+		})
+	}
+
+	for _, window := range after.Windows {
+		previous, wasScratchpad := beforeWindows[window.WindowID]
+		from := previous.Workspace
+		if !wasScratchpad {
+			from = before.Workspaces[window.WindowID]
+		}
+		if wasScratchpad && from == window.Workspace {
+			continue
+		}
+
+		action := workspaceChangeAction(from, window.Workspace)
+		if !wasScratchpad && action != actionHidden {
+			action = actionAdded
+		}
+		newEvent(action, window, from, window.Workspace)
+	}
+
+	removed := make([]windowsipc.Window, 0)
+	for _, window := range before.Windows {
+		if _, ok := afterWindows[window.WindowID]; !ok {
+			removed = append(removed, window)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		return removed[i].WindowID < removed[j].WindowID
+	})
+	for _, window := range removed {
+		to, exists := after.Workspaces[window.WindowID]
+		action := actionRemoved
+		// A tiling window shown from the scratchpad is no longer a
+		// scratchpad window, but it was shown rather than removed.
+		if exists && workspaceChangeAction(window.Workspace, to) == actionShown {
+			action = actionShown
+		}
+		newEvent(action, window, window.Workspace, to)
+	}
+
+	if after.FocusedWindowID != before.FocusedWindowID {
+		if window, ok := afterWindows[after.FocusedWindowID]; ok {
+			newEvent(actionFocused, window, window.Workspace, window.Workspace)
+		}
+	}
+
+	return events
+}
+
+// workspaceChangeAction names the move of a window between workspaces.
+func workspaceChangeAction(from, to string) string {
+	fromScratchpad := aerospace.IsScratchpadWorkspace(from)
+	toScratchpad := aerospace.IsScratchpadWorkspace(to)
+	switch {
+	case from != "" && !fromScratchpad && toScratchpad:
+		return actionHidden
+	case fromScratchpad && to != "" && !toScratchpad:
+		return actionShown
+	default:
+		return actionMoved
+	}
+}
+
+// watchEventMatches applies the --filter flags to the window of the event.
+func watchEventMatches(
+	event cli.OutputEvent,
+	before, after watchSnapshot,
+	filters []aerospace.Filter,
+) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	for _, snapshot := range []watchSnapshot{after, before} {
+		for _, window := range snapshot.Windows {
+			if window.WindowID != event.WindowID {
+				continue
+			}
+			matches, err := aerospace.ApplyFilters(window, filters)
+			return err == nil && matches
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+type watchEvent struct {
+	Action          string `json:"action"`
+	WindowID        int    `json:"window_id"`
+	Workspace       string `json:"workspace"`
+	TargetWorkspace string `json:"target_workspace"`
+}
+
+// runWatch runs watch while changes are applied to the world, one at a time
+// so every poll sees them, and returns the printed events.
+func runWatch(
+	t *testing.T,
+	client aerospace.AeroSpaceWMClient,
+	world *fakeaerospace.World,
+	changes [][]string,
+	args ...string,
+) []watchEvent {
+	t.Helper()
+
+	go func() {
+		for _, change := range changes {
+			time.Sleep(60 * time.Millisecond)
+			if response := world.Execute(change); response.ExitCode != 0 {
+				t.Errorf("unable to apply %v: %s", change, response.StdErr)
+			}
+		}
+	}()
+
+	args = append([]string{"watch", "--interval", "10ms", "--timeout", "400ms"}, args...)
+	out, err := testutils.CmdExecute(cmd.RootCmd(client), args...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var events []watchEvent
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		var event watchEvent
+		if err = json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("expected NDJSON, got %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestWatchCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	stderr.SetBehavior(false)
+
+	t.Run("prints the changes of the scratchpad windows", func(t *testing.T) {
+		world, err := fakeaerospace.ParseWorld([]byte(fakeWorld))
+		if err != nil {
+			t.Fatalf("unable to parse world: %v", err)
+		}
+		client := aerospace.NewClientFromConnection(fakeaerospace.NewConnection(world))
+
+		events := runWatch(t, client, world, [][]string{
+			{"move-node-to-workspace", "--window-id", "2", "ws1"},
+			{"focus", "--window-id", "2"},
+			{"move-node-to-workspace", "--window-id", "2", ".scratchpad"},
+			{"close", "--window-id", "2"},
+		})
+
+		expected := []watchEvent{
+			{Action: "shown", WindowID: 2, Workspace: ".scratchpad", TargetWorkspace: "ws1"},
+			{Action: "focused", WindowID: 2, Workspace: "ws1", TargetWorkspace: "ws1"},
+			{Action: "hidden", WindowID: 2, Workspace: "ws1", TargetWorkspace: ".scratchpad"},
+			{Action: "removed", WindowID: 2, Workspace: ".scratchpad"},
+		}
+		if len(events) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, events)
+		}
+		for i := range expected {
+			if events[i] != expected[i] {
+				t.Fatalf("expected %v, got %v", expected, events)
+			}
+		}
+	})
+
+	t.Run("prints only the windows matching the filters", func(t *testing.T) {
+		world, err := fakeaerospace.ParseWorld([]byte(fakeWorld))
+		if err != nil {
+			t.Fatalf("unable to parse world: %v", err)
+		}
+		client := aerospace.NewClientFromConnection(fakeaerospace.NewConnection(world))
+
+		events := runWatch(t, client, world, [][]string{
+			{"move-node-to-workspace", "--window-id", "2", "ws1"},
+		}, "--filter", "app-name=Ghostty")
+		if len(events) != 0 {
+			t.Fatalf("expected no event for Finder, got %v", events)
+		}
+	})
+
+	t.Run("takes the snapshots in the daemon when it runs", func(t *testing.T) {
+		world, err := fakeaerospace.ParseWorld([]byte(fakeWorld))
+		if err != nil {
+			t.Fatalf("unable to parse world: %v", err)
+		}
+		startDaemon(t, world)
+
+		// The CLI world never changes, the events come from the daemon one.
+		local, err := fakeaerospace.ParseWorld([]byte(fakeWorld))
+		if err != nil {
+			t.Fatalf("unable to parse world: %v", err)
+		}
+		client := aerospace.NewClientFromConnection(fakeaerospace.NewConnection(local))
+
+		events := runWatch(t, client, world, [][]string{
+			{"move-node-to-workspace", "--window-id", "2", "ws1"},
+		})
+		if len(events) != 1 || events[0].Action != "shown" {
+			t.Fatalf("expected Finder shown, got %v", events)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package aerospace
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+// ResponseCache caches the responses of read-only commands.
+//
+// Most queries depend on what the user is doing (focus, windows moving
+// around), so they are only kept until Reset or until a command changes the
+// state. Stable queries, e.g. the list of monitors or the config, are kept
+// for the ttl across resets.
+type ResponseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	response *client.Response
+	storedAt time.Time
+	stable   bool
+}
+
+// NewResponseCache creates a cache that keeps stable queries for ttl.
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		ttl:     ttl,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+// IsStableQuery reports whether the command answers with data that doesn't
+// change while the user works, only when the setup changes.
+func IsStableQuery(command string, args []string) bool {
+	switch command {
+	case "config":
+		return true
+	case "list-monitors":
+		return !slices.Contains(args, "--focused") && !slices.Contains(args, "--mouse")
+	default:
+		return false
+	}
+}
+
+// Interceptor serves the cached responses and stores the new ones.
+// Any command that is not read-only clears the cache.
+func (c *ResponseCache) Interceptor() Interceptor {
+	return func(next SendFunc) SendFunc {
+		return func(command string, args []string) (*client.Response, error) {
+			if !IsReadOnlyCommand(command) {
+				c.Reset()
+				return next(command, args)
+			}
+
+			key := cacheKey(command, args)
+			if response, ok := c.get(key); ok {
+				return response, nil
+			}
+
+			response, err := next(command, args)
+			if err == nil && response != nil {
+				c.put(key, response, IsStableQuery(command, args))
+			}
+			return response, err
+		}
+	}
+}
+
+// Reset drops the cached queries, except the stable ones that are still fresh.
+func (c *ResponseCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if !entry.stable || time.Since(entry.storedAt) >= c.ttl {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *ResponseCache) get(key string) (*client.Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if entry.stable && time.Since(entry.storedAt) >= c.ttl {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	// Callers may modify the response, never hand out the cached one.
+	response := *entry.response
+	return &response, true
+}
+
+func (c *ResponseCache) put(key string, response *client.Response, stable bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored := *response
+	c.entries[key] = cacheEntry{
+		response: &stored,
+		storedAt: time.Now(),
+		stable:   stable,
+	}
+}
+
+func cacheKey(command string, args []string) string {
+	return command + "\x00" + strings.Join(args, "\x00")
+}
@@ -0,0 +1,80 @@
+package aerospace_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+)
+
+func TestResponseCache(t *testing.T) {
+	setup := func(ttl time.Duration) (*countingConnection, *aerospace.ChainConnection, *aerospace.ResponseCache) {
+		counting := &countingConnection{Connection: *newWorldConnection()}
+		cache := aerospace.NewResponseCache(ttl)
+		return counting, aerospace.NewChainConnection(counting, cache.Interceptor()), cache
+	}
+	listWindows := func(t *testing.T, conn *aerospace.ChainConnection) {
+		t.Helper()
+		if _, err := conn.SendCommand("list-windows", []string{"--all", "--json"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("answers repeated queries from the cache", func(t *testing.T) {
+		counting, conn, _ := setup(time.Minute)
+
+		listWindows(t, conn)
+		listWindows(t, conn)
+
+		if counting.calls != 1 {
+			t.Fatalf("expected a single request, got %d", counting.calls)
+		}
+	})
+
+	t.Run("forgets everything once the state changes", func(t *testing.T) {
+		counting, conn, _ := setup(time.Minute)
+
+		listWindows(t, conn)
+		if _, err := conn.SendCommand("focus", []string{"--window-id", "1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		listWindows(t, conn)
+
+		if counting.calls != 3 {
+			t.Fatalf("expected 3 requests, got %d", counting.calls)
+		}
+	})
+
+	t.Run("keeps only the fresh stable queries on reset", func(t *testing.T) {
+		counting, conn, cache := setup(time.Minute)
+
+		listWindows(t, conn)
+		if _, err := conn.SendCommand("list-monitors", []string{"--json"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cache.Reset()
+		listWindows(t, conn)
+		if _, err := conn.SendCommand("list-monitors", []string{"--json"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if counting.calls != 3 {
+			t.Fatalf("expected the monitors to be cached, got %d requests", counting.calls)
+		}
+	})
+
+	t.Run("expires the stable queries after the ttl", func(t *testing.T) {
+		counting, conn, _ := setup(time.Nanosecond)
+
+		for range 2 {
+			if _, err := conn.SendCommand("list-monitors", []string{"--json"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		if counting.calls != 2 {
+			t.Fatalf("expected 2 requests, got %d", counting.calls)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package aerospace
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+// Capability is a feature of AeroSpace that only some versions have.
+type Capability string
+
+// CapabilityFocusFollowsWindow is move-node-to-workspace --focus-follows-window.
+const CapabilityFocusFollowsWindow Capability = "focus-follows-window"
+
+// CapabilityRequirement is the first AeroSpace version having a capability.
+type CapabilityRequirement struct {
+	Capability Capability
+	MinVersion Version
+}
+
+// CapabilityTable lists the capabilities aerospace-scratchpad checks before
+// relying on them.
+//
+//nolint:gochecknoglobals // static table of capabilities
+var CapabilityTable = []CapabilityRequirement{
+	{Capability: CapabilityFocusFollowsWindow, MinVersion: Version{Major: 0, Minor: 15}},
+}
+
+// Version is an AeroSpace release version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion reads the version reported by the AeroSpace server,
+// e.g. "0.19.2-Beta 1b5f3bd".
+func ParseVersion(serverVersion string) (Version, error) {
+	fields := strings.Fields(serverVersion)
+	if len(fields) == 0 {
+		return Version{}, fmt.Errorf("invalid AeroSpace version %q", serverVersion)
+	}
+	release, _, _ := strings.Cut(strings.TrimPrefix(fields[0], "v"), "-")
+
+	parts := strings.Split(release, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid AeroSpace version %q", serverVersion)
+	}
+	numbers := make([]int, 3)
+	for index, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return Version{}, fmt.Errorf("invalid AeroSpace version %q", serverVersion)
+		}
+		numbers[index] = number
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// AtLeast reports whether v is the same as other or newer.
+func (v Version) AtLeast(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Capabilities tells which capabilities the running AeroSpace has.
+type Capabilities struct {
+	// ServerVersion is the version reported by AeroSpace, empty when unknown.
+	ServerVersion string
+	// Compatible is nil when the client library supports the server version,
+	// the reason otherwise.
+	Compatible error
+	// version is nil when the server version can't be read.
+	version *Version
+}
+
+// DetectCapabilities asks AeroSpace its version.
+func DetectCapabilities(conn client.AeroSpaceConnection) Capabilities {
+	capabilities := Capabilities{Compatible: conn.CheckServerVersion()}
+
+	serverVersion, err := conn.GetServerVersion()
+	if err != nil {
+		if capabilities.Compatible == nil {
+			capabilities.Compatible = err
+		}
+		return capabilities
+	}
+
+	capabilities.ServerVersion = serverVersion
+	if version, parseErr := ParseVersion(serverVersion); parseErr == nil {
+		capabilities.version = &version
+	}
+	return capabilities
+}
+
+// Detected reports whether the AeroSpace version could be read.
+func (c Capabilities) Detected() bool {
+	return c.version != nil
+}
+
+// Supports reports whether AeroSpace has the capability. An unknown version
+// is assumed to have them all, the command then fails as it would have.
+func (c Capabilities) Supports(capability Capability) bool {
+	if c.version == nil {
+		return true
+	}
+	for _, requirement := range CapabilityTable {
+		if requirement.Capability == capability {
+			return c.version.AtLeast(requirement.MinVersion)
+		}
+	}
+	return true
+}
+
+// CapabilitiesOf returns the capabilities of the AeroSpace behind cli,
+// reusing the ones detected by an AeroSpaceClient.
+func CapabilitiesOf(cli AeroSpaceWMClient) Capabilities {
+	if aerospaceClient, ok := cli.(*AeroSpaceClient); ok {
+		return aerospaceClient.Capabilities()
+	}
+	return DetectCapabilities(cli.Connection())
+}
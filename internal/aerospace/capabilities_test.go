@@ -0,0 +1,69 @@
+package aerospace_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]struct {
+		serverVersion string
+		expected      aerospace.Version
+		valid         bool
+	}{
+		"release with hash": {
+			serverVersion: "0.19.2-Beta 1b5f3bd",
+			expected:      aerospace.Version{Major: 0, Minor: 19, Patch: 2},
+			valid:         true,
+		},
+		"prefixed": {
+			serverVersion: "v1.2",
+			expected:      aerospace.Version{Major: 1, Minor: 2},
+			valid:         true,
+		},
+		"empty":   {serverVersion: ""},
+		"garbage": {serverVersion: "dev build"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			version, err := aerospace.ParseVersion(tc.serverVersion)
+			if (err == nil) != tc.valid {
+				t.Fatalf("expected valid=%v, got %v", tc.valid, err)
+			}
+			if version != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, version)
+			}
+		})
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	detect := func(serverVersion string) aerospace.Capabilities {
+		return aerospace.DetectCapabilities(fakeaerospace.NewConnection(
+			fakeaerospace.NewWorld(fakeaerospace.State{ServerVersion: serverVersion}),
+		))
+	}
+
+	t.Run("supports what the running version has", func(t *testing.T) {
+		if !detect("0.16.1-Beta abc").Supports(aerospace.CapabilityFocusFollowsWindow) {
+			t.Fatalf("expected focus-follows-window on 0.16.1")
+		}
+		if detect("0.14.2-Beta abc").Supports(aerospace.CapabilityFocusFollowsWindow) {
+			t.Fatalf("expected no focus-follows-window on 0.14.2")
+		}
+	})
+
+	t.Run("assumes everything of an unknown version", func(t *testing.T) {
+		capabilities := detect("nightly")
+
+		if capabilities.Detected() {
+			t.Fatalf("expected an unknown version")
+		}
+		if !capabilities.Supports(aerospace.CapabilityFocusFollowsWindow) {
+			t.Fatalf("expected the capabilities to be assumed")
+		}
+	})
+}
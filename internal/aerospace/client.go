@@ -1,111 +1,175 @@
 package aerospace
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"sync"
+	"time"
 
-	aerospacecli "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/focus"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/layout"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
 	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+)
+
+const (
+	// readRetryAttempts bounds how many times a query is sent in total
+	// when the connection fails.
+	readRetryAttempts = 3
+	readRetryDelay    = 50 * time.Millisecond
 )
 
 // AeroSpaceClient implements the AeroSpaceClient interface for interacting with AeroSpaceWM.
 //
+// Every call goes through a chain of interceptors (context, logging, the ones
+// added with Use, retries and dry-run) before reaching the wrapped client's
+// connection.
+//
 //revive:disable:exported
 type AeroSpaceClient struct {
-	ogClient *aerospacecli.AeroSpaceWM
-	client   AeroSpaceWMClient // Interface for Windows()/Workspaces() access
-	dryRun   bool
+	mu           sync.Mutex
+	client       AeroSpaceWMClient // Interface for Windows()/Workspaces() access
+	interceptors []Interceptor
+	dryRun       bool
+	out          io.Writer
+	ctx          context.Context
+
+	// chained holds the services wired through the interceptors.
+	// It is built on first use, so the options can change until then.
+	chained *ConnectionClient
+
+	// capabilities of the AeroSpace behind client, detected on first use.
+	capabilities *Capabilities
 }
 
 // ClientOpts defines options for creating a new AeroSpaceClient.
 type ClientOpts struct {
 	DryRun bool
+	// Out is where the dry-run changes are printed, os.Stdout when nil.
+	Out io.Writer
 }
 
 // NewAeroSpaceClient creates a new AeroSpaceClient with the default settings.
 func NewAeroSpaceClient(client AeroSpaceWMClient) *AeroSpaceClient {
-	// Type assert to get the underlying *AeroSpaceWM for storage
-	var ogClient *aerospacecli.AeroSpaceWM
-	if realClient, ok := client.(*aerospacecli.AeroSpaceWM); ok {
-		ogClient = realClient
-	}
 	return &AeroSpaceClient{
-		ogClient: ogClient,
-		client:   client,
-		dryRun:   false, // Default dry-run is false
+		client: client,
+		dryRun: false, // Default dry-run is false
 	}
 }
 
+// SetClient replaces the client used to talk to AeroSpaceWM.
+// It allows commands to be pointed to a different backend after the CLI
+// flags are parsed (e.g. a simulated world).
+func (c *AeroSpaceClient) SetClient(client AeroSpaceWMClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.client = client
+	c.chained = nil
+	c.capabilities = nil
+}
+
 // SetOptions the dry-run flag for the AeroSpaceClient.
 func (c *AeroSpaceClient) SetOptions(opts ClientOpts) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.dryRun = opts.DryRun
+	c.out = opts.Out
+	c.chained = nil
+}
+
+// SetContext bounds every request to ctx, e.g. to apply an overall deadline.
+func (c *AeroSpaceClient) SetContext(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ctx = ctx
+	c.chained = nil
+}
+
+// Use adds interceptors to the chain. They see the requests after the
+// logging and before the retry interceptors, in the order they were added.
+func (c *AeroSpaceClient) Use(interceptors ...Interceptor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interceptors = append(c.interceptors, interceptors...)
+	c.chained = nil
+}
+
+// Capabilities returns what the running AeroSpace supports. They are
+// detected once, unless AeroSpace doesn't answer.
+func (c *AeroSpaceClient) Capabilities() Capabilities {
+	c.mu.Lock()
+	cached := c.capabilities
+	c.mu.Unlock()
+	if cached != nil {
+		return *cached
+	}
+
+	detected := DetectCapabilities(c.Connection())
+	if detected.ServerVersion != "" {
+		c.mu.Lock()
+		c.capabilities = &detected
+		c.mu.Unlock()
+	}
+	return detected
 }
 
 // Windows returns the windows service.
 func (c *AeroSpaceClient) Windows() *windows.Service {
-	return c.client.Windows()
+	return c.services().Windows()
 }
 
 // Workspaces returns the workspaces service.
 func (c *AeroSpaceClient) Workspaces() *workspaces.Service {
-	return c.client.Workspaces()
+	return c.services().Workspaces()
 }
 
 // Focus returns the focus service.
 func (c *AeroSpaceClient) Focus() *focus.Service {
-	return c.client.Focus()
+	return c.services().Focus()
 }
 
 // Layout returns the layout service.
 func (c *AeroSpaceClient) Layout() *layout.Service {
-	return c.client.Layout()
+	return c.services().Layout()
 }
 
 // GetAllWindows retrieves all windows managed by AeroSpaceWM.
-func (c *AeroSpaceClient) GetAllWindows() ([]windows.Window, error) {
-	return c.client.Windows().GetAllWindows()
+func (c *AeroSpaceClient) GetAllWindows(ctx context.Context) ([]windows.Window, error) {
+	return WithContext(ctx, c).Windows().GetAllWindows()
 }
 
 func (c *AeroSpaceClient) GetAllWindowsByWorkspace(
+	ctx context.Context,
 	workspaceName string,
 ) ([]windows.Window, error) {
-	return c.client.Windows().GetAllWindowsByWorkspace(workspaceName)
+	return WithContext(ctx, c).Windows().GetAllWindowsByWorkspace(workspaceName)
 }
 
-func (c *AeroSpaceClient) GetFocusedWindow() (*windows.Window, error) {
-	return c.client.Windows().GetFocusedWindow()
+func (c *AeroSpaceClient) GetFocusedWindow(ctx context.Context) (*windows.Window, error) {
+	return WithContext(ctx, c).Windows().GetFocusedWindow()
 }
 
-func (c *AeroSpaceClient) SetFocusByWindowID(windowID int) error {
-	if c.dryRun {
-		fmt.Fprintf(os.Stdout, "[dry-run] SetFocusByWindowID(%d)\n", windowID)
-		return nil
-	}
-	return c.client.Focus().SetFocusByWindowID(windowID)
+func (c *AeroSpaceClient) SetFocusByWindowID(ctx context.Context, windowID int) error {
+	return WithContext(ctx, c).Focus().SetFocusByWindowID(windowID)
 }
 
-func (c *AeroSpaceClient) GetFocusedWorkspace() (*workspaces.Workspace, error) {
-	return c.client.Workspaces().GetFocusedWorkspace()
+func (c *AeroSpaceClient) GetFocusedWorkspace(ctx context.Context) (*workspaces.Workspace, error) {
+	return WithContext(ctx, c).Workspaces().GetFocusedWorkspace()
 }
 
 func (c *AeroSpaceClient) MoveWindowToWorkspace(
+	ctx context.Context,
 	windowID int,
 	workspaceName string,
 ) error {
-	if c.dryRun {
-		fmt.Fprintf(
-			os.Stdout,
-			"[dry-run] MoveWindowToWorkspace(windowID=%d, workspace=%s)\n",
-			windowID,
-			workspaceName,
-		)
-		return nil
-	}
-	return c.client.Workspaces().MoveWindowToWorkspaceWithOpts(
+	return WithContext(ctx, c).Workspaces().MoveWindowToWorkspaceWithOpts(
 		workspaces.MoveWindowToWorkspaceArgs{
 			WorkspaceName: workspaceName,
 		},
@@ -115,40 +179,30 @@ func (c *AeroSpaceClient) MoveWindowToWorkspace(
 	)
 }
 
-func (c *AeroSpaceClient) SetLayout(windowID int, layoutName string) error {
-	if c.dryRun {
-		fmt.Fprintf(
-			os.Stdout,
-			"[dry-run] SetLayout(windowID=%d, layout=%s)\n",
-			windowID,
-			layoutName,
-		)
-		return nil
-	}
-	return c.client.Layout().SetLayout([]string{layoutName}, layout.SetLayoutOpts{
+func (c *AeroSpaceClient) SetLayout(ctx context.Context, windowID int, layoutName string) error {
+	return WithContext(ctx, c).Layout().SetLayout([]string{layoutName}, layout.SetLayoutOpts{
 		WindowID: layout.IntPtr(windowID),
 	})
 }
 
+// Connection returns the connection wrapped by the interceptor chain.
 func (c *AeroSpaceClient) Connection() client.AeroSpaceConnection {
-	if c.client != nil {
-		return c.client.Connection()
-	}
-	return c.ogClient.Connection()
+	return c.services().Connection()
 }
 
 func (c *AeroSpaceClient) CloseConnection() error {
-	if c.dryRun {
-		fmt.Fprintln(os.Stdout, "[dry-run] CloseConnection()")
+	c.mu.Lock()
+	dryRun, out, wrapped := c.dryRun, c.out, c.client
+	c.mu.Unlock()
+
+	if dryRun {
+		fmt.Fprintln(outputOrStdout(out), "[dry-run] CloseConnection()")
 		return nil
 	}
-	if c.client != nil {
-		if closer, ok := c.client.(interface{ CloseConnection() error }); ok {
-			return closer.CloseConnection()
-		}
-		return nil
+	if closer, ok := wrapped.(interface{ CloseConnection() error }); ok {
+		return closer.CloseConnection()
 	}
-	return c.ogClient.CloseConnection()
+	return nil
 }
 
 // AeroSpaceWMClient defines the interface for clients that provide Windows(), Workspaces(), Focus(), and Layout() services.
@@ -160,11 +214,45 @@ type AeroSpaceWMClient interface {
 	Connection() client.AeroSpaceConnection
 }
 
-// GetUnderlyingClient returns the underlying AeroSpaceWM client.
-// This is needed for components that need direct access to Windows() and Workspaces() methods.
+// GetUnderlyingClient returns the wrapped client, bypassing the interceptors.
+// Prefer passing the AeroSpaceClient itself, so dry-run and tracing apply.
 func (c *AeroSpaceClient) GetUnderlyingClient() AeroSpaceWMClient {
-	if c.client != nil {
-		return c.client
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.client
+}
+
+func (c *AeroSpaceClient) services() *ConnectionClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.chained != nil {
+		return c.chained
+	}
+
+	conn := c.client.Connection()
+	// A client wrapping another AeroSpaceClient, e.g. the commands run by
+	// batch, leaves the logging and the retries to the outer one.
+	_, nested := c.client.(*AeroSpaceClient)
+
+	var chain []Interceptor
+	if c.ctx != nil && c.ctx.Done() != nil {
+		chain = append(chain, contextInterceptor(c.ctx, conn))
+	}
+	if !nested {
+		chain = append(chain, LoggingInterceptor(logger.GetDefaultLogger()))
 	}
-	return c.ogClient
+	chain = append(chain, c.interceptors...)
+	if !nested {
+		chain = append(chain, RetryInterceptor(readRetryAttempts, readRetryDelay))
+	}
+	if c.dryRun {
+		chain = append(chain, DryRunInterceptor(c.out))
+	}
+
+	c.chained = NewClientFromConnection(
+		NewChainConnection(conn, chain...),
+	)
+	return c.chained
 }
@@ -0,0 +1,62 @@
+package aerospace
+
+import (
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/focus"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/layout"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+// ConnectionClient is an AeroSpaceWMClient built on top of any connection.
+//
+// aerospace-ipc only builds clients for its own socket connection, this
+// allows plugging in other connections (in-process worlds, recorders, etc).
+type ConnectionClient struct {
+	conn       client.AeroSpaceConnection
+	windows    *windows.Service
+	workspaces *workspaces.Service
+	focus      *focus.Service
+	layout     *layout.Service
+}
+
+// NewClientFromConnection creates a client that sends every command through conn.
+func NewClientFromConnection(conn client.AeroSpaceConnection) *ConnectionClient {
+	return &ConnectionClient{
+		conn:       conn,
+		windows:    windows.NewService(conn),
+		workspaces: workspaces.NewService(conn),
+		focus:      focus.NewService(conn),
+		layout:     layout.NewService(conn),
+	}
+}
+
+// Windows returns the windows service.
+func (c *ConnectionClient) Windows() *windows.Service {
+	return c.windows
+}
+
+// Workspaces returns the workspaces service.
+func (c *ConnectionClient) Workspaces() *workspaces.Service {
+	return c.workspaces
+}
+
+// Focus returns the focus service.
+func (c *ConnectionClient) Focus() *focus.Service {
+	return c.focus
+}
+
+// Layout returns the layout service.
+func (c *ConnectionClient) Layout() *layout.Service {
+	return c.layout
+}
+
+// Connection returns the underlying connection.
+func (c *ConnectionClient) Connection() client.AeroSpaceConnection {
+	return c.conn
+}
+
+// CloseConnection closes the underlying connection.
+func (c *ConnectionClient) CloseConnection() error {
+	return c.conn.CloseConnection()
+}
@@ -0,0 +1,166 @@
+package aerospace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/focus"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/layout"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+// ExitCodeTimeout is the exit code used when AeroSpace doesn't answer in
+// time. It is the same code used by timeout(1).
+const ExitCodeTimeout = 124
+
+// ErrTimeout is returned when AeroSpace doesn't answer before the deadline.
+// Use errors.Is to tell timeouts apart from other failures.
+var ErrTimeout = errors.New("timed out waiting for AeroSpace")
+
+// TimeoutError is the error returned when a request hits the deadline.
+type TimeoutError struct {
+	Command string
+	Args    []string
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf(
+		"%s to answer '%s'",
+		ErrTimeout,
+		strings.TrimSpace(e.Command+" "+strings.Join(e.Args, " ")),
+	)
+}
+
+// Is makes errors.Is(err, ErrTimeout) true for any TimeoutError.
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// ExitCode is the exit code the CLI should use for this error.
+func (e *TimeoutError) ExitCode() int {
+	return ExitCodeTimeout
+}
+
+// WithContext returns a client bound to ctx: every request fails once ctx is
+// done, and a request in flight gives up at the ctx deadline.
+// Contexts that can never be done return cli itself.
+func WithContext(ctx context.Context, cli AeroSpaceWMClient) AeroSpaceWMClient {
+	if ctx == nil || ctx.Done() == nil {
+		return cli
+	}
+
+	conn := cli.Connection()
+	return NewClientFromConnection(
+		NewChainConnection(conn, contextInterceptor(ctx, conn)),
+	)
+}
+
+// LazyWithContext is WithContext, binding cli on first use instead. Building
+// it never touches the connection, e.g. of a client that failed to connect.
+func LazyWithContext(ctx context.Context, cli AeroSpaceWMClient) AeroSpaceWMClient {
+	return &lazyClient{bind: func() AeroSpaceWMClient { return WithContext(ctx, cli) }}
+}
+
+type lazyClient struct {
+	bind  func() AeroSpaceWMClient
+	once  sync.Once
+	bound AeroSpaceWMClient
+}
+
+func (c *lazyClient) client() AeroSpaceWMClient {
+	c.once.Do(func() { c.bound = c.bind() })
+	return c.bound
+}
+
+// Windows returns the windows service.
+func (c *lazyClient) Windows() *windows.Service {
+	return c.client().Windows()
+}
+
+// Workspaces returns the workspaces service.
+func (c *lazyClient) Workspaces() *workspaces.Service {
+	return c.client().Workspaces()
+}
+
+// Focus returns the focus service.
+func (c *lazyClient) Focus() *focus.Service {
+	return c.client().Focus()
+}
+
+// Layout returns the layout service.
+func (c *lazyClient) Layout() *layout.Service {
+	return c.client().Layout()
+}
+
+// Connection returns the connection bound to the context.
+func (c *lazyClient) Connection() client.AeroSpaceConnection {
+	return c.client().Connection()
+}
+
+// contextInterceptor stops requests once ctx is done. When conn is backed by
+// a socket, its deadline is set to the ctx deadline so a request blocked on
+// AeroSpace is interrupted instead of hanging.
+func contextInterceptor(ctx context.Context, conn client.AeroSpaceConnection) Interceptor {
+	return func(next SendFunc) SendFunc {
+		return func(command string, args []string) (*client.Response, error) {
+			if ctx.Err() != nil {
+				return nil, contextError(ctx, command, args)
+			}
+
+			if deadline, ok := ctx.Deadline(); ok {
+				if netConn := socketOf(conn); netConn != nil {
+					_ = netConn.SetDeadline(deadline)
+					defer func() { _ = netConn.SetDeadline(time.Time{}) }()
+				}
+			}
+
+			response, err := next(command, args)
+			if err == nil {
+				return response, nil
+			}
+			if ctx.Err() != nil {
+				return nil, contextError(ctx, command, args)
+			}
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				return nil, &TimeoutError{Command: command, Args: args}
+			}
+			return response, err
+		}
+	}
+}
+
+func contextError(ctx context.Context, command string, args []string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Command: command, Args: args}
+	}
+	return fmt.Errorf("%w: %s", context.Cause(ctx), command)
+}
+
+// connectionWrapper is implemented by connections that decorate another one.
+type connectionWrapper interface {
+	Unwrap() client.AeroSpaceConnection
+}
+
+// socketOf digs through the connection wrappers looking for the AeroSpace
+// socket. It returns nil for connections that aren't backed by one.
+func socketOf(conn client.AeroSpaceConnection) net.Conn {
+	for conn != nil {
+		switch c := conn.(type) {
+		case *client.AeroSpaceSocketConnection:
+			return c.Conn
+		case connectionWrapper:
+			conn = c.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
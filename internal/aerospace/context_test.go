@@ -0,0 +1,127 @@
+package aerospace_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/stderr"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+// countingConnection counts the requests that reach the connection.
+type countingConnection struct {
+	fakeaerospace.Connection
+
+	calls int
+}
+
+func (c *countingConnection) SendCommand(command string, args []string) (*client.Response, error) {
+	c.calls++
+	return c.Connection.SendCommand(command, args)
+}
+
+func TestWithContext(t *testing.T) {
+	t.Run("gives up on a stuck AeroSpace at the deadline", func(t *testing.T) {
+		conn := testutils.StartUnresponsiveAeroSpace(t)
+		cli := aerospace.NewAeroSpaceClient(aerospace.NewClientFromConnection(conn))
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		startedAt := time.Now()
+		_, err := cli.GetAllWindows(ctx)
+		if !errors.Is(err, aerospace.ErrTimeout) {
+			t.Fatalf("expected a timeout, got %v", err)
+		}
+		if elapsed := time.Since(startedAt); elapsed > time.Second {
+			t.Fatalf("expected to give up at the deadline, took %s", elapsed)
+		}
+
+		var coder stderr.ExitCoder
+		if !errors.As(err, &coder) || coder.ExitCode() != aerospace.ExitCodeTimeout {
+			t.Fatalf("expected exit code %d, got %v", aerospace.ExitCodeTimeout, err)
+		}
+	})
+
+	t.Run("does not send requests once the context is done", func(t *testing.T) {
+		conn := &countingConnection{Connection: *newWorldConnection()}
+		ctx, cancel := context.WithTimeout(t.Context(), -time.Second)
+		defer cancel()
+
+		_, err := aerospace.WithContext(ctx, aerospace.NewClientFromConnection(conn)).
+			Windows().
+			GetAllWindows()
+		if !errors.Is(err, aerospace.ErrTimeout) {
+			t.Fatalf("expected a timeout, got %v", err)
+		}
+		if conn.calls != 0 {
+			t.Fatalf("expected no requests, got %d", conn.calls)
+		}
+	})
+
+	t.Run("keeps the client for contexts that never end", func(t *testing.T) {
+		cli := aerospace.NewClientFromConnection(newWorldConnection())
+		if aerospace.WithContext(context.Background(), cli) != cli {
+			t.Fatalf("expected the same client")
+		}
+	})
+}
+
+func TestReconnectingConnection(t *testing.T) {
+	world := fakeaerospace.NewWorld(fakeaerospace.State{
+		Windows: []fakeaerospace.Window{{WindowID: 1, AppName: "Finder", Workspace: "ws1"}},
+	})
+
+	dir, err := os.MkdirTemp("", "fas")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "aerospace.sock")
+
+	serve := func() *fakeaerospace.Server {
+		server := fakeaerospace.NewServer(world, t.Logf)
+		go func() { _ = server.ListenAndServe(socketPath) }()
+		return server
+	}
+
+	server := serve()
+	var conn client.AeroSpaceConnection
+	for range 100 {
+		conn, err = client.NewAeroSpaceSocketConnection(socketPath)
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("unable to connect: %v", err)
+	}
+
+	reconnecting := aerospace.NewReconnectingConnection(conn, aerospace.DialSocket(socketPath))
+	t.Cleanup(func() { _ = reconnecting.CloseConnection() })
+	cli := aerospace.NewClientFromConnection(aerospace.NewChainConnection(
+		reconnecting,
+		aerospace.RetryInterceptor(3, 50*time.Millisecond),
+	))
+
+	// AeroSpace restarts between two requests.
+	_ = server.Close()
+	server = serve()
+	t.Cleanup(func() { _ = server.Close() })
+
+	windows, err := cli.Windows().GetAllWindows()
+	if err != nil {
+		t.Fatalf("expected the query to survive the restart, got %v", err)
+	}
+	if len(windows) != 1 || windows[0].AppName != "Finder" {
+		t.Fatalf("unexpected windows: %+v", windows)
+	}
+}
@@ -0,0 +1,233 @@
+package aerospace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+)
+
+// SendFunc sends a command to AeroSpace, same as AeroSpaceConnection.SendCommand.
+type SendFunc func(command string, args []string) (*client.Response, error)
+
+// Interceptor decorates a SendFunc with cross-cutting behavior, e.g.
+// dry-run, logging, tracing or retries. It must call next to reach AeroSpace.
+type Interceptor func(next SendFunc) SendFunc
+
+// ChainConnection is a connection whose SendCommand goes through a chain of
+// interceptors before reaching the wrapped connection.
+type ChainConnection struct {
+	client.AeroSpaceConnection
+
+	send SendFunc
+}
+
+// NewChainConnection wraps conn with the interceptors.
+// The first interceptor is the outermost one, i.e. the first to see a request.
+func NewChainConnection(
+	conn client.AeroSpaceConnection,
+	interceptors ...Interceptor,
+) *ChainConnection {
+	send := conn.SendCommand
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		send = interceptors[i](send)
+	}
+
+	return &ChainConnection{
+		AeroSpaceConnection: conn,
+		send:                send,
+	}
+}
+
+// SendCommand sends the command through the interceptor chain.
+func (c *ChainConnection) SendCommand(command string, args []string) (*client.Response, error) {
+	return c.send(command, args)
+}
+
+// Unwrap returns the wrapped connection.
+func (c *ChainConnection) Unwrap() client.AeroSpaceConnection {
+	return c.AeroSpaceConnection
+}
+
+// IsReadOnlyCommand reports whether the AeroSpace command only queries state.
+// Read-only commands are safe to run in dry-run mode and to retry.
+func IsReadOnlyCommand(command string) bool {
+	switch command {
+	case "config", "debug-windows":
+		return true
+	default:
+		return strings.HasPrefix(command, "list-")
+	}
+}
+
+// DryRunInterceptor prints the commands that would change AeroSpace state to
+// out (os.Stdout when nil) instead of sending them. Queries are still sent so
+// the commands can decide what they would do.
+func DryRunInterceptor(out io.Writer) Interceptor {
+	return func(next SendFunc) SendFunc {
+		return func(command string, args []string) (*client.Response, error) {
+			if IsReadOnlyCommand(command) {
+				return next(command, args)
+			}
+
+			fmt.Fprintln(outputOrStdout(out), "[dry-run] "+describeCommand(command, args))
+			return &client.Response{}, nil
+		}
+	}
+}
+
+func outputOrStdout(out io.Writer) io.Writer {
+	if out != nil {
+		return out
+	}
+	return os.Stdout
+}
+
+// LoggingInterceptor logs every request with its outcome at debug level.
+func LoggingInterceptor(log logger.Logger) Interceptor {
+	return func(next SendFunc) SendFunc {
+		return func(command string, args []string) (*client.Response, error) {
+			startedAt := time.Now()
+			response, err := next(command, args)
+			log.LogDebug(
+				"IPC: request",
+				"command", command,
+				"args", args,
+				"duration", time.Since(startedAt),
+				"error", err,
+			)
+			return response, err
+		}
+	}
+}
+
+// TimingInterceptor writes every request, its arguments and its latency to out.
+func TimingInterceptor(out io.Writer) Interceptor {
+	return func(next SendFunc) SendFunc {
+		return func(command string, args []string) (*client.Response, error) {
+			startedAt := time.Now()
+			response, err := next(command, args)
+			elapsed := time.Since(startedAt)
+
+			status := "ok"
+			if err != nil {
+				status = "error"
+			}
+			fmt.Fprintf(
+				out,
+				"[timings] %8.3fms %-5s %s\n",
+				float64(elapsed)/float64(time.Millisecond),
+				status,
+				strings.TrimSpace(command+" "+strings.Join(args, " ")),
+			)
+
+			return response, err
+		}
+	}
+}
+
+// RetryInterceptor retries read-only commands up to attempts times in total,
+// waiting delay between attempts. Commands that change state are never
+// retried since they may have been applied before failing.
+func RetryInterceptor(attempts int, delay time.Duration) Interceptor {
+	return func(next SendFunc) SendFunc {
+		return func(command string, args []string) (*client.Response, error) {
+			response, err := next(command, args)
+			if !IsReadOnlyCommand(command) {
+				return response, err
+			}
+
+			for attempt := 1; err != nil && attempt < attempts && isRetryable(err); attempt++ {
+				logger.GetDefaultLogger().LogDebug(
+					"IPC: retrying request",
+					"command", command,
+					"attempt", attempt+1,
+					"error", err,
+				)
+				time.Sleep(delay)
+				response, err = next(command, args)
+			}
+
+			return response, err
+		}
+	}
+}
+
+// isRetryable tells transient transport failures apart from AeroSpace
+// answering with an error, which would fail again. Timeouts aren't retried
+// since the deadline has already passed.
+func isRetryable(err error) bool {
+	return isTransportError(err) &&
+		!errors.Is(err, os.ErrDeadlineExceeded) &&
+		!errors.Is(err, ErrTimeout)
+}
+
+// isTransportError reports whether err comes from the socket itself rather
+// than from AeroSpace, e.g. AeroSpace restarted or the connection dropped.
+func isTransportError(err error) bool {
+	for _, target := range []error{
+		io.EOF,
+		io.ErrUnexpectedEOF,
+		net.ErrClosed,
+		os.ErrDeadlineExceeded,
+		syscall.EAGAIN,
+		syscall.EPIPE,
+		syscall.ECONNRESET,
+		syscall.ECONNREFUSED,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	// aerospace-ipc doesn't wrap anything when it has no connection.
+	return strings.Contains(err.Error(), "connection is not established")
+}
+
+// describeCommand renders a command the same way the dry-run mode always did
+// for the calls made by the scratchpad, and verbatim for anything else.
+func describeCommand(command string, args []string) string {
+	windowID := flagArg(args, "--window-id")
+	positional := positionalArg(args)
+
+	switch {
+	case command == "move-node-to-workspace" && windowID != "":
+		return fmt.Sprintf("MoveWindowToWorkspace(windowID=%s, workspace=%s)", windowID, positional)
+	case command == "layout" && windowID != "":
+		return fmt.Sprintf("SetLayout(windowID=%s, layout=%s)", windowID, positional)
+	case command == "focus" && windowID != "":
+		return fmt.Sprintf("SetFocusByWindowID(%s)", windowID)
+	default:
+		return strings.TrimSpace(command + " " + strings.Join(args, " "))
+	}
+}
+
+func flagArg(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// positionalArg returns the first argument that is neither a flag nor the
+// value of --window-id.
+func positionalArg(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--window-id":
+			i++
+		case strings.HasPrefix(args[i], "--"):
+		default:
+			return args[i]
+		}
+	}
+	return ""
+}
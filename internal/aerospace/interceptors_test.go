@@ -0,0 +1,127 @@
+package aerospace_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+)
+
+// flakyConnection fails the first failures calls with a transport error.
+type flakyConnection struct {
+	fakeaerospace.Connection
+
+	failures int
+	calls    int
+}
+
+func (f *flakyConnection) SendCommand(command string, args []string) (*client.Response, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, fmt.Errorf("failed to read response length\n%w", syscall.EAGAIN)
+	}
+	return f.Connection.SendCommand(command, args)
+}
+
+func newWorldConnection() *fakeaerospace.Connection {
+	return fakeaerospace.NewConnection(fakeaerospace.NewWorld(fakeaerospace.State{
+		Windows: []fakeaerospace.Window{{WindowID: 1, AppName: "Finder", Workspace: "ws1"}},
+	}))
+}
+
+func TestInterceptors(t *testing.T) {
+	t.Run("runs the chain from the outermost interceptor", func(t *testing.T) {
+		var calls []string
+		tag := func(name string) aerospace.Interceptor {
+			return func(next aerospace.SendFunc) aerospace.SendFunc {
+				return func(command string, args []string) (*client.Response, error) {
+					calls = append(calls, name)
+					return next(command, args)
+				}
+			}
+		}
+
+		conn := aerospace.NewChainConnection(newWorldConnection(), tag("first"), tag("second"))
+		if _, err := conn.SendCommand("list-windows", []string{"--all"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Join(calls, ",") != "first,second" {
+			t.Fatalf("unexpected order: %v", calls)
+		}
+	})
+
+	t.Run("dry-run prints mutations and sends queries", func(t *testing.T) {
+		var out bytes.Buffer
+		conn := aerospace.NewChainConnection(newWorldConnection(), aerospace.DryRunInterceptor(&out))
+
+		if _, err := conn.SendCommand("list-windows", []string{"--all"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err := conn.SendCommand(
+			"move-node-to-workspace",
+			[]string{".scratchpad", "--window-id", "1"},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "[dry-run] MoveWindowToWorkspace(windowID=1, workspace=.scratchpad)\n"
+		if out.String() != expected {
+			t.Fatalf("expected %q, got %q", expected, out.String())
+		}
+	})
+
+	t.Run("timings report each call", func(t *testing.T) {
+		var out bytes.Buffer
+		conn := aerospace.NewChainConnection(
+			newWorldConnection(),
+			aerospace.TimingInterceptor(&out),
+		)
+
+		_, _ = conn.SendCommand("list-windows", []string{"--all"})
+		_, _ = conn.SendCommand("focus", []string{"--window-id", "42"})
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 2 ||
+			!strings.HasSuffix(lines[0], "ok    list-windows --all") ||
+			!strings.HasSuffix(lines[1], "error focus --window-id 42") {
+			t.Fatalf("unexpected timings:\n%s", out.String())
+		}
+	})
+
+	t.Run("retries queries on transport errors", func(t *testing.T) {
+		flaky := &flakyConnection{Connection: *newWorldConnection(), failures: 2}
+		conn := aerospace.NewChainConnection(flaky, aerospace.RetryInterceptor(3, time.Millisecond))
+
+		if _, err := conn.SendCommand("list-windows", []string{"--all", "--json"}); err != nil {
+			t.Fatalf("expected success after retries, got %v", err)
+		}
+		if flaky.calls != 3 {
+			t.Fatalf("expected 3 calls, got %d", flaky.calls)
+		}
+	})
+
+	t.Run("never retries mutations nor failed commands", func(t *testing.T) {
+		flaky := &flakyConnection{Connection: *newWorldConnection(), failures: 1}
+		conn := aerospace.NewChainConnection(flaky, aerospace.RetryInterceptor(3, time.Millisecond))
+
+		if _, err := conn.SendCommand("focus", []string{"--window-id", "1"}); err == nil {
+			t.Fatalf("expected the mutation to fail")
+		}
+
+		flaky.calls, flaky.failures = 0, 0
+		if _, err := conn.SendCommand("list-windows", []string{"--unknown"}); err == nil {
+			t.Fatalf("expected the query to fail")
+		}
+		if flaky.calls != 1 {
+			t.Fatalf("expected a single call, got %d", flaky.calls)
+		}
+	})
+}
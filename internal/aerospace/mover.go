@@ -1,6 +1,7 @@
 package aerospace
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -13,22 +14,43 @@ import (
 
 type Mover interface {
 	// MoveWindowToScratchpad sends a window to a workspace
-	MoveWindowToScratchpad(window windows.Window) (string, error)
+	MoveWindowToScratchpad(ctx context.Context, window windows.Window) (string, error)
 
 	// MoveWindowToScratchpadForMonitor sends a window to a scratchpad workspace for a specific monitor.
 	// If monitorID <= 0, falls back to the default scratchpad workspace.
-	MoveWindowToScratchpadForMonitor(window windows.Window, monitorID int) (string, error)
+	MoveWindowToScratchpadForMonitor(
+		ctx context.Context,
+		window windows.Window,
+		monitorID int,
+	) (string, error)
 
 	// MoveWindowToWorkspace sends a window to a workspace and set focus
 	MoveWindowToWorkspace(
+		ctx context.Context,
 		window *windows.Window,
 		workspace *workspaces.Workspace,
 		shouldSetFocus bool,
 	) error
 }
 
+// Operation describes a window moved by the MoverAeroSpace.
+type Operation struct {
+	WindowID       int
+	AppName        string
+	FromWorkspace  string
+	ToWorkspace    string
+	PreviousLayout string
+}
+
+// OperationRecorder is told about every window the MoverAeroSpace moved,
+// e.g. to keep a journal of the changes that can be undone.
+type OperationRecorder interface {
+	RecordOperation(operation Operation)
+}
+
 type MoverAeroSpace struct {
 	aerospace AeroSpaceWMClient
+	recorder  OperationRecorder
 }
 
 func NewAeroSpaceMover(aerospace AeroSpaceWMClient) MoverAeroSpace {
@@ -37,21 +59,44 @@ func NewAeroSpaceMover(aerospace AeroSpaceWMClient) MoverAeroSpace {
 	}
 }
 
+// SetRecorder records the moves with recorder, nil stops recording.
+func (a *MoverAeroSpace) SetRecorder(recorder OperationRecorder) {
+	a.recorder = recorder
+}
+
+func (a *MoverAeroSpace) record(window windows.Window, toWorkspace string) {
+	if a.recorder == nil {
+		return
+	}
+
+	a.recorder.RecordOperation(Operation{
+		WindowID:       window.WindowID,
+		AppName:        window.AppName,
+		FromWorkspace:  window.Workspace,
+		ToWorkspace:    toWorkspace,
+		PreviousLayout: window.WindowLayout,
+	})
+}
+
 func (a *MoverAeroSpace) MoveWindowToScratchpad(
+	ctx context.Context,
 	window windows.Window,
 ) (string, error) {
 	logger := logger.GetDefaultLogger()
 	logger.LogDebug("MOVING: MoveWindowToScratchpad", "window", window)
 
-	targetWorkspace := a.resolveScratchpadWorkspace()
+	cli := WithContext(ctx, a.aerospace)
+	targetWorkspace := resolveScratchpadWorkspace(cli)
 
-	if err := a.moveWindowToScratchpadWorkspace(window, targetWorkspace); err != nil {
+	if err := moveWindowToScratchpadWorkspace(cli, window, targetWorkspace); err != nil {
 		return targetWorkspace, err
 	}
+	a.record(window, targetWorkspace)
 	return targetWorkspace, nil
 }
 
 func (a *MoverAeroSpace) MoveWindowToScratchpadForMonitor(
+	ctx context.Context,
 	window windows.Window,
 	monitorID int,
 ) (string, error) {
@@ -64,15 +109,18 @@ func (a *MoverAeroSpace) MoveWindowToScratchpadForMonitor(
 		monitorID,
 	)
 
-	targetWorkspace := a.resolveScratchpadWorkspaceForMonitor(monitorID)
+	cli := WithContext(ctx, a.aerospace)
+	targetWorkspace := resolveScratchpadWorkspaceForMonitor(cli, monitorID)
 
-	if err := a.moveWindowToScratchpadWorkspace(window, targetWorkspace); err != nil {
+	if err := moveWindowToScratchpadWorkspace(cli, window, targetWorkspace); err != nil {
 		return targetWorkspace, err
 	}
+	a.record(window, targetWorkspace)
 	return targetWorkspace, nil
 }
 
 func (a *MoverAeroSpace) MoveWindowToWorkspace(
+	ctx context.Context,
 	window *windows.Window,
 	workspace *workspaces.Workspace,
 	shouldSetFocus bool,
@@ -84,71 +132,45 @@ func (a *MoverAeroSpace) MoveWindowToWorkspace(
 		return errors.New("workspace is nil")
 	}
 
-	// Use wrapper's MoveWindowToWorkspace if available (for dry-run support)
-	if wrapper, ok := a.aerospace.(*AeroSpaceClient); ok {
-		if err := wrapper.MoveWindowToWorkspace(
-			window.WindowID,
+	cli := WithContext(ctx, a.aerospace)
+	windowID := window.WindowID
+	if err := cli.Workspaces().MoveWindowToWorkspaceWithOpts(
+		workspaces.MoveWindowToWorkspaceArgs{
+			WorkspaceName: workspace.Workspace,
+		},
+		workspaces.MoveWindowToWorkspaceOpts{
+			WindowID: &windowID,
+		},
+	); err != nil {
+		return fmt.Errorf(
+			"unable to move window '%+v' to workspace '%s': %w",
+			window,
 			workspace.Workspace,
-		); err != nil {
-			return fmt.Errorf(
-				"unable to move window '%+v' to workspace '%s': %w",
-				window,
-				workspace.Workspace,
-				err,
-			)
-		}
-	} else {
-		// Fallback to direct service call
-		windowID := window.WindowID
-		if err := a.aerospace.Workspaces().MoveWindowToWorkspaceWithOpts(
-			workspaces.MoveWindowToWorkspaceArgs{
-				WorkspaceName: workspace.Workspace,
-			},
-			workspaces.MoveWindowToWorkspaceOpts{
-				WindowID: &windowID,
-			},
-		); err != nil {
-			return fmt.Errorf(
-				"unable to move window '%+v' to workspace '%s': %w",
-				window,
-				workspace.Workspace,
-				err,
-			)
-		}
+			err,
+		)
 	}
+	a.record(*window, workspace.Workspace)
 
 	if !shouldSetFocus {
 		return nil
 	}
 
-	// Use wrapper's SetFocusByWindowID if available (for dry-run support)
-	if wrapper, ok := a.aerospace.(*AeroSpaceClient); ok {
-		if err := wrapper.SetFocusByWindowID(window.WindowID); err != nil {
-			return fmt.Errorf(
-				"unable to set focus to window '%+v': %w",
-				window,
-				err,
-			)
-		}
-	} else {
-		// Fallback to direct service call
-		if err := a.aerospace.Focus().SetFocusByWindowID(window.WindowID); err != nil {
-			return fmt.Errorf(
-				"unable to set focus to window '%+v': %w",
-				window,
-				err,
-			)
-		}
+	if err := cli.Focus().SetFocusByWindowID(window.WindowID); err != nil {
+		return fmt.Errorf(
+			"unable to set focus to window '%+v': %w",
+			window,
+			err,
+		)
 	}
 
 	return nil
 }
 
-func (a *MoverAeroSpace) resolveScratchpadWorkspace() string {
+func resolveScratchpadWorkspace(cli AeroSpaceWMClient) string {
 	logger := logger.GetDefaultLogger()
 	targetWorkspace := constants.DefaultScratchpadWorkspaceName
 
-	monitor, err := GetFocusedMonitor(a.aerospace)
+	monitor, err := GetFocusedMonitor(cli)
 	if err != nil {
 		logger.LogError(
 			"MOVER: unable to get focused monitor, defaulting to base scratchpad",
@@ -168,7 +190,7 @@ func (a *MoverAeroSpace) resolveScratchpadWorkspace() string {
 	)
 
 	workspaceName, resolveErr := ResolveScratchpadWorkspaceNameForMonitor(
-		a.aerospace,
+		cli,
 		monitor.MonitorID,
 	)
 	if resolveErr != nil {
@@ -206,7 +228,7 @@ func (a *MoverAeroSpace) resolveScratchpadWorkspace() string {
 	return workspaceName
 }
 
-func (a *MoverAeroSpace) resolveScratchpadWorkspaceForMonitor(monitorID int) string {
+func resolveScratchpadWorkspaceForMonitor(cli AeroSpaceWMClient, monitorID int) string {
 	logger := logger.GetDefaultLogger()
 	targetWorkspace := constants.DefaultScratchpadWorkspaceName
 
@@ -225,7 +247,7 @@ func (a *MoverAeroSpace) resolveScratchpadWorkspaceForMonitor(monitorID int) str
 	)
 
 	workspaceName, resolveErr := ResolveScratchpadWorkspaceNameForMonitor(
-		a.aerospace,
+		cli,
 		monitorID,
 	)
 	if resolveErr != nil {
@@ -263,29 +285,21 @@ func (a *MoverAeroSpace) resolveScratchpadWorkspaceForMonitor(monitorID int) str
 	return workspaceName
 }
 
-func (a *MoverAeroSpace) moveWindowToScratchpadWorkspace(
+func moveWindowToScratchpadWorkspace(
+	cli AeroSpaceWMClient,
 	window windows.Window,
 	targetWorkspace string,
 ) error {
 	logger := logger.GetDefaultLogger()
-	// Use wrapper's MoveWindowToWorkspace if available (for dry-run support)
-	var err error
-	if wrapper, ok := a.aerospace.(*AeroSpaceClient); ok {
-		err = wrapper.MoveWindowToWorkspace(
-			window.WindowID,
-			targetWorkspace,
-		)
-	} else {
-		windowID := window.WindowID
-		err = a.aerospace.Workspaces().MoveWindowToWorkspaceWithOpts(
-			workspaces.MoveWindowToWorkspaceArgs{
-				WorkspaceName: targetWorkspace,
-			},
-			workspaces.MoveWindowToWorkspaceOpts{
-				WindowID: &windowID,
-			},
-		)
-	}
+	windowID := window.WindowID
+	err := cli.Workspaces().MoveWindowToWorkspaceWithOpts(
+		workspaces.MoveWindowToWorkspaceArgs{
+			WorkspaceName: targetWorkspace,
+		},
+		workspaces.MoveWindowToWorkspaceOpts{
+			WindowID: &windowID,
+		},
+	)
 	logger.LogDebug(
 		"MOVING: after MoveWindowToWorkspace",
 		"window", window,
@@ -296,14 +310,9 @@ func (a *MoverAeroSpace) moveWindowToScratchpadWorkspace(
 		return err
 	}
 
-	// Use wrapper's SetLayout if available (for dry-run support)
-	if wrapper, ok := a.aerospace.(*AeroSpaceClient); ok {
-		err = wrapper.SetLayout(window.WindowID, floatingLayout)
-	} else {
-		err = a.aerospace.Layout().SetLayout([]string{floatingLayout}, layout.SetLayoutOpts{
-			WindowID: layout.IntPtr(window.WindowID),
-		})
-	}
+	err = cli.Layout().SetLayout([]string{floatingLayout}, layout.SetLayoutOpts{
+		WindowID: layout.IntPtr(window.WindowID),
+	})
 	if err != nil {
 		logger.LogDebug(
 			"MOVER: unable to set layout to floating",
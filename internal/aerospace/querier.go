@@ -1,10 +1,12 @@
 package aerospace
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -18,63 +20,132 @@ type Querier interface {
 	// IsWindowInWorkspace checks if a window is in a workspace
 	//
 	// Returns true if the window is in the workspace
-	IsWindowInWorkspace(windowID int, workspaceName string) (bool, error)
+	IsWindowInWorkspace(ctx context.Context, windowID int, workspaceName string) (bool, error)
 
 	// IsWindowInFocusedWorkspace checks if a window is in the focused workspace
 	//
 	// Returns true if the window is in the focused workspace
-	IsWindowInFocusedWorkspace(windowID int) (bool, error)
+	IsWindowInFocusedWorkspace(ctx context.Context, windowID int) (bool, error)
 
 	// IsWindowFocused checks if a window is focused
 	//
 	// Returns true if the window is focused
-	IsWindowFocused(windowID int) (bool, error)
+	IsWindowFocused(ctx context.Context, windowID int) (bool, error)
 
 	// GetNextScratchpadWindow returns the next scratchpad window in the workspace
-	GetNextScratchpadWindow() (*windows.Window, error)
+	GetNextScratchpadWindow(ctx context.Context) (*windows.Window, error)
 
 	// GetNextScratchpadWindowForMonitor returns the next scratchpad window filtered by monitor.
 	// monitorID can be:
 	//   -1 for all monitors (same as GetNextScratchpadWindow)
 	//   -2 for current monitor
 	//   >=0 for specific monitor ID
-	GetNextScratchpadWindowForMonitor(monitorID int) (*windows.Window, error)
+	GetNextScratchpadWindowForMonitor(ctx context.Context, monitorID int) (*windows.Window, error)
 
 	// GetFilteredWindows returns all windows that match the given filters
 	GetFilteredWindows(
+		ctx context.Context,
 		windowNamePattern string,
 		filterFlags []string,
 	) ([]windows.Window, error)
 
-	// GetAllFloatingWindows returns all floating windows
-	GetAllFloatingWindows() ([]windows.Window, error)
+	// GetAllFloatingWindows returns all floating windows counted as
+	// scratchpad windows by the ScratchpadScope
+	GetAllFloatingWindows(ctx context.Context) ([]windows.Window, error)
 
 	// GetScratchpadWindows returns all scratchpad windows
 	// A scratchpad window is defined as:
 	// - A window in a scratchpad workspace (.scratchpad or .scratchpad.<monitor-id>), OR
-	// - A floating window (WindowLayout == floatingLayout)
-	GetScratchpadWindows() ([]windows.Window, error)
+	// - A window counted by the ScratchpadScope, by default a floating window
+	GetScratchpadWindows(ctx context.Context) ([]windows.Window, error)
 
 	// GetScratchpadWindowsForMonitor returns scratchpad windows filtered by monitor.
 	// monitorID can be:
 	//   -1 for all monitors (same as GetScratchpadWindows)
 	//   -2 for current monitor
 	//   >=0 for specific monitor ID
-	GetScratchpadWindowsForMonitor(monitorID int) ([]windows.Window, error)
+	GetScratchpadWindowsForMonitor(ctx context.Context, monitorID int) ([]windows.Window, error)
 }
 
 type QueryMaker struct {
-	cli AeroSpaceWMClient
+	cli   AeroSpaceWMClient
+	scope ScratchpadScope
+}
+
+// Scope tells which windows outside the scratchpad workspaces count as
+// scratchpad windows.
+type Scope string
+
+const (
+	// ScopeStrict counts the windows moved to a scratchpad workspace by
+	// aerospace-scratchpad, see ScratchpadScope.Managed.
+	ScopeStrict Scope = "strict"
+	// ScopeFloating counts the floating windows, the default.
+	ScopeFloating Scope = "floating"
+	// ScopeAll counts every window.
+	ScopeAll Scope = "all"
+)
+
+// Scopes lists the supported scopes.
+func Scopes() []Scope {
+	return []Scope{ScopeStrict, ScopeFloating, ScopeAll}
+}
+
+// ParseScope returns the scope named value.
+func ParseScope(value string) (Scope, error) {
+	scope := Scope(strings.ToLower(strings.TrimSpace(value)))
+	if !slices.Contains(Scopes(), scope) {
+		return "", fmt.Errorf("invalid scope: %s, expected strict, floating or all", value)
+	}
+	return scope, nil
+}
+
+// ScratchpadScope is the definition of a scratchpad window. The windows in a
+// scratchpad workspace always are one, the others depend on it.
+type ScratchpadScope struct {
+	// Scope is ScopeFloating when empty.
+	Scope Scope
+	// Managed are the windows moved to a scratchpad workspace by
+	// aerospace-scratchpad, the ones ScopeStrict counts.
+	Managed map[int]bool
+	// Exclude are the windows never counted outside a scratchpad workspace,
+	// each entry a set of filters that must all match.
+	Exclude [][]Filter
+}
+
+// Includes reports whether a window outside the scratchpad workspaces counts
+// as a scratchpad window.
+func (s ScratchpadScope) Includes(window windows.Window) (bool, error) {
+	for _, filters := range s.Exclude {
+		excluded, err := ApplyFilters(window, filters)
+		if err != nil {
+			return false, fmt.Errorf("invalid scratchpad exclusion: %w", err)
+		}
+		if excluded {
+			return false, nil
+		}
+	}
+
+	switch s.Scope {
+	case ScopeStrict:
+		return s.Managed[window.WindowID], nil
+	case ScopeAll:
+		return true, nil
+	case ScopeFloating, "":
+		return window.WindowLayout == floatingLayout, nil
+	default:
+		return false, fmt.Errorf("invalid scope: %s", s.Scope)
+	}
 }
 
 // resolveMonitorID resolves the monitor ID for filtering.
 // Returns target monitor ID or error.
-func (a *QueryMaker) resolveMonitorID(monitorID int) (int, error) {
+func (a *QueryMaker) resolveMonitorID(ctx context.Context, monitorID int) (int, error) {
 	logger := logger.GetDefaultLogger()
 	if monitorID != -2 {
 		return monitorID, nil
 	}
-	focusedMonitor, err := GetFocusedMonitor(a.cli)
+	focusedMonitor, err := GetFocusedMonitor(WithContext(ctx, a.cli))
 	if err != nil {
 		if strings.Contains(err.Error(), "no focused monitor found") {
 			logger.LogDebug("no focused monitor found, defaulting to all monitors")
@@ -249,6 +320,32 @@ func ListWorkspacesWithMonitors(cli AeroSpaceWMClient) ([]WorkspaceMonitor, erro
 	return workspaces, nil
 }
 
+// ListVisibleWorkspaces returns the workspaces shown on the monitors.
+func ListVisibleWorkspaces(cli AeroSpaceWMClient) ([]string, error) {
+	response, err := cli.Connection().SendCommand(
+		"list-workspaces",
+		[]string{"--monitor", "all", "--visible", jsonFlag},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list visible workspaces: %w", err)
+	}
+
+	if response.ExitCode != 0 {
+		return nil, fmt.Errorf("unable to list visible workspaces: %s", response.StdErr)
+	}
+
+	var workspaces []WorkspaceMonitor
+	if err = json.Unmarshal([]byte(response.StdOut), &workspaces); err != nil {
+		return nil, fmt.Errorf("unable to parse visible workspaces: %w", err)
+	}
+
+	names := make([]string, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		names = append(names, workspace.Workspace)
+	}
+	return names, nil
+}
+
 // GetFocusedMonitor returns the currently focused monitor metadata.
 func GetFocusedMonitor(cli AeroSpaceWMClient) (*MonitorInfo, error) {
 	response, err := cli.Connection().SendCommand(
@@ -331,11 +428,12 @@ func countUniqueMonitors(workspaces []WorkspaceMonitor) int {
 }
 
 func (a *QueryMaker) IsWindowInWorkspace(
+	ctx context.Context,
 	windowID int,
 	workspaceName string,
 ) (bool, error) {
 	// Get all windows from the workspace
-	wsWindows, err := a.cli.Windows().GetAllWindowsByWorkspace(workspaceName)
+	wsWindows, err := WithContext(ctx, a.cli).Windows().GetAllWindowsByWorkspace(workspaceName)
 	if err != nil {
 		return false, fmt.Errorf(
 			"unable to get windows from workspace '%s'. Reason: %w",
@@ -355,10 +453,11 @@ func (a *QueryMaker) IsWindowInWorkspace(
 }
 
 func (a *QueryMaker) IsWindowInFocusedWorkspace(
+	ctx context.Context,
 	windowID int,
 ) (bool, error) {
 	// Get the focused workspace
-	focusedWorkspace, err := a.cli.Workspaces().GetFocusedWorkspace()
+	focusedWorkspace, err := WithContext(ctx, a.cli).Workspaces().GetFocusedWorkspace()
 	if err != nil {
 		return false, fmt.Errorf(
 			"unable to get focused workspace, reason %w",
@@ -367,12 +466,12 @@ func (a *QueryMaker) IsWindowInFocusedWorkspace(
 	}
 
 	// Check if the window is in the focused workspace
-	return a.IsWindowInWorkspace(windowID, focusedWorkspace.Workspace)
+	return a.IsWindowInWorkspace(ctx, windowID, focusedWorkspace.Workspace)
 }
 
-func (a *QueryMaker) IsWindowFocused(windowID int) (bool, error) {
+func (a *QueryMaker) IsWindowFocused(ctx context.Context, windowID int) (bool, error) {
 	// Get the focused window
-	focusedWindow, err := a.cli.Windows().GetFocusedWindow()
+	focusedWindow, err := WithContext(ctx, a.cli).Windows().GetFocusedWindow()
 	if err != nil {
 		return false, fmt.Errorf("unable to get focused window, reason %w", err)
 	}
@@ -381,8 +480,8 @@ func (a *QueryMaker) IsWindowFocused(windowID int) (bool, error) {
 	return focusedWindow.WindowID == windowID, nil
 }
 
-func (a *QueryMaker) GetNextScratchpadWindow() (*windows.Window, error) {
-	scratchpadWindows, err := a.GetScratchpadWindows()
+func (a *QueryMaker) GetNextScratchpadWindow(ctx context.Context) (*windows.Window, error) {
+	scratchpadWindows, err := a.GetScratchpadWindows(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -394,8 +493,11 @@ func (a *QueryMaker) GetNextScratchpadWindow() (*windows.Window, error) {
 	return &scratchpadWindows[0], nil
 }
 
-func (a *QueryMaker) GetNextScratchpadWindowForMonitor(monitorID int) (*windows.Window, error) {
-	scratchpadWindows, err := a.GetScratchpadWindowsForMonitor(monitorID)
+func (a *QueryMaker) GetNextScratchpadWindowForMonitor(
+	ctx context.Context,
+	monitorID int,
+) (*windows.Window, error) {
+	scratchpadWindows, err := a.GetScratchpadWindowsForMonitor(ctx, monitorID)
 	if err != nil {
 		return nil, err
 	}
@@ -436,7 +538,20 @@ type Filter struct {
 
 const filterPartsExpected = 2
 
+// FilterProperties lists the window properties a Filter can match.
+//
+//nolint:gochecknoglobals // static list of properties
+var FilterProperties = []string{
+	"app-name",
+	"window-title",
+	"app-bundle-id",
+	"window-id",
+	"workspace",
+	"window-layout",
+}
+
 func (a *QueryMaker) GetFilteredWindows(
+	ctx context.Context,
 	appNamePattern string,
 	filterFlags []string,
 ) ([]windows.Window, error) {
@@ -465,7 +580,7 @@ func (a *QueryMaker) GetFilteredWindows(
 		return nil, err
 	}
 
-	allWindows, err := a.cli.Windows().GetAllWindows()
+	allWindows, err := WithContext(ctx, a.cli).Windows().GetAllWindows()
 	if err != nil {
 		logger.LogError("FILTER: unable to get all windows", "error", err)
 		return nil, fmt.Errorf("unable to get windows: %w", err)
@@ -514,10 +629,10 @@ func (a *QueryMaker) GetFilteredWindows(
 	return filteredWindows, nil
 }
 
-func (a *QueryMaker) GetAllFloatingWindows() ([]windows.Window, error) {
+func (a *QueryMaker) GetAllFloatingWindows(ctx context.Context) ([]windows.Window, error) {
 	logger := logger.GetDefaultLogger()
 
-	allWindows, err := a.cli.Windows().GetAllWindows()
+	allWindows, err := WithContext(ctx, a.cli).Windows().GetAllWindows()
 	if err != nil {
 		logger.LogError("FILTER: unable to get all windows", "error", err)
 		return nil, fmt.Errorf("unable to get windows: %w", err)
@@ -525,7 +640,14 @@ func (a *QueryMaker) GetAllFloatingWindows() ([]windows.Window, error) {
 
 	var floatingWindows []windows.Window
 	for _, window := range allWindows {
-		if window.WindowLayout == floatingLayout {
+		if window.WindowLayout != floatingLayout {
+			continue
+		}
+		included, includeErr := a.scope.Includes(window)
+		if includeErr != nil {
+			return nil, includeErr
+		}
+		if included {
 			floatingWindows = append(floatingWindows, window)
 		}
 	}
@@ -538,16 +660,17 @@ func (a *QueryMaker) GetAllFloatingWindows() ([]windows.Window, error) {
 	return floatingWindows, nil
 }
 
-func (a *QueryMaker) GetScratchpadWindows() ([]windows.Window, error) {
+func (a *QueryMaker) GetScratchpadWindows(ctx context.Context) ([]windows.Window, error) {
 	logger := logger.GetDefaultLogger()
+	cli := WithContext(ctx, a.cli)
 
-	allWindows, err := a.cli.Windows().GetAllWindows()
+	allWindows, err := cli.Windows().GetAllWindows()
 	if err != nil {
 		logger.LogError("FILTER: unable to get all windows", "error", err)
 		return nil, fmt.Errorf("unable to get windows: %w", err)
 	}
 
-	scratchpadWorkspaces, err := ListScratchpadWorkspaceNames(a.cli)
+	scratchpadWorkspaces, err := ListScratchpadWorkspaceNames(cli)
 	if err != nil {
 		logger.LogError(
 			"FILTER: unable to list scratchpad workspaces",
@@ -561,7 +684,7 @@ func (a *QueryMaker) GetScratchpadWindows() ([]windows.Window, error) {
 
 	for _, workspace := range scratchpadWorkspaces {
 		// Get windows from scratchpad workspace
-		scratchpadWorkspaceWindows, workspaceErr := a.cli.Windows().GetAllWindowsByWorkspace(
+		scratchpadWorkspaceWindows, workspaceErr := cli.Windows().GetAllWindowsByWorkspace(
 			workspace,
 		)
 		if workspaceErr != nil {
@@ -580,13 +703,18 @@ func (a *QueryMaker) GetScratchpadWindows() ([]windows.Window, error) {
 		}
 	}
 
-	// Add floating windows
+	// Add the windows counted by the scope, floating ones by default
 	for _, window := range allWindows {
-		if window.WindowLayout == floatingLayout {
-			// Only add if not already in map (avoid duplicates)
-			if _, exists := scratchpadWindowMap[window.WindowID]; !exists {
-				scratchpadWindowMap[window.WindowID] = window
-			}
+		// Only add if not already in map (avoid duplicates)
+		if _, exists := scratchpadWindowMap[window.WindowID]; exists {
+			continue
+		}
+		included, includeErr := a.scope.Includes(window)
+		if includeErr != nil {
+			return nil, includeErr
+		}
+		if included {
+			scratchpadWindowMap[window.WindowID] = window
 		}
 	}
 
@@ -615,17 +743,20 @@ func (a *QueryMaker) GetScratchpadWindows() ([]windows.Window, error) {
 //	-1 for all monitors (same as GetScratchpadWindows)
 //	-2 for current monitor
 //	>=0 for specific monitor ID
-func (a *QueryMaker) GetScratchpadWindowsForMonitor(monitorID int) ([]windows.Window, error) {
+func (a *QueryMaker) GetScratchpadWindowsForMonitor(
+	ctx context.Context,
+	monitorID int,
+) ([]windows.Window, error) {
 	logger := logger.GetDefaultLogger()
 
 	// Resolve monitor ID if needed
-	targetMonitorID, err := a.resolveMonitorID(monitorID)
+	targetMonitorID, err := a.resolveMonitorID(ctx, monitorID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get all scratchpad windows
-	allWindows, err := a.GetScratchpadWindows()
+	allWindows, err := a.GetScratchpadWindows(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -636,7 +767,7 @@ func (a *QueryMaker) GetScratchpadWindowsForMonitor(monitorID int) ([]windows.Wi
 	}
 
 	// Get workspace-to-monitor mapping
-	workspaces, err := ListWorkspacesWithMonitors(a.cli)
+	workspaces, err := ListWorkspacesWithMonitors(WithContext(ctx, a.cli))
 	if err != nil {
 		logger.LogDebug(
 			"unable to list workspaces with monitors, skipping monitor filtering",
@@ -783,3 +914,12 @@ func NewAerospaceQuerier(cli AeroSpaceWMClient) Querier {
 		cli: cli,
 	}
 }
+
+// NewAerospaceQuerierWithScope creates a new AerospaceQuerier counting the
+// scratchpad windows of scope.
+func NewAerospaceQuerierWithScope(cli AeroSpaceWMClient, scope ScratchpadScope) Querier {
+	return &QueryMaker{
+		cli:   cli,
+		scope: scope,
+	}
+}
@@ -39,7 +39,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 			Times(1)
 
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		in, err := q.IsWindowInWorkspace(2, workspace)
+		in, err := q.IsWindowInWorkspace(t.Context(), 2, workspace)
 		if err != nil {
 			t.Fatalf("unexpected err: %v", err)
 		}
@@ -61,7 +61,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 			Return(windowsList, nil).
 			Times(1)
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		in, err := q.IsWindowInWorkspace(3, workspace)
+		in, err := q.IsWindowInWorkspace(t.Context(), 3, workspace)
 		if err != nil {
 			t.Fatalf("unexpected err: %v", err)
 		}
@@ -90,7 +90,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 		)
 
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		in, err := q.IsWindowInFocusedWorkspace(5)
+		in, err := q.IsWindowInFocusedWorkspace(t.Context(), 5)
 		if err != nil || !in {
 			t.Fatalf("expected true, got %v err=%v", in, err)
 		}
@@ -107,7 +107,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 			Return(focused, nil).
 			Times(1)
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		is, err := q.IsWindowFocused(10)
+		is, err := q.IsWindowFocused(t.Context(), 10)
 		if err != nil || !is {
 			t.Fatalf("expected true, got %v err=%v", is, err)
 		}
@@ -124,7 +124,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 			Return(focused, nil).
 			Times(1)
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		is, err := q.IsWindowFocused(11)
+		is, err := q.IsWindowFocused(t.Context(), 11)
 		if err != nil || is {
 			t.Fatalf("expected false, got %v err=%v", is, err)
 		}
@@ -147,7 +147,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 				Times(1),
 		)
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		w, err := q.GetNextScratchpadWindow()
+		w, err := q.GetNextScratchpadWindow(t.Context())
 		if err != nil || w == nil || w.WindowID != 77 {
 			t.Fatalf("expected 77, got %v err=%v", w, err)
 		}
@@ -171,7 +171,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 					Times(1),
 			)
 			q := aerospace.NewAerospaceQuerier(mockClient)
-			if _, err := q.GetNextScratchpadWindow(); err == nil {
+			if _, err := q.GetNextScratchpadWindow(t.Context()); err == nil {
 				t.Fatalf("expected error when no scratchpad windows")
 			}
 		},
@@ -202,7 +202,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 		)
 
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		wins, err := q.GetScratchpadWindows()
+		wins, err := q.GetScratchpadWindows(t.Context())
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -233,7 +233,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 		)
 
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		wins, err := q.GetScratchpadWindows()
+		wins, err := q.GetScratchpadWindows(t.Context())
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -266,7 +266,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 		)
 
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		wins, err := q.GetScratchpadWindows()
+		wins, err := q.GetScratchpadWindows(t.Context())
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -318,7 +318,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 		)
 
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		wins, err := q.GetScratchpadWindows()
+		wins, err := q.GetScratchpadWindows(t.Context())
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -380,7 +380,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 				Return(all, nil).
 				Times(1)
 			q := aerospace.NewAerospaceQuerier(mockClient)
-			wins, err := q.GetFilteredWindows("Finder", nil)
+			wins, err := q.GetFilteredWindows(t.Context(), "Finder", nil)
 			if err != nil || len(wins) != 2 {
 				t.Fatalf(
 					"expected 2 finder windows, got %d err=%v",
@@ -427,6 +427,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 			Times(1)
 		q := aerospace.NewAerospaceQuerier(mockClient)
 		wins, err := q.GetFilteredWindows(
+			t.Context(),
 			"Finder",
 			[]string{"window-title=foo", "app-bundle-id=apple"},
 		)
@@ -441,7 +442,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 
 		mockClient := testutils.NewMockAeroSpaceWM(ctrl)
 		q := aerospace.NewAerospaceQuerier(mockClient)
-		if _, err := q.GetFilteredWindows("[invalid", nil); err == nil {
+		if _, err := q.GetFilteredWindows(t.Context(), "[invalid", nil); err == nil {
 			t.Fatalf("expected invalid pattern error")
 		}
 	})
@@ -461,7 +462,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 				Return(all, nil).
 				Times(1)
 			q := aerospace.NewAerospaceQuerier(mockClient)
-			if _, err := q.GetFilteredWindows("Finder", []string{"unknown=foo"}); err == nil {
+			if _, err := q.GetFilteredWindows(t.Context(), "Finder", []string{"unknown=foo"}); err == nil {
 				t.Fatalf("expected unknown property error")
 			}
 		},
@@ -492,7 +493,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 				Return(all, nil).
 				Times(1)
 			q := aerospace.NewAerospaceQuerier(mockClient)
-			if _, err := q.GetFilteredWindows("Finder", nil); err == nil {
+			if _, err := q.GetFilteredWindows(t.Context(), "Finder", nil); err == nil {
 				t.Fatalf("expected no match error")
 			}
 		},
@@ -510,7 +511,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 				Return(nil, errors.New("mocked_error")).
 				Times(1)
 			q := aerospace.NewAerospaceQuerier(mockClient)
-			if _, err := q.GetFilteredWindows("Finder", nil); err == nil {
+			if _, err := q.GetFilteredWindows(t.Context(), "Finder", nil); err == nil {
 				t.Fatalf("expected get windows error")
 			}
 		},
@@ -548,6 +549,7 @@ func TestAeroSpaceQuerier(t *testing.T) {
 				Times(1)
 			q := aerospace.NewAerospaceQuerier(mockClient)
 			wins, err := q.GetFilteredWindows(
+				t.Context(),
 				"Terminal",
 				[]string{"window-id=1"},
 			)
@@ -853,3 +855,70 @@ func (m *mockConnectionAeroSpaceClient) Layout() *layout.Service {
 func (m *mockConnectionAeroSpaceClient) Connection() client.AeroSpaceConnection {
 	return m.conn
 }
+
+func TestScratchpadScope(t *testing.T) {
+	floating := windows.Window{WindowID: 1, AppName: "Notes", WindowLayout: "floating"}
+	tiling := windows.Window{WindowID: 2, AppName: "Ghostty", WindowLayout: "tiling"}
+	pip := windows.Window{
+		WindowID:     3,
+		AppName:      "Firefox",
+		WindowTitle:  "Picture-in-Picture",
+		WindowLayout: "floating",
+	}
+
+	exclude, err := aerospace.ParseFilters([]string{"window-title=^Picture-in-Picture$"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		scope    aerospace.ScratchpadScope
+		window   windows.Window
+		expected bool
+	}{
+		{"empty counts floating", aerospace.ScratchpadScope{}, floating, true},
+		{"empty ignores tiling", aerospace.ScratchpadScope{}, tiling, false},
+		{
+			"strict ignores unmanaged floating",
+			aerospace.ScratchpadScope{Scope: aerospace.ScopeStrict},
+			floating,
+			false,
+		},
+		{
+			"strict counts managed tiling",
+			aerospace.ScratchpadScope{Scope: aerospace.ScopeStrict, Managed: map[int]bool{2: true}},
+			tiling,
+			true,
+		},
+		{"all counts tiling", aerospace.ScratchpadScope{Scope: aerospace.ScopeAll}, tiling, true},
+		{
+			"exclusions win over the scope",
+			aerospace.ScratchpadScope{Scope: aerospace.ScopeAll, Exclude: [][]aerospace.Filter{exclude}},
+			pip,
+			false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, includeErr := tc.scope.Includes(tc.window)
+			if includeErr != nil {
+				t.Fatalf("unexpected error: %v", includeErr)
+			}
+			if got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+
+	t.Run("ParseScope", func(t *testing.T) {
+		if scope, parseErr := aerospace.ParseScope(" Strict "); parseErr != nil ||
+			scope != aerospace.ScopeStrict {
+			t.Fatalf("expected strict, got %q, %v", scope, parseErr)
+		}
+		if _, parseErr := aerospace.ParseScope("tiling"); parseErr == nil {
+			t.Fatal("expected an error for an unknown scope")
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package aerospace
+
+import (
+	"sync"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
+)
+
+// DialFunc opens a new connection to AeroSpace.
+type DialFunc func() (client.AeroSpaceConnection, error)
+
+// DialSocket dials the AeroSpace socket at socketPath.
+func DialSocket(socketPath string) DialFunc {
+	return func() (client.AeroSpaceConnection, error) {
+		return client.NewAeroSpaceSocketConnection(socketPath)
+	}
+}
+
+// ReconnectingConnection replaces its connection after a transport failure,
+// e.g. when AeroSpace restarts. The failed request still returns its error,
+// retrying it is up to the caller (see RetryInterceptor).
+type ReconnectingConnection struct {
+	mu   sync.Mutex
+	conn client.AeroSpaceConnection
+	dial DialFunc
+}
+
+// NewReconnectingConnection wraps conn and uses dial to replace it once it breaks.
+func NewReconnectingConnection(
+	conn client.AeroSpaceConnection,
+	dial DialFunc,
+) *ReconnectingConnection {
+	return &ReconnectingConnection{
+		conn: conn,
+		dial: dial,
+	}
+}
+
+// SendCommand sends the command over the current connection.
+func (r *ReconnectingConnection) SendCommand(command string, args []string) (*client.Response, error) {
+	conn := r.Unwrap()
+	response, err := conn.SendCommand(command, args)
+	if err != nil && isTransportError(err) {
+		r.reconnect(conn, err)
+	}
+	return response, err
+}
+
+// Unwrap returns the current connection.
+func (r *ReconnectingConnection) Unwrap() client.AeroSpaceConnection {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.conn
+}
+
+// GetSocketPath returns the socket path of the current connection.
+func (r *ReconnectingConnection) GetSocketPath() (string, error) {
+	return r.Unwrap().GetSocketPath()
+}
+
+// GetServerVersion returns the server version of the current connection.
+func (r *ReconnectingConnection) GetServerVersion() (string, error) {
+	return r.Unwrap().GetServerVersion()
+}
+
+// CheckServerVersion checks the server version of the current connection.
+func (r *ReconnectingConnection) CheckServerVersion() error {
+	return r.Unwrap().CheckServerVersion()
+}
+
+// CloseConnection closes the current connection.
+func (r *ReconnectingConnection) CloseConnection() error {
+	return r.Unwrap().CloseConnection()
+}
+
+// reconnect replaces failed, unless another request already did it.
+func (r *ReconnectingConnection) reconnect(failed client.AeroSpaceConnection, cause error) {
+	log := logger.GetDefaultLogger()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn != failed {
+		return
+	}
+
+	_ = failed.CloseConnection()
+	fresh, err := r.dial()
+	if err != nil {
+		log.LogError("IPC: unable to reconnect", "cause", cause, "error", err)
+		return
+	}
+
+	log.LogInfo("IPC: reconnected", "cause", cause)
+	r.conn = fresh
+}
@@ -0,0 +1,135 @@
+package aerospacetoml
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// UnifiedDiff returns the changes from before to after in the unified
+// format, or an empty string when they are the same.
+func UnifiedDiff(beforeName, afterName, before, after string) string {
+	if before == after {
+		return ""
+	}
+
+	ops := diffLines(splitLines(before), splitLines(after))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", beforeName, afterName)
+
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		// Grow the hunk while the changes are closer than twice the context.
+		hunkStart := max(start-diffContext, 0)
+		end := start
+		for index := start; index < len(ops); index++ {
+			if ops[index].kind != ' ' {
+				end = index + 1
+				continue
+			}
+			if index-end >= 2*diffContext {
+				break
+			}
+		}
+		hunkEnd := min(end+diffContext, len(ops))
+
+		writeHunk(&out, ops, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+
+	return out.String()
+}
+
+func writeHunk(out *strings.Builder, ops []diffOp, start, end int) {
+	beforeLine, afterLine := 1, 1
+	for _, op := range ops[:start] {
+		if op.kind != '+' {
+			beforeLine++
+		}
+		if op.kind != '-' {
+			afterLine++
+		}
+	}
+
+	beforeCount, afterCount := 0, 0
+	for _, op := range ops[start:end] {
+		if op.kind != '+' {
+			beforeCount++
+		}
+		if op.kind != '-' {
+			afterCount++
+		}
+	}
+	// An empty range starts at the line before it.
+	if beforeCount == 0 {
+		beforeLine--
+	}
+	if afterCount == 0 {
+		afterLine--
+	}
+
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", beforeLine, beforeCount, afterLine, afterCount)
+	for _, op := range ops[start:end] {
+		fmt.Fprintf(out, "%c%s\n", op.kind, op.line)
+	}
+}
+
+// diffLines aligns the lines on their longest common subsequence, the
+// config files are small enough for the quadratic table.
+func diffLines(before, after []string) []diffOp {
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(before)+len(after))
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			ops = append(ops, diffOp{kind: ' ', line: before[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			ops = append(ops, diffOp{kind: '-', line: before[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: after[j]})
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		ops = append(ops, diffOp{kind: '-', line: before[i]})
+	}
+	for ; j < len(after); j++ {
+		ops = append(ops, diffOp{kind: '+', line: after[j]})
+	}
+	return ops
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
@@ -0,0 +1,400 @@
+package aerospacetoml
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Hook is a hook subcommand of aerospace-scratchpad that can be installed.
+type Hook string
+
+const (
+	HookPullWindow   Hook = "pull-window"
+	HookFollow       Hook = "follow"
+	HookFocusChanged Hook = "focus-changed"
+)
+
+// Hooks lists the hooks that can be installed.
+//
+//nolint:gochecknoglobals // static list of hooks
+var Hooks = []Hook{HookPullWindow, HookFollow, HookFocusChanged}
+
+const (
+	workspaceChangeKey = "exec-on-workspace-change"
+	focusChangedKey    = "on-focus-changed"
+	bindingTable       = "mode.main.binding"
+
+	// The changes are written between these markers, with the lines they
+	// replaced kept as originalPrefix comments, so Uninstall can revert them.
+	blockBegin     = "# >>> aerospace-scratchpad hook install >>>"
+	blockEnd       = "# <<< aerospace-scratchpad hook install <<<"
+	originalPrefix = "#| "
+)
+
+// Keybinding is a binding of [mode.main.binding].
+type Keybinding struct {
+	Key     string
+	Command string
+}
+
+// Options configures Install.
+type Options struct {
+	// Program is how AeroSpace runs aerospace-scratchpad.
+	Program string
+	Hooks   []Hook
+	// Keybindings are added unless their key or their command is bound.
+	Keybindings []Keybinding
+}
+
+// SuggestedKeybindings returns the keybindings Install adds by default.
+func SuggestedKeybindings(program string) []Keybinding {
+	return []Keybinding{
+		{Key: "ctrl-minus", Command: fmt.Sprintf("exec-and-forget %s next", program)},
+		{Key: "cmd-shift-h", Command: fmt.Sprintf("exec-and-forget %s move --all-floating", program)},
+	}
+}
+
+// Result is the patched config and what was left out, and why.
+type Result struct {
+	Content string
+	Notes   []string
+}
+
+type edit struct {
+	startLine int
+	endLine   int
+	lines     []string
+	order     int
+}
+
+// Install merges the hooks and keybindings into the config content. The
+// existing exec-on-workspace-change and on-focus-changed commands are kept
+// and run before the hooks. A previous install is replaced.
+func Install(content string, opts Options) (Result, error) {
+	content, _, err := Uninstall(content)
+	if err != nil {
+		return Result{}, err
+	}
+	doc, err := parseDocument(content)
+	if err != nil {
+		return Result{}, fmt.Errorf("unable to parse the config: %w", err)
+	}
+
+	var result Result
+	var edits []edit
+
+	var workspaceHooks []Hook
+	for _, hook := range opts.Hooks {
+		switch hook {
+		case HookPullWindow, HookFollow:
+			workspaceHooks = append(workspaceHooks, hook)
+		case HookFocusChanged:
+		default:
+			return Result{}, fmt.Errorf("unknown hook %q", hook)
+		}
+	}
+
+	if len(workspaceHooks) > 0 {
+		workspaceEdit, notes, editErr := mergeWorkspaceChange(doc, opts.Program, workspaceHooks)
+		if editErr != nil {
+			return Result{}, editErr
+		}
+		result.Notes = append(result.Notes, notes...)
+		if workspaceEdit != nil {
+			edits = append(edits, *workspaceEdit)
+		}
+	}
+
+	if slices.Contains(opts.Hooks, HookFocusChanged) {
+		focusEdit, notes, editErr := mergeFocusChanged(doc, opts.Program)
+		if editErr != nil {
+			return Result{}, editErr
+		}
+		result.Notes = append(result.Notes, notes...)
+		if focusEdit != nil {
+			edits = append(edits, *focusEdit)
+		}
+	}
+
+	if len(opts.Keybindings) > 0 {
+		bindingEdit, notes, editErr := mergeKeybindings(doc, opts.Keybindings)
+		if editErr != nil {
+			return Result{}, editErr
+		}
+		result.Notes = append(result.Notes, notes...)
+		if bindingEdit != nil {
+			edits = append(edits, *bindingEdit)
+		}
+	}
+
+	result.Content = applyEdits(doc.lines, edits)
+	return result, nil
+}
+
+// Uninstall reverts Install, it reports whether there was anything to revert.
+func Uninstall(content string) (string, bool, error) {
+	lines := strings.Split(content, "\n")
+	reverted := make([]string, 0, len(lines))
+	inBlock := false
+	found := false
+	for number, line := range lines {
+		switch {
+		case strings.TrimSpace(line) == blockBegin:
+			if inBlock {
+				return "", false, fmt.Errorf("line %d: nested install block", number+1)
+			}
+			inBlock, found = true, true
+		case strings.TrimSpace(line) == blockEnd:
+			if !inBlock {
+				return "", false, fmt.Errorf("line %d: install block end without begin", number+1)
+			}
+			inBlock = false
+		case inBlock:
+			if original, isOriginal := strings.CutPrefix(line, originalPrefix); isOriginal {
+				reverted = append(reverted, original)
+			} else if line == strings.TrimSpace(originalPrefix) {
+				reverted = append(reverted, "")
+			}
+		default:
+			reverted = append(reverted, line)
+		}
+	}
+	if inBlock {
+		return "", false, errors.New("unterminated install block")
+	}
+
+	return strings.Join(reverted, "\n"), found, nil
+}
+
+// HasHook reports whether the config runs the hook, installed or by hand.
+func HasHook(content string, hook Hook) (bool, error) {
+	doc, err := parseDocument(content)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse the config: %w", err)
+	}
+
+	key := workspaceChangeKey
+	if hook == HookFocusChanged {
+		key = focusChangedKey
+	}
+	stmt, exists := doc.find("", key)
+	if !exists {
+		return false, nil
+	}
+	items, err := parseStringArray(stmt.value)
+	if err != nil {
+		return false, fmt.Errorf("unable to read %s: %w", key, err)
+	}
+	return runsHook(items, hook), nil
+}
+
+func runsHook(items []string, hook Hook) bool {
+	return slices.ContainsFunc(items, func(item string) bool {
+		return strings.Contains(item, "hook "+string(hook))
+	})
+}
+
+// workspaceScript is the shell command running the hook on workspace
+// changes. The variables are quoted for workspace names with spaces.
+func workspaceScript(program string, hook Hook) string {
+	if hook == HookFollow {
+		return fmt.Sprintf(`%s hook follow "$AEROSPACE_FOCUSED_WORKSPACE"`, program)
+	}
+	return fmt.Sprintf(
+		`%s hook pull-window "$AEROSPACE_PREV_WORKSPACE" "$AEROSPACE_FOCUSED_WORKSPACE"`,
+		program,
+	)
+}
+
+// mergeWorkspaceChange appends the hooks to the shell command of
+// exec-on-workspace-change, or wraps the existing command in a shell.
+func mergeWorkspaceChange(doc *document, program string, hooks []Hook) (*edit, []string, error) {
+	stmt, exists := doc.find("", workspaceChangeKey)
+	if !exists {
+		scripts := make([]string, 0, len(hooks))
+		for _, hook := range hooks {
+			scripts = append(scripts, workspaceScript(program, hook))
+		}
+		argv := []string{"/bin/bash", "-c", strings.Join(scripts, "; ")}
+		return &edit{
+			startLine: doc.topLevelEnd(),
+			endLine:   doc.topLevelEnd(),
+			lines:     block(nil, workspaceChangeKey+" = "+encodeStringArray(argv)),
+			order:     0,
+		}, nil, nil
+	}
+
+	argv, err := parseStringArray(stmt.value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read %s: %w", workspaceChangeKey, err)
+	}
+
+	var notes []string
+	var missing []string
+	for _, hook := range hooks {
+		if runsHook(argv, hook) {
+			notes = append(notes, fmt.Sprintf("%s already runs hook %s", workspaceChangeKey, hook))
+			continue
+		}
+		missing = append(missing, workspaceScript(program, hook))
+	}
+	if len(missing) == 0 {
+		return nil, notes, nil
+	}
+
+	script := strings.Join(missing, "; ")
+	if len(argv) >= 3 && argv[1] == "-c" && strings.HasSuffix(argv[0], "sh") {
+		argv[2] = strings.TrimRight(argv[2], " \t\r\n;") + "; " + script
+	} else {
+		argv = []string{"/bin/bash", "-c", shellJoin(argv) + "; " + script}
+	}
+
+	return &edit{
+		startLine: stmt.startLine,
+		endLine:   stmt.endLine,
+		lines: block(
+			doc.lines[stmt.startLine:stmt.endLine],
+			workspaceChangeKey+" = "+encodeStringArray(argv),
+		),
+		order: 0,
+	}, notes, nil
+}
+
+// mergeFocusChanged adds the focus-changed hook to the on-focus-changed
+// commands.
+func mergeFocusChanged(doc *document, program string) (*edit, []string, error) {
+	command := fmt.Sprintf("exec-and-forget %s hook focus-changed", program)
+
+	stmt, exists := doc.find("", focusChangedKey)
+	if !exists {
+		return &edit{
+			startLine: doc.topLevelEnd(),
+			endLine:   doc.topLevelEnd(),
+			lines:     block(nil, focusChangedKey+" = "+encodeStringArray([]string{command})),
+			order:     1,
+		}, nil, nil
+	}
+
+	commands, err := parseStringArray(stmt.value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read %s: %w", focusChangedKey, err)
+	}
+	if runsHook(commands, HookFocusChanged) {
+		return nil, []string{fmt.Sprintf("%s already runs hook %s", focusChangedKey, HookFocusChanged)}, nil
+	}
+
+	return &edit{
+		startLine: stmt.startLine,
+		endLine:   stmt.endLine,
+		lines: block(
+			doc.lines[stmt.startLine:stmt.endLine],
+			focusChangedKey+" = "+encodeStringArray(append(commands, command)),
+		),
+		order: 1,
+	}, nil, nil
+}
+
+// mergeKeybindings adds the keybindings whose key and command aren't bound
+// in [mode.main.binding] yet.
+func mergeKeybindings(doc *document, keybindings []Keybinding) (*edit, []string, error) {
+	var notes []string
+	var lines []string
+	for _, keybinding := range keybindings {
+		stmt, keyBound := doc.find(bindingTable, keybinding.Key)
+		if keyBound {
+			notes = append(notes, fmt.Sprintf(
+				"%s is bound already (%s), skipping %q", keybinding.Key, stmt.value, keybinding.Command,
+			))
+			continue
+		}
+		if boundTo, bound := findBinding(doc, keybinding.Command); bound {
+			notes = append(notes, fmt.Sprintf("%q is bound to %s already", keybinding.Command, boundTo))
+			continue
+		}
+		lines = append(lines, keybinding.Key+" = "+encodeString(keybinding.Command))
+	}
+	if len(lines) == 0 {
+		return nil, notes, nil
+	}
+
+	bindingHeader, exists := doc.findHeader(bindingTable)
+	if !exists {
+		end := doc.end()
+		return &edit{
+			startLine: end,
+			endLine:   end,
+			lines:     block(nil, append([]string{"[" + bindingTable + "]"}, lines...)...),
+			order:     2,
+		}, notes, nil
+	}
+
+	return &edit{
+		startLine: bindingHeader.line + 1,
+		endLine:   bindingHeader.line + 1,
+		lines:     block(nil, lines...),
+		order:     2,
+	}, notes, nil
+}
+
+func findBinding(doc *document, command string) (string, bool) {
+	for _, stmt := range doc.statements {
+		if stmt.table != bindingTable {
+			continue
+		}
+		commands, err := parseStringArray(stmt.value)
+		if err != nil {
+			continue
+		}
+		for _, bound := range commands {
+			if strings.Join(strings.Fields(bound), " ") == command {
+				return stmt.key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// block wraps the new lines in the install markers, keeping the original
+// lines they replace as comments.
+func block(original []string, lines ...string) []string {
+	wrapped := []string{blockBegin}
+	for _, line := range original {
+		if line == "" {
+			wrapped = append(wrapped, strings.TrimSpace(originalPrefix))
+			continue
+		}
+		wrapped = append(wrapped, originalPrefix+line)
+	}
+	wrapped = append(wrapped, lines...)
+	return append(wrapped, blockEnd)
+}
+
+func applyEdits(lines []string, edits []edit) string {
+	slices.SortFunc(edits, func(a, b edit) int {
+		if a.startLine != b.startLine {
+			return b.startLine - a.startLine
+		}
+		return b.order - a.order
+	})
+
+	patched := slices.Clone(lines)
+	for _, change := range edits {
+		patched = slices.Replace(patched, change.startLine, change.endLine, change.lines...)
+	}
+	return strings.Join(patched, "\n")
+}
+
+// shellJoin quotes the arguments for a shell command line.
+func shellJoin(argv []string) string {
+	quoted := make([]string, 0, len(argv))
+	for _, arg := range argv {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,193 @@
+package aerospacetoml_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospacetoml"
+)
+
+const userConfig = `# My AeroSpace config
+start-at-login = true
+
+# Keep sketchybar in sync
+exec-on-workspace-change = ["/bin/bash", "-c",
+  "sketchybar --trigger aerospace_workspace_change FOCUSED=$AEROSPACE_FOCUSED_WORKSPACE"
+]
+
+[mode.main.binding]
+alt-1 = "workspace 1" # first workspace
+ctrl-minus = "exec-and-forget aerospace-scratchpad show Notes"
+`
+
+func defaultOptions() aerospacetoml.Options {
+	return aerospacetoml.Options{
+		Program:     "aerospace-scratchpad",
+		Hooks:       []aerospacetoml.Hook{aerospacetoml.HookPullWindow},
+		Keybindings: aerospacetoml.SuggestedKeybindings("aerospace-scratchpad"),
+	}
+}
+
+func TestInstall(t *testing.T) {
+	t.Run("merges the hooks into the existing commands", func(t *testing.T) {
+		result, err := aerospacetoml.Install(userConfig, defaultOptions())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := `# My AeroSpace config
+start-at-login = true
+
+# Keep sketchybar in sync
+# >>> aerospace-scratchpad hook install >>>
+#| exec-on-workspace-change = ["/bin/bash", "-c",
+#|   "sketchybar --trigger aerospace_workspace_change FOCUSED=$AEROSPACE_FOCUSED_WORKSPACE"
+#| ]
+exec-on-workspace-change = ['/bin/bash', '-c', 'sketchybar --trigger aerospace_workspace_change ` +
+			`FOCUSED=$AEROSPACE_FOCUSED_WORKSPACE; aerospace-scratchpad hook pull-window ` +
+			`"$AEROSPACE_PREV_WORKSPACE" "$AEROSPACE_FOCUSED_WORKSPACE"']
+# <<< aerospace-scratchpad hook install <<<
+
+[mode.main.binding]
+# >>> aerospace-scratchpad hook install >>>
+cmd-shift-h = 'exec-and-forget aerospace-scratchpad move --all-floating'
+# <<< aerospace-scratchpad hook install <<<
+alt-1 = "workspace 1" # first workspace
+ctrl-minus = "exec-and-forget aerospace-scratchpad show Notes"
+`
+		if result.Content != expected {
+			t.Fatalf("unexpected config:\n%s", result.Content)
+		}
+		if len(result.Notes) != 1 || !strings.Contains(result.Notes[0], "ctrl-minus is bound already") {
+			t.Fatalf("expected a note about ctrl-minus, got %q", result.Notes)
+		}
+
+		hasHook, err := aerospacetoml.HasHook(result.Content, aerospacetoml.HookPullWindow)
+		if err != nil || !hasHook {
+			t.Fatalf("expected the pull-window hook installed, got %v %v", hasHook, err)
+		}
+	})
+
+	t.Run("is reverted by uninstall", func(t *testing.T) {
+		opts := defaultOptions()
+		opts.Hooks = aerospacetoml.Hooks
+		result, err := aerospacetoml.Install(userConfig, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		reverted, found, err := aerospacetoml.Uninstall(result.Content)
+		if err != nil || !found {
+			t.Fatalf("expected an install to revert, got %v %v", found, err)
+		}
+		if reverted != userConfig {
+			t.Fatalf("expected the original config, got:\n%s", reverted)
+		}
+	})
+
+	t.Run("replaces a previous install", func(t *testing.T) {
+		first, err := aerospacetoml.Install(userConfig, defaultOptions())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := aerospacetoml.Install(first.Content, defaultOptions())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if second.Content != first.Content {
+			t.Fatalf("expected the same config, got:\n%s", second.Content)
+		}
+	})
+
+	t.Run("adds the entries to an empty config", func(t *testing.T) {
+		opts := defaultOptions()
+		opts.Hooks = []aerospacetoml.Hook{aerospacetoml.HookFollow, aerospacetoml.HookFocusChanged}
+		result, err := aerospacetoml.Install("", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := `# >>> aerospace-scratchpad hook install >>>
+exec-on-workspace-change = ['/bin/bash', '-c', 'aerospace-scratchpad hook follow "$AEROSPACE_FOCUSED_WORKSPACE"']
+# <<< aerospace-scratchpad hook install <<<
+# >>> aerospace-scratchpad hook install >>>
+on-focus-changed = ['exec-and-forget aerospace-scratchpad hook focus-changed']
+# <<< aerospace-scratchpad hook install <<<
+# >>> aerospace-scratchpad hook install >>>
+[mode.main.binding]
+ctrl-minus = 'exec-and-forget aerospace-scratchpad next'
+cmd-shift-h = 'exec-and-forget aerospace-scratchpad move --all-floating'
+# <<< aerospace-scratchpad hook install <<<
+`
+		if result.Content != expected {
+			t.Fatalf("unexpected config:\n%s", result.Content)
+		}
+	})
+
+	t.Run("wraps commands that don't run a shell", func(t *testing.T) {
+		config := "exec-on-workspace-change = ['/usr/bin/env', 'notify', 'it\\'s']\n"
+		opts := defaultOptions()
+		opts.Keybindings = nil
+
+		result, err := aerospacetoml.Install(
+			`exec-on-workspace-change = ["/usr/bin/env", "notify", "it's"]`+"\n",
+			opts,
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `exec-on-workspace-change = ['/bin/bash', '-c', "/usr/bin/env notify 'it'\\''s'; ` +
+			`aerospace-scratchpad hook pull-window \"$AEROSPACE_PREV_WORKSPACE\" \"$AEROSPACE_FOCUSED_WORKSPACE\""]`
+		if !strings.Contains(result.Content, expected) {
+			t.Fatalf("unexpected config:\n%s", result.Content)
+		}
+
+		if _, err = aerospacetoml.Install(config, opts); err == nil {
+			t.Fatalf("expected an error for a broken string")
+		}
+	})
+
+	t.Run("leaves hooks configured by hand", func(t *testing.T) {
+		config := `exec-on-workspace-change = ["/bin/bash", "-c",
+  "aerospace-scratchpad hook pull-window $AEROSPACE_PREV_WORKSPACE $AEROSPACE_FOCUSED_WORKSPACE"
+]
+`
+		opts := defaultOptions()
+		opts.Keybindings = nil
+
+		result, err := aerospacetoml.Install(config, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Content != config || len(result.Notes) != 1 {
+			t.Fatalf("expected the config untouched with a note, got %q\n%s", result.Notes, result.Content)
+		}
+	})
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	after := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+
+	expected := `--- before
++++ after
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+`
+	if diff := aerospacetoml.UnifiedDiff("before", "after", before, after); diff != expected {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+	if diff := aerospacetoml.UnifiedDiff("before", "after", before, before); diff != "" {
+		t.Fatalf("expected no diff, got:\n%s", diff)
+	}
+}
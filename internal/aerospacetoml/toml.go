@@ -0,0 +1,411 @@
+// Package aerospacetoml reads and patches the AeroSpace config file
+// (aerospace.toml) as text, so the comments and the layout of the user's
+// config are kept.
+//
+// It understands enough TOML to find the statements of the config and to
+// read arrays of strings, which is what the hooks and keybindings use.
+package aerospacetoml
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// statement is a key = value line, or lines when the value spans several.
+type statement struct {
+	// table is the table the statement belongs to, empty for the top level.
+	table string
+	key   string
+	// value is the raw value, as written in the file.
+	value string
+	// startLine and endLine delimit the statement lines, end exclusive.
+	startLine int
+	endLine   int
+}
+
+// header is a [table] or [[array-of-tables]] line.
+type header struct {
+	name string
+	line int
+}
+
+type document struct {
+	lines      []string
+	statements []statement
+	headers    []header
+}
+
+// ConfigPath returns the AeroSpace config file: ~/.aerospace.toml, or
+// $XDG_CONFIG_HOME/aerospace/aerospace.toml when only that one exists.
+func ConfigPath() string {
+	home, _ := os.UserHomeDir()
+	path := filepath.Join(home, ".aerospace.toml")
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	xdgPath := filepath.Join(configHome, "aerospace", "aerospace.toml")
+	if _, err := os.Stat(xdgPath); err == nil {
+		return xdgPath
+	}
+
+	return path
+}
+
+func parseDocument(content string) (*document, error) {
+	doc := &document{lines: strings.Split(content, "\n")}
+
+	table := ""
+	pos := 0
+	for pos < len(content) {
+		switch char := content[pos]; {
+		case char == ' ' || char == '\t' || char == '\r' || char == '\n':
+			pos++
+		case char == '#':
+			pos = endOfLine(content, pos)
+		case char == '[':
+			end := endOfLine(content, pos)
+			name := strings.TrimSpace(stripComment(content[pos:end]))
+			name = strings.Trim(name, "[]")
+			table = strings.TrimSpace(name)
+			doc.headers = append(doc.headers, header{name: table, line: lineOf(content, pos)})
+			pos = end
+		default:
+			stmt, next, err := parseStatement(content, pos)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineOf(content, pos)+1, err)
+			}
+			stmt.table = table
+			doc.statements = append(doc.statements, stmt)
+			pos = next
+		}
+	}
+
+	return doc, nil
+}
+
+// parseStatement reads the statement starting at pos and returns the
+// position of the line after it.
+func parseStatement(content string, pos int) (statement, int, error) {
+	start := pos
+	for pos < len(content) && content[pos] != '=' {
+		switch content[pos] {
+		case '"', '\'':
+			end, err := scanString(content, pos)
+			if err != nil {
+				return statement{}, 0, err
+			}
+			pos = end
+		case '\n':
+			return statement{}, 0, errors.New("expected a key = value")
+		default:
+			pos++
+		}
+	}
+	if pos >= len(content) {
+		return statement{}, 0, errors.New("expected a key = value")
+	}
+	key := strings.TrimSpace(content[start:pos])
+	pos++
+
+	valueStart := pos
+	valueEnd, err := scanValue(content, pos)
+	if err != nil {
+		return statement{}, 0, err
+	}
+
+	return statement{
+		key:       unquoteKey(key),
+		value:     strings.TrimSpace(content[valueStart:valueEnd]),
+		startLine: lineOf(content, start),
+		endLine:   lineOf(content, valueEnd) + 1,
+	}, endOfLine(content, valueEnd), nil
+}
+
+// scanValue returns the position right after the value starting at pos.
+// Arrays and inline tables may span lines.
+func scanValue(content string, pos int) (int, error) {
+	depth := 0
+	for pos < len(content) {
+		char := content[pos]
+		switch {
+		case char == ' ' || char == '\t' || char == '\r':
+			pos++
+			continue
+		case char == '\n' || char == '#':
+			if depth == 0 {
+				return 0, errors.New("missing value")
+			}
+			if char == '#' {
+				pos = endOfLine(content, pos)
+			} else {
+				pos++
+			}
+			continue
+		case char == '"' || char == '\'':
+			end, err := scanString(content, pos)
+			if err != nil {
+				return 0, err
+			}
+			pos = end
+		case char == '[' || char == '{':
+			depth++
+			pos++
+			continue
+		case char == ']' || char == '}':
+			depth--
+			pos++
+		case char == ',':
+			pos++
+			continue
+		default:
+			for pos < len(content) && !strings.ContainsRune(" \t\r\n,]}#", rune(content[pos])) {
+				pos++
+			}
+		}
+
+		if depth <= 0 {
+			return pos, nil
+		}
+	}
+
+	return 0, errors.New("unterminated value")
+}
+
+// scanString returns the position right after the string starting at pos.
+func scanString(content string, pos int) (int, error) {
+	quote := content[pos]
+	multiline := strings.HasPrefix(content[pos:], strings.Repeat(string(quote), 3))
+	if multiline {
+		delimiter := strings.Repeat(string(quote), 3)
+		for index := pos + 3; index < len(content); index++ {
+			if quote == '"' && content[index] == '\\' {
+				index++
+				continue
+			}
+			if strings.HasPrefix(content[index:], delimiter) {
+				end := index + 3
+				// Up to two quotes may close the string right before the delimiter.
+				for end < len(content) && end-index < 5 && content[end] == quote {
+					end++
+				}
+				return end, nil
+			}
+		}
+		return 0, errors.New("unterminated multi-line string")
+	}
+
+	for index := pos + 1; index < len(content); index++ {
+		switch content[index] {
+		case '\\':
+			if quote == '"' {
+				index++
+			}
+		case '\n':
+			return 0, errors.New("unterminated string")
+		case quote:
+			return index + 1, nil
+		}
+	}
+	return 0, errors.New("unterminated string")
+}
+
+// parseStringArray decodes a value made of strings, either a single one or
+// an array of them.
+func parseStringArray(value string) ([]string, error) {
+	var items []string
+	pos := 0
+	for pos < len(value) {
+		char := value[pos]
+		switch {
+		case strings.ContainsRune(" \t\r\n,[]", rune(char)):
+			pos++
+		case char == '#':
+			pos = endOfLine(value, pos)
+		case char == '"' || char == '\'':
+			end, err := scanString(value, pos)
+			if err != nil {
+				return nil, err
+			}
+			item, err := decodeString(value[pos:end])
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			pos = end
+		default:
+			return nil, fmt.Errorf("expected an array of strings, got %q", value)
+		}
+	}
+	return items, nil
+}
+
+func decodeString(literal string) (string, error) {
+	switch {
+	case strings.HasPrefix(literal, "'''"):
+		return trimFirstNewline(literal[3 : len(literal)-3]), nil
+	case strings.HasPrefix(literal, "'"):
+		return literal[1 : len(literal)-1], nil
+	case strings.HasPrefix(literal, `"""`):
+		return decodeBasic(trimFirstNewline(literal[3:len(literal)-3]), true)
+	default:
+		return decodeBasic(literal[1:len(literal)-1], false)
+	}
+}
+
+func decodeBasic(raw string, multiline bool) (string, error) {
+	var decoded strings.Builder
+	for index := 0; index < len(raw); index++ {
+		if raw[index] != '\\' {
+			decoded.WriteByte(raw[index])
+			continue
+		}
+		index++
+		if index >= len(raw) {
+			return "", errors.New("trailing backslash in string")
+		}
+
+		switch escape := raw[index]; escape {
+		case 'b':
+			decoded.WriteByte('\b')
+		case 't':
+			decoded.WriteByte('\t')
+		case 'n':
+			decoded.WriteByte('\n')
+		case 'f':
+			decoded.WriteByte('\f')
+		case 'r':
+			decoded.WriteByte('\r')
+		case 'e':
+			decoded.WriteByte('\x1b')
+		case '"', '\\':
+			decoded.WriteByte(escape)
+		case 'u', 'U':
+			size := 4
+			if escape == 'U' {
+				size = 8
+			}
+			if index+size >= len(raw) {
+				return "", errors.New("invalid unicode escape in string")
+			}
+			code, err := strconv.ParseUint(raw[index+1:index+1+size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return "", errors.New("invalid unicode escape in string")
+			}
+			decoded.WriteRune(rune(code))
+			index += size
+		default:
+			// A line ending backslash trims the whitespace up to the next
+			// character in multi-line strings.
+			rest := strings.TrimLeft(raw[index:], " \t\r")
+			if !multiline || !strings.HasPrefix(rest, "\n") {
+				return "", fmt.Errorf("invalid escape \\%c in string", escape)
+			}
+			rest = strings.TrimLeft(rest, " \t\r\n")
+			index = len(raw) - len(rest) - 1
+		}
+	}
+	return decoded.String(), nil
+}
+
+// encodeString writes value as a TOML string, a literal one when possible
+// so shell quotes stay readable.
+func encodeString(value string) string {
+	if !strings.ContainsAny(value, "'\n\r\t") {
+		return "'" + value + "'"
+	}
+
+	escaper := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return `"` + escaper.Replace(value) + `"`
+}
+
+func encodeStringArray(items []string) string {
+	encoded := make([]string, 0, len(items))
+	for _, item := range items {
+		encoded = append(encoded, encodeString(item))
+	}
+	return "[" + strings.Join(encoded, ", ") + "]"
+}
+
+func (d *document) find(table, key string) (statement, bool) {
+	for _, stmt := range d.statements {
+		if stmt.table == table && stmt.key == key {
+			return stmt, true
+		}
+	}
+	return statement{}, false
+}
+
+func (d *document) findHeader(name string) (header, bool) {
+	for _, h := range d.headers {
+		if h.name == name {
+			return h, true
+		}
+	}
+	return header{}, false
+}
+
+// topLevelEnd is the line where top level statements may be added: right
+// before the first table.
+func (d *document) topLevelEnd() int {
+	if len(d.headers) > 0 {
+		return d.headers[0].line
+	}
+	return d.end()
+}
+
+// end is the line after the last one, not counting the final newline.
+func (d *document) end() int {
+	end := len(d.lines)
+	if end > 0 && d.lines[end-1] == "" {
+		end--
+	}
+	return end
+}
+
+func unquoteKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		if decoded, err := decodeString(key); err == nil {
+			return decoded
+		}
+	}
+	return key
+}
+
+func trimFirstNewline(value string) string {
+	value = strings.TrimPrefix(value, "\r")
+	return strings.TrimPrefix(value, "\n")
+}
+
+func stripComment(line string) string {
+	if index := strings.Index(line, "#"); index >= 0 {
+		return line[:index]
+	}
+	return line
+}
+
+func endOfLine(content string, pos int) int {
+	if index := strings.IndexByte(content[pos:], '\n'); index >= 0 {
+		return pos + index + 1
+	}
+	return len(content)
+}
+
+func lineOf(content string, pos int) int {
+	return strings.Count(content[:pos], "\n")
+}
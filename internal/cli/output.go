@@ -18,6 +18,11 @@ const (
 	OutputFormatCSV  OutputFormat = "csv"
 )
 
+// OutputFormats lists the supported formats.
+//
+//nolint:gochecknoglobals // static list of formats
+var OutputFormats = []OutputFormat{OutputFormatText, OutputFormatJSON, OutputFormatTSV, OutputFormatCSV}
+
 // OutputEvent describes a single command result in a structured way.
 type OutputEvent struct {
 	Command         string `json:"command"`
@@ -67,6 +72,88 @@ func (f *OutputFormatter) Print(event OutputEvent) error {
 	}
 }
 
+// PrintColumns writes a row of the named columns instead of an event, e.g.
+// for list --columns. A nil value is written empty, or null in json.
+func (f *OutputFormatter) PrintColumns(columns []string, values []any) error {
+	if len(columns) != len(values) {
+		return fmt.Errorf("%d columns for %d values", len(columns), len(values))
+	}
+
+	switch f.format {
+	case OutputFormatJSON:
+		return f.printColumnsJSON(columns, values)
+	case OutputFormatTSV:
+		return f.printColumnsSeparated(columns, values, '\t')
+	case OutputFormatCSV:
+		return f.printColumnsSeparated(columns, values, ',')
+	case OutputFormatText:
+		parts := make([]string, 0, len(columns))
+		for i, column := range columns {
+			parts = append(parts, fmt.Sprintf("%s=%s", column, quoteIfNeeded(columnString(values[i]))))
+		}
+		_, err := fmt.Fprintln(f.writer, strings.Join(parts, " "))
+		return err
+	default:
+		return fmt.Errorf("unsupported output format: %s", f.format)
+	}
+}
+
+func (f *OutputFormatter) printColumnsJSON(columns []string, values []any) error {
+	// Built by hand to keep the columns in the requested order.
+	var builder strings.Builder
+	builder.WriteByte('{')
+	for i, column := range columns {
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.Write(key)
+		builder.WriteByte(':')
+		builder.Write(value)
+	}
+	builder.WriteByte('}')
+
+	_, err := fmt.Fprintln(f.writer, builder.String())
+	return err
+}
+
+func (f *OutputFormatter) printColumnsSeparated(columns []string, values []any, sep rune) error {
+	writer := csv.NewWriter(f.writer)
+	writer.Comma = sep
+
+	if !f.headerWritten {
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+		f.headerWritten = true
+	}
+
+	row := make([]string, 0, len(values))
+	for _, value := range values {
+		row = append(row, columnString(value))
+	}
+	if err := writer.Write(row); err != nil {
+		return err
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func columnString(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
 func (f *OutputFormatter) printText(event OutputEvent) error {
 	values := f.rowValues(event)
 	parts := make([]string, 0, len(outputHeaders))
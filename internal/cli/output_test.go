@@ -251,3 +251,46 @@ func equalStringSlices(a, b []string) bool {
 
 	return true
 }
+
+func TestOutputFormatter_PrintColumns(t *testing.T) {
+	columns := []string{"id", "app", "monitor", "state"}
+	values := []any{7, "Google Chrome", nil, "hidden"}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: "text", expected: `id=7 app="Google Chrome" monitor="" state=hidden`},
+		{format: "json", expected: `{"id":7,"app":"Google Chrome","monitor":null,"state":"hidden"}`},
+		{format: "tsv", expected: "id\tapp\tmonitor\tstate\n7\tGoogle Chrome\t\thidden"},
+		{format: "csv", expected: "id,app,monitor,state\n7,Google Chrome,,hidden"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			formatter, err := cli.NewOutputFormatter(buf, tc.format)
+			if err != nil {
+				t.Fatalf("unexpected error creating formatter: %v", err)
+			}
+
+			if err = formatter.PrintColumns(columns, values); err != nil {
+				t.Fatalf("unexpected error printing columns: %v", err)
+			}
+
+			if got := strings.TrimSpace(buf.String()); got != tc.expected {
+				t.Fatalf("output mismatch:\nwant: %s\ngot:  %s", tc.expected, got)
+			}
+		})
+	}
+
+	t.Run("rejects a values mismatch", func(t *testing.T) {
+		formatter, err := cli.NewOutputFormatter(&bytes.Buffer{}, "text")
+		if err != nil {
+			t.Fatalf("unexpected error creating formatter: %v", err)
+		}
+		if err = formatter.PrintColumns(columns, values[:1]); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
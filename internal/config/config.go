@@ -0,0 +1,155 @@
+// Package config reads the aerospace-scratchpad config file.
+//
+// The config is optional, a missing file is the same as an empty one:
+//
+//	window-detected:
+//	  rules:
+//	    - filter: ["app-name=^Zoom$"]
+//	      action: scratchpad
+//	scratchpad-windows:
+//	  scope: floating
+//	  exclude:
+//	    - filter: ["window-title=^Picture-in-Picture$"]
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+)
+
+// RuleAction is what a rule does with the windows it matches.
+type RuleAction string
+
+const (
+	// ActionScratchpad sends the window to the scratchpad.
+	ActionScratchpad RuleAction = "scratchpad"
+	// ActionFloat makes the window floating, where it is.
+	ActionFloat RuleAction = "float"
+	// ActionTag tags the window, see WindowRule.Tag.
+	ActionTag RuleAction = "tag"
+	// ActionIgnore leaves the window alone and stops looking at other rules.
+	ActionIgnore RuleAction = "ignore"
+)
+
+// Config is the content of the config file.
+type Config struct {
+	WindowDetected    WindowDetected    `yaml:"window-detected"`
+	ScratchpadWindows ScratchpadWindows `yaml:"scratchpad-windows"`
+}
+
+// ScratchpadWindows configures which windows count as scratchpad windows.
+type ScratchpadWindows struct {
+	// Scope is the default of --scope: strict, floating or all.
+	Scope string `yaml:"scope,omitempty"`
+	// Exclude are the windows that never count outside a scratchpad
+	// workspace.
+	Exclude []WindowMatcher `yaml:"exclude,omitempty"`
+}
+
+// WindowMatcher matches windows with filters in the --filter syntax,
+// property=regex, all of which must match.
+type WindowMatcher struct {
+	Filter []string `yaml:"filter"`
+}
+
+// WindowDetected configures the window-detected hook.
+type WindowDetected struct {
+	// Rules are evaluated in order, the first matching rule applies.
+	Rules []WindowRule `yaml:"rules"`
+}
+
+// WindowRule matches windows with filters in the --filter syntax,
+// property=regex, all of which must match.
+type WindowRule struct {
+	Filter []string   `yaml:"filter"`
+	Action RuleAction `yaml:"action"`
+	// Tag is the tag set by ActionTag.
+	Tag string `yaml:"tag,omitempty"`
+}
+
+// Path returns the config file path: $AEROSPACE_SCRATCHPAD_CONFIG,
+// $XDG_CONFIG_HOME/aerospace-scratchpad/config.yaml or
+// ~/.config/aerospace-scratchpad/config.yaml.
+func Path() string {
+	if path := os.Getenv(constants.EnvAeroSpaceScratchpadConfig); path != "" {
+		return path
+	}
+
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "aerospace-scratchpad", "config.yaml")
+}
+
+// Load reads the config file in path. A missing file is an empty config.
+func Load(path string) (*Config, error) {
+	config := &Config{}
+	if path == "" {
+		return config, nil
+	}
+
+	// #nosec G304 -- the config path is provided by the user on purpose.
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config: %w", err)
+	}
+
+	if err = yaml.UnmarshalWithOptions(data, config, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err = config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	if c.ScratchpadWindows.Scope != "" {
+		if _, err := aerospace.ParseScope(c.ScratchpadWindows.Scope); err != nil {
+			return fmt.Errorf("scratchpad-windows: %w", err)
+		}
+	}
+	for index, matcher := range c.ScratchpadWindows.Exclude {
+		if len(matcher.Filter) == 0 {
+			return fmt.Errorf("scratchpad-windows exclude %d: filter is required", index+1)
+		}
+	}
+
+	for index, rule := range c.WindowDetected.Rules {
+		if len(rule.Filter) == 0 {
+			return fmt.Errorf("window-detected rule %d: filter is required", index+1)
+		}
+
+		switch rule.Action {
+		case ActionScratchpad, ActionFloat, ActionIgnore:
+		case ActionTag:
+			if rule.Tag == "" {
+				return fmt.Errorf("window-detected rule %d: tag is required", index+1)
+			}
+		default:
+			return fmt.Errorf(
+				"window-detected rule %d: unknown action %q, expected scratchpad|float|tag|ignore",
+				index+1,
+				rule.Action,
+			)
+		}
+	}
+
+	return nil
+}
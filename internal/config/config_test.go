@@ -0,0 +1,116 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/config"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("a missing file is an empty config", func(t *testing.T) {
+		cfg, err := config.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.WindowDetected.Rules) != 0 {
+			t.Fatalf("expected no rules, got %+v", cfg.WindowDetected.Rules)
+		}
+	})
+
+	t.Run("reads the window-detected rules", func(t *testing.T) {
+		cfg, err := config.Load(writeConfig(t, `
+window-detected:
+  rules:
+    - filter: ["app-name=^Zoom$"]
+      action: scratchpad
+    - filter: ["app-name=^Slack$"]
+      action: tag
+      tag: chat
+`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rules := cfg.WindowDetected.Rules
+		if len(rules) != 2 ||
+			rules[0].Action != config.ActionScratchpad ||
+			rules[1].Tag != "chat" {
+			t.Fatalf("unexpected rules: %+v", rules)
+		}
+	})
+
+	t.Run("reads the scratchpad-windows scope and exclusions", func(t *testing.T) {
+		cfg, err := config.Load(writeConfig(t, `
+scratchpad-windows:
+  scope: strict
+  exclude:
+    - filter: ["window-title=^Picture-in-Picture$"]
+`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		scratchpadWindows := cfg.ScratchpadWindows
+		if scratchpadWindows.Scope != "strict" ||
+			len(scratchpadWindows.Exclude) != 1 ||
+			scratchpadWindows.Exclude[0].Filter[0] != "window-title=^Picture-in-Picture$" {
+			t.Fatalf("unexpected scratchpad-windows: %+v", scratchpadWindows)
+		}
+	})
+
+	t.Run("accepts the scopes --scope accepts", func(t *testing.T) {
+		if _, err := config.Load(writeConfig(t, "scratchpad-windows:\n  scope: Strict\n")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	for name, tc := range map[string]struct {
+		content string
+		err     string
+	}{
+		"unknown action": {
+			content: "window-detected:\n  rules:\n    - filter: [app-name=Zoom]\n      action: hide\n",
+			err:     `rule 1: unknown action "hide"`,
+		},
+		"tag without tag": {
+			content: "window-detected:\n  rules:\n    - filter: [app-name=Zoom]\n      action: tag\n",
+			err:     "rule 1: tag is required",
+		},
+		"rule without filter": {
+			content: "window-detected:\n  rules:\n    - action: float\n",
+			err:     "rule 1: filter is required",
+		},
+		"unknown scope": {
+			content: "scratchpad-windows:\n  scope: tiling\n",
+			err:     "scratchpad-windows: invalid scope: tiling",
+		},
+		"exclusion without filter": {
+			content: "scratchpad-windows:\n  exclude:\n    - filter: []\n",
+			err:     "exclude 1: filter is required",
+		},
+		"unknown field": {
+			content: "window-detected:\n  rule: []\n",
+			err:     "unknown field",
+		},
+	} {
+		t.Run("rejects "+name, func(t *testing.T) {
+			_, err := config.Load(writeConfig(t, tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
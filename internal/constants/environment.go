@@ -11,6 +11,15 @@ const (
 	// default: `DISABLED`
 	EnvAeroSpaceScratchpadLogsLevel string = "AEROSPACE_SCRATCHPAD_LOGS_LEVEL"
 
+	// EnvAeroSpaceScratchpadStateDir is the environment variable for the directory
+	// where the state (e.g. the journal) is kept
+	// default: `$XDG_STATE_HOME/aerospace-scratchpad` or `~/.local/state/aerospace-scratchpad`
+	EnvAeroSpaceScratchpadStateDir string = "AEROSPACE_SCRATCHPAD_STATE_DIR"
+
+	// EnvAeroSpaceScratchpadConfig is the environment variable for the config file path
+	// default: `$XDG_CONFIG_HOME/aerospace-scratchpad/config.yaml` or `~/.config/aerospace-scratchpad/config.yaml`
+	EnvAeroSpaceScratchpadConfig string = "AEROSPACE_SCRATCHPAD_CONFIG"
+
 	// EnvAeroSpaceSock is the environment variable for the AeroSpace IPC socket path.
 	EnvAeroSpaceSock string = "AEROSPACESOCK"
 )
@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// dialTimeout is short on purpose: the daemon is local, when it doesn't
+// accept right away it is better to run the command directly.
+const dialTimeout = 100 * time.Millisecond
+
+// Send runs the request on the daemon listening on socketPath.
+// It returns ErrNotRunning when there is no daemon to answer.
+func Send(socketPath string, request Request) (Response, error) {
+	var response Response
+
+	conn, err := net.DialTimeout("unix", socketPath, dialTimeout)
+	if err != nil {
+		return response, fmt.Errorf("%w: %w", ErrNotRunning, err)
+	}
+	defer conn.Close()
+
+	if err = json.NewEncoder(conn).Encode(request); err != nil {
+		return response, fmt.Errorf("unable to send request: %w", err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return response, fmt.Errorf("unable to read response: %w", err)
+	}
+	if err = json.Unmarshal(line, &response); err != nil {
+		return response, fmt.Errorf("unable to parse response: %w", err)
+	}
+	if response.Error != "" {
+		return response, errors.New(response.Error)
+	}
+
+	return response, nil
+}
+
+// IsRunning reports whether a daemon accepts connections on socketPath.
+func IsRunning(socketPath string) bool {
+	conn, err := net.DialTimeout("unix", socketPath, dialTimeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
@@ -0,0 +1,65 @@
+// Package daemon lets a long running aerospace-scratchpad process run the
+// commands of short lived ones, so a keypress doesn't pay for the process
+// start, the logger and the AeroSpace connection every time.
+//
+// The daemon listens on its own unix socket. A client sends one Request per
+// connection, a JSON line with its argv, and reads back one Response with
+// the output and the exit code of the command.
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+)
+
+// SocketEnv overrides the daemon socket path.
+const SocketEnv = "AEROSPACE_SCRATCHPAD_SOCKET"
+
+// ErrNotRunning is returned when no daemon answers on the socket.
+var ErrNotRunning = errors.New("daemon is not running")
+
+// Request is a command to be run by the daemon.
+type Request struct {
+	// Version is the client version. The daemon refuses requests from other
+	// versions so upgrades don't run with the old daemon.
+	Version string `json:"version"`
+	// Args is the argv of the command, without the program name.
+	Args []string `json:"args"`
+	// Cwd is the client working directory, for relative paths in args.
+	Cwd string `json:"cwd,omitempty"`
+	// Env holds the AeroSpace variables of the client, e.g. AEROSPACE_WINDOW_ID.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// Response is the outcome of a Request.
+type Response struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+	// Error is set when the daemon refused to run the command. The client
+	// should run it by itself instead.
+	Error string `json:"error,omitempty"`
+}
+
+// DefaultSocketPath returns the daemon socket path, $AEROSPACE_SCRATCHPAD_SOCKET
+// or a per user socket in the temp dir, next to the AeroSpace one.
+func DefaultSocketPath() string {
+	if path := os.Getenv(SocketEnv); path != "" {
+		return path
+	}
+
+	return filepath.Join(os.TempDir(), fmt.Sprintf("aerospace-scratchpad-%s.sock", username()))
+}
+
+func username() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return fmt.Sprint(os.Getuid())
+}
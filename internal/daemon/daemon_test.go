@@ -0,0 +1,133 @@
+package daemon_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/daemon"
+)
+
+func startServer(t *testing.T, handler daemon.Handler) (string, *daemon.Server) {
+	t.Helper()
+
+	// Unix socket paths are limited in length, so avoid the long test dirs.
+	dir, err := os.MkdirTemp("", "sd")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	socketPath := filepath.Join(dir, "daemon.sock")
+
+	server := daemon.NewServer(handler)
+	done := make(chan error, 1)
+	go func() {
+		done <- server.ListenAndServe(socketPath)
+	}()
+	for range 100 {
+		if daemon.IsRunning(socketPath) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Cleanup(func() {
+		_ = server.Close()
+		if serveErr := <-done; serveErr != nil {
+			t.Errorf("unexpected serve error: %v", serveErr)
+		}
+		_ = os.RemoveAll(dir)
+	})
+
+	return socketPath, server
+}
+
+func TestDaemon(t *testing.T) {
+	t.Run("runs the request and returns its response", func(t *testing.T) {
+		socketPath, _ := startServer(t, func(request daemon.Request) daemon.Response {
+			return daemon.Response{
+				Stdout:   strings.Join(request.Args, " ") + "\n",
+				Stderr:   request.Env["AEROSPACE_WINDOW_ID"],
+				ExitCode: 3,
+			}
+		})
+
+		response, err := daemon.Send(socketPath, daemon.Request{
+			Args: []string{"show", "Finder"},
+			Env:  map[string]string{"AEROSPACE_WINDOW_ID": "42"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Stdout != "show Finder\n" || response.Stderr != "42" || response.ExitCode != 3 {
+			t.Fatalf("unexpected response: %+v", response)
+		}
+	})
+
+	t.Run("reports refused requests as errors", func(t *testing.T) {
+		socketPath, _ := startServer(t, func(daemon.Request) daemon.Response {
+			return daemon.Response{Error: "version mismatch"}
+		})
+
+		_, err := daemon.Send(socketPath, daemon.Request{Args: []string{"list"}})
+		if err == nil || err.Error() != "version mismatch" {
+			t.Fatalf("expected the refusal, got %v", err)
+		}
+	})
+
+	t.Run("runs the background work between the requests", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		socketPath, server := startServer(t, func(daemon.Request) daemon.Response {
+			close(started)
+			<-release
+			return daemon.Response{}
+		})
+
+		go func() { _, _ = daemon.Send(socketPath, daemon.Request{Args: []string{"list"}}) }()
+		<-started
+
+		done := make(chan struct{})
+		go server.Do(func() { close(done) })
+		select {
+		case <-done:
+			t.Fatalf("expected to wait for the running request")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		<-done
+	})
+
+	t.Run("refuses to start twice on the same socket", func(t *testing.T) {
+		socketPath, _ := startServer(t, func(daemon.Request) daemon.Response {
+			return daemon.Response{}
+		})
+
+		err := daemon.NewServer(nil).ListenAndServe(socketPath)
+		if err == nil || !strings.Contains(err.Error(), "already running") {
+			t.Fatalf("expected already running error, got %v", err)
+		}
+	})
+
+	t.Run("tells when no daemon is running", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "missing.sock")
+		if daemon.IsRunning(socketPath) {
+			t.Fatalf("expected no daemon")
+		}
+
+		_, err := daemon.Send(socketPath, daemon.Request{Args: []string{"list"}})
+		if !errors.Is(err, daemon.ErrNotRunning) {
+			t.Fatalf("expected ErrNotRunning, got %v", err)
+		}
+	})
+
+	t.Run("uses the socket from the environment", func(t *testing.T) {
+		t.Setenv(daemon.SocketEnv, "/tmp/custom.sock")
+		if got := daemon.DefaultSocketPath(); got != "/tmp/custom.sock" {
+			t.Fatalf("unexpected socket path %s", got)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// socketFileMode keeps other users away from the daemon.
+	socketFileMode = 0o600
+	// socketUmask creates the socket with socketFileMode already.
+	socketUmask = 0o077
+	// requestTimeout bounds how long a client may take to send its request.
+	requestTimeout = 5 * time.Second
+)
+
+// Handler runs a request and returns its response.
+type Handler func(Request) Response
+
+// Server runs the requests received on a unix socket.
+//
+// Requests are handled one at a time: commands share the daemon state, e.g.
+// its cache and working directory, and AeroSpace applies the changes in order
+// anyway.
+type Server struct {
+	handler Handler
+
+	// running serializes the requests and the work passed to Do.
+	running sync.Mutex
+
+	mu       sync.Mutex
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// NewServer creates a server that runs the requests with handler.
+func NewServer(handler Handler) *Server {
+	return &Server{
+		handler: handler,
+	}
+}
+
+// ListenAndServe listens on socketPath and serves until Close is called.
+// It fails when another daemon is already answering on socketPath, and
+// replaces the socket file left by a daemon that is gone.
+func (s *Server) ListenAndServe(socketPath string) error {
+	if IsRunning(socketPath) {
+		return fmt.Errorf("a daemon is already running on %s", socketPath)
+	}
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove stale socket: %w", err)
+	}
+
+	// The socket is created private, so other users can't reach it before
+	// the chmod either.
+	previousUmask := syscall.Umask(socketUmask)
+	listener, err := net.Listen("unix", socketPath)
+	syscall.Umask(previousUmask)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s: %w", socketPath, err)
+	}
+	if err = os.Chmod(socketPath, socketFileMode); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("unable to restrict socket permissions: %w", err)
+	}
+
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on the listener until Close is called.
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("unable to accept connection: %w", err)
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleConnection(conn)
+		}()
+	}
+}
+
+// Close stops accepting requests, waits for the running one and closes the
+// socket. The socket file is removed by the listener.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// Do runs fn between the requests, e.g. background work sharing the daemon
+// state with them.
+func (s *Server) Do(fn func()) {
+	s.running.Lock()
+	defer s.running.Unlock()
+	fn()
+}
+
+func (s *Server) handleConnection(conn net.Conn) {
+	defer conn.Close()
+
+	var request Request
+	_ = conn.SetReadDeadline(time.Now().Add(requestTimeout))
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &request)
+	}
+
+	var response Response
+	if err != nil {
+		response = Response{Error: fmt.Sprintf("invalid request: %v", err)}
+	} else {
+		s.running.Lock()
+		response = s.handler(request)
+		s.running.Unlock()
+	}
+
+	_ = json.NewEncoder(conn).Encode(response)
+}
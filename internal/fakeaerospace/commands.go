@@ -0,0 +1,540 @@
+package fakeaerospace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+const (
+	flagAll                = "--all"
+	flagCount              = "--count"
+	flagFocused            = "--focused"
+	flagFormat             = "--format"
+	flagJSON               = "--json"
+	flagMonitor            = "--monitor"
+	flagVisible            = "--visible"
+	flagWindowID           = "--window-id"
+	flagWorkspace          = "--workspace"
+	flagFocusFollowsWindow = "--focus-follows-window"
+	flagFailIfNoop         = "--fail-if-noop"
+
+	valueFocused = "focused"
+	valueAll     = "all"
+
+	layoutTiling = "tiling"
+	layoutHTiles = "h_tiles"
+
+	// DumpWorldCommand is a fake-only command that returns the current world
+	// as yaml. It is handy to inspect the outcome of end-to-end scenarios.
+	DumpWorldCommand = "fake-dump-world"
+)
+
+//nolint:gochecknoglobals // compiled once and only read
+var formatVariablePattern = regexp.MustCompile(`%\{([a-z-]+)\}`)
+
+// Execute applies an AeroSpace command to the world and returns the response
+// the real server would send back.
+//
+// args[0] is the command name, e.g. ["list-windows", "--all", "--json"].
+func (w *World) Execute(args []string) client.Response {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(args) == 0 {
+		return w.failure("Empty request")
+	}
+
+	command, commandArgs := args[0], args[1:]
+	switch command {
+	case "list-windows":
+		return w.listWindows(commandArgs)
+	case "list-workspaces":
+		return w.listWorkspaces(commandArgs)
+	case "list-monitors":
+		return w.listMonitors(commandArgs)
+	case "focus":
+		return w.focus(commandArgs)
+	case "move-node-to-workspace":
+		return w.moveNodeToWorkspace(commandArgs)
+	case "layout":
+		return w.layout(commandArgs)
+	case "workspace":
+		return w.workspace(commandArgs)
+	case "workspace-back-and-forth":
+		return w.workspace([]string{w.previousWorkspace})
+	case "close":
+		return w.closeWindow(commandArgs)
+	case "config":
+		return w.success(w.state.ConfigPath)
+	case "version":
+		return w.success(w.state.ServerVersion)
+	case DumpWorldCommand:
+		return w.dumpWorld()
+	default:
+		return w.failure(fmt.Sprintf("Unknown command '%s'", command))
+	}
+}
+
+func (w *World) listWindows(args []string) client.Response {
+	var selected []Window
+	switch {
+	case hasFlag(args, flagFocused):
+		if window := w.focusedWindow(); window != nil {
+			selected = append(selected, *window)
+		}
+	case flagValue(args, flagWorkspace) != "":
+		name := flagValue(args, flagWorkspace)
+		if name == valueFocused {
+			name = w.state.FocusedWorkspace
+		}
+		selected = w.windowsInWorkspace(name)
+	case flagValue(args, flagMonitor) != "":
+		monitorID, err := w.resolveMonitor(flagValue(args, flagMonitor))
+		if err != nil {
+			return w.failure(err.Error())
+		}
+		for _, window := range w.state.Windows {
+			if monitorID < 0 || w.monitorOfWorkspace(window.Workspace) == monitorID {
+				selected = append(selected, window)
+			}
+		}
+	case hasFlag(args, flagAll):
+		selected = append(selected, w.state.Windows...)
+	default:
+		return w.failure("Mandatory option is not specified (--focused|--all|--monitor|--workspace)")
+	}
+
+	records := make([]record, 0, len(selected))
+	for _, window := range selected {
+		records = append(records, w.windowRecord(window))
+	}
+
+	return w.render(args, records, []string{"window-id", "app-name", "window-title"})
+}
+
+func (w *World) listWorkspaces(args []string) client.Response {
+	var selected []Workspace
+	switch {
+	case hasFlag(args, flagFocused):
+		if workspace := w.findWorkspace(w.state.FocusedWorkspace); workspace != nil {
+			selected = append(selected, *workspace)
+		}
+	case flagValue(args, flagMonitor) != "":
+		monitorID, err := w.resolveMonitor(flagValue(args, flagMonitor))
+		if err != nil {
+			return w.failure(err.Error())
+		}
+		for _, workspace := range w.sortedWorkspaces() {
+			if monitorID < 0 || workspace.MonitorID == monitorID {
+				selected = append(selected, workspace)
+			}
+		}
+	case hasFlag(args, flagAll):
+		selected = w.sortedWorkspaces()
+	default:
+		return w.failure("Mandatory option is not specified (--focused|--all|--monitor)")
+	}
+	if hasFlag(args, flagVisible) {
+		selected = slices.DeleteFunc(selected, func(workspace Workspace) bool {
+			return !w.isVisible(workspace)
+		})
+	}
+
+	records := make([]record, 0, len(selected))
+	for _, workspace := range selected {
+		records = append(records, w.workspaceRecord(workspace))
+	}
+
+	return w.render(args, records, []string{"workspace"})
+}
+
+func (w *World) listMonitors(args []string) client.Response {
+	var selected []Monitor
+	if hasFlag(args, flagFocused) {
+		selected = append(selected, *w.focusedMonitor())
+	} else {
+		selected = append(selected, w.state.Monitors...)
+	}
+
+	records := make([]record, 0, len(selected))
+	for _, monitor := range selected {
+		records = append(records, record{
+			"monitor-id":   monitor.MonitorID,
+			"monitor-name": monitor.MonitorName,
+		})
+	}
+
+	return w.render(args, records, []string{"monitor-id", "monitor-name"})
+}
+
+func (w *World) focus(args []string) client.Response {
+	rawID := flagValue(args, flagWindowID)
+	if rawID == "" {
+		return w.failure("Only 'focus --window-id <id>' is supported by the fake server")
+	}
+
+	window, failure := w.lookupWindow(rawID)
+	if failure != nil {
+		return *failure
+	}
+
+	w.focusWindow(window)
+	return w.success("")
+}
+
+func (w *World) moveNodeToWorkspace(args []string) client.Response {
+	positional := positionalArgs(args, flagWindowID)
+	if len(positional) != 1 {
+		return w.failure("Usage: move-node-to-workspace <workspace-name> [--window-id <id>]")
+	}
+	target := positional[0]
+
+	window, failure := w.targetWindow(args)
+	if failure != nil {
+		return *failure
+	}
+
+	if window.Workspace == target {
+		message := fmt.Sprintf(
+			"Window '%d' already belongs to workspace '%s'",
+			window.WindowID,
+			target,
+		)
+		if hasFlag(args, flagFailIfNoop) {
+			return w.failure(message)
+		}
+		return client.Response{ServerVersion: w.state.ServerVersion, StdErr: message}
+	}
+
+	wasFocused := w.focusedWindow() != nil && w.focusedWindow().WindowID == window.WindowID
+	// ensureWorkspace may grow the workspaces slice, so resolve it first.
+	destination := w.ensureWorkspace(target)
+	source := w.findWorkspace(window.Workspace)
+
+	window.Workspace = destination.Workspace
+	if source != nil && source.FocusedWindowID == window.WindowID {
+		w.refocusWorkspace(source)
+	}
+	if destination.FocusedWindowID == 0 || hasFlag(args, flagFocusFollowsWindow) {
+		destination.FocusedWindowID = window.WindowID
+	}
+
+	if hasFlag(args, flagFocusFollowsWindow) {
+		w.focusWindow(window)
+	} else if wasFocused && source != nil {
+		w.setFocusedWorkspace(source.Workspace)
+	}
+
+	return w.success("")
+}
+
+func (w *World) layout(args []string) client.Response {
+	layouts := positionalArgs(args, flagWindowID)
+	if len(layouts) == 0 {
+		return w.failure("Usage: layout <layout>... [--window-id <id>]")
+	}
+
+	window, failure := w.targetWindow(args)
+	if failure != nil {
+		return *failure
+	}
+
+	// With multiple layouts AeroSpace picks the one after the current one.
+	next := layouts[0]
+	for i, candidate := range layouts {
+		if normalizeLayout(candidate) == window.WindowLayout {
+			next = layouts[(i+1)%len(layouts)]
+			break
+		}
+	}
+
+	layoutName := normalizeLayout(next)
+	window.WindowLayout = layoutName
+	window.WindowParentContainerLayout = layoutName
+
+	return w.success("")
+}
+
+func (w *World) workspace(args []string) client.Response {
+	positional := positionalArgs(args)
+	if len(positional) != 1 || positional[0] == "" {
+		return w.failure("Usage: workspace <workspace-name>")
+	}
+
+	workspace := w.ensureWorkspace(positional[0])
+	if workspace.Workspace != w.state.FocusedWorkspace {
+		w.previousWorkspace = w.state.FocusedWorkspace
+		w.setFocusedWorkspace(workspace.Workspace)
+	}
+
+	return w.success("")
+}
+
+func (w *World) closeWindow(args []string) client.Response {
+	window, failure := w.targetWindow(args)
+	if failure != nil {
+		return *failure
+	}
+
+	windowID := window.WindowID
+	workspace := w.findWorkspace(window.Workspace)
+	for i := range w.state.Windows {
+		if w.state.Windows[i].WindowID == windowID {
+			w.state.Windows = append(w.state.Windows[:i], w.state.Windows[i+1:]...)
+			break
+		}
+	}
+	if workspace != nil && workspace.FocusedWindowID == windowID {
+		w.refocusWorkspace(workspace)
+	}
+
+	return w.success("")
+}
+
+func (w *World) dumpWorld() client.Response {
+	data, err := marshalState(w.copyState())
+	if err != nil {
+		return w.failure(err.Error())
+	}
+	return w.success(string(data))
+}
+
+func (w *World) focusWindow(window *Window) {
+	workspace := w.ensureWorkspace(window.Workspace)
+	workspace.FocusedWindowID = window.WindowID
+	if workspace.Workspace != w.state.FocusedWorkspace {
+		w.previousWorkspace = w.state.FocusedWorkspace
+		w.setFocusedWorkspace(workspace.Workspace)
+	}
+}
+
+// targetWindow returns the window referenced by --window-id or, when absent,
+// the focused window.
+func (w *World) targetWindow(args []string) (*Window, *client.Response) {
+	if rawID := flagValue(args, flagWindowID); rawID != "" {
+		return w.lookupWindow(rawID)
+	}
+
+	window := w.focusedWindow()
+	if window == nil {
+		failure := w.failure("No window is focused")
+		return nil, &failure
+	}
+	return window, nil
+}
+
+func (w *World) lookupWindow(rawID string) (*Window, *client.Response) {
+	windowID, err := strconv.Atoi(rawID)
+	if err != nil {
+		failure := w.failure(fmt.Sprintf("Invalid window id '%s'", rawID))
+		return nil, &failure
+	}
+
+	window := w.findWindow(windowID)
+	if window == nil {
+		failure := w.failure(fmt.Sprintf("Invalid <window-id> %d", windowID))
+		return nil, &failure
+	}
+
+	return window, nil
+}
+
+// resolveMonitor returns the monitor ID for a --monitor value, or -1 for all.
+func (w *World) resolveMonitor(value string) (int, error) {
+	switch value {
+	case valueAll:
+		return -1, nil
+	case valueFocused, "mouse":
+		return w.focusedMonitor().MonitorID, nil
+	default:
+		monitorID, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid monitor '%s'", value)
+		}
+		return monitorID, nil
+	}
+}
+
+func (w *World) windowRecord(window Window) record {
+	workspace := w.findWorkspace(window.Workspace)
+	monitorID := w.state.Monitors[0].MonitorID
+	if workspace != nil {
+		monitorID = workspace.MonitorID
+	}
+	monitorName := ""
+	if monitor := w.findMonitor(monitorID); monitor != nil {
+		monitorName = monitor.MonitorName
+	}
+
+	return record{
+		"window-id":                      window.WindowID,
+		"window-title":                   window.WindowTitle,
+		"app-name":                       window.AppName,
+		"app-bundle-id":                  window.AppBundleID,
+		"workspace":                      window.Workspace,
+		"window-layout":                  window.WindowLayout,
+		"window-parent-container-layout": window.WindowParentContainerLayout,
+		"monitor-id":                     monitorID,
+		"monitor-name":                   monitorName,
+	}
+}
+
+func (w *World) workspaceRecord(workspace Workspace) record {
+	monitorName := ""
+	if monitor := w.findMonitor(workspace.MonitorID); monitor != nil {
+		monitorName = monitor.MonitorName
+	}
+
+	return record{
+		"workspace":    workspace.Workspace,
+		"monitor-id":   workspace.MonitorID,
+		"monitor-name": monitorName,
+	}
+}
+
+// render prints the records the way AeroSpace does for the given flags:
+// a json array with the requested variables, a count, or formatted lines.
+func (w *World) render(args []string, records []record, defaultVariables []string) client.Response {
+	if hasFlag(args, flagCount) {
+		return w.success(strconv.Itoa(len(records)))
+	}
+
+	format := flagValue(args, flagFormat)
+	variables := defaultVariables
+	if format != "" {
+		variables = nil
+		for _, match := range formatVariablePattern.FindAllStringSubmatch(format, -1) {
+			variables = append(variables, match[1])
+		}
+	}
+
+	if hasFlag(args, flagJSON) {
+		out, err := renderJSON(records, variables)
+		if err != nil {
+			return w.failure(err.Error())
+		}
+		return w.success(out)
+	}
+
+	lines := make([]string, 0, len(records))
+	for _, rec := range records {
+		if format == "" {
+			values := make([]string, 0, len(variables))
+			for _, variable := range variables {
+				values = append(values, fmt.Sprint(rec[variable]))
+			}
+			lines = append(lines, strings.Join(values, " | "))
+			continue
+		}
+		lines = append(lines, formatVariablePattern.ReplaceAllStringFunc(
+			format,
+			func(token string) string {
+				name := formatVariablePattern.FindStringSubmatch(token)[1]
+				return fmt.Sprint(rec[name])
+			},
+		))
+	}
+
+	return w.success(strings.Join(lines, "\n"))
+}
+
+func (w *World) success(stdout string) client.Response {
+	return client.Response{
+		ServerVersion: w.state.ServerVersion,
+		StdOut:        stdout,
+		ExitCode:      0,
+	}
+}
+
+func (w *World) failure(message string) client.Response {
+	return client.Response{
+		ServerVersion: w.state.ServerVersion,
+		StdErr:        message,
+		ExitCode:      1,
+	}
+}
+
+// record holds the values of a listed item keyed by AeroSpace format variable.
+type record map[string]any
+
+func renderJSON(records []record, variables []string) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, rec := range records {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("{")
+		for j, variable := range variables {
+			if j > 0 {
+				buf.WriteString(",")
+			}
+			key, err := json.Marshal(variable)
+			if err != nil {
+				return "", err
+			}
+			value, err := json.Marshal(rec[variable])
+			if err != nil {
+				return "", err
+			}
+			buf.Write(key)
+			buf.WriteString(":")
+			buf.Write(value)
+		}
+		buf.WriteString("}")
+	}
+	buf.WriteString("]")
+	return buf.String(), nil
+}
+
+func normalizeLayout(layoutName string) string {
+	if layoutName == layoutTiling {
+		return layoutHTiles
+	}
+	return layoutName
+}
+
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func flagValue(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// positionalArgs returns the arguments that are neither flags nor values of
+// the given flags that take a value.
+func positionalArgs(args []string, valueFlags ...string) []string {
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "--") {
+			for _, valueFlag := range valueFlags {
+				if arg == valueFlag {
+					i++
+					break
+				}
+			}
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	return positional
+}
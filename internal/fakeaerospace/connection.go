@@ -0,0 +1,61 @@
+package fakeaerospace
+
+import (
+	"fmt"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+// InProcessSocketPath is reported as the socket path of in-process connections.
+const InProcessSocketPath = "in-process"
+
+// Connection talks to a World directly, without a socket.
+// It behaves like the aerospace-ipc socket connection, including turning
+// non-zero exit codes into errors.
+type Connection struct {
+	world *World
+}
+
+// NewConnection creates an in-process connection to the world.
+func NewConnection(world *World) *Connection {
+	return &Connection{world: world}
+}
+
+// World returns the world behind the connection.
+func (c *Connection) World() *World {
+	return c.world
+}
+
+// SendCommand applies the command to the world.
+func (c *Connection) SendCommand(command string, args []string) (*client.Response, error) {
+	response := c.world.Execute(append([]string{command}, args...))
+	if response.ExitCode != 0 {
+		return nil, fmt.Errorf(
+			"command failed with exit code %d\n%s",
+			response.ExitCode,
+			response.StdErr,
+		)
+	}
+
+	return &response, nil
+}
+
+// GetSocketPath returns InProcessSocketPath.
+func (c *Connection) GetSocketPath() (string, error) {
+	return InProcessSocketPath, nil
+}
+
+// GetServerVersion returns the version of the world.
+func (c *Connection) GetServerVersion() (string, error) {
+	return c.world.ServerVersion(), nil
+}
+
+// CheckServerVersion always succeeds, the world is whatever version it says.
+func (c *Connection) CheckServerVersion() error {
+	return nil
+}
+
+// CloseConnection is a no-op.
+func (c *Connection) CloseConnection() error {
+	return nil
+}
@@ -0,0 +1,203 @@
+package fakeaerospace
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+const (
+	// socketProtocolVersion is the handshake version spoken by AeroSpace.
+	socketProtocolVersion uint32 = 1
+	// maxRequestSize protects the server from reading garbage frames.
+	maxRequestSize = 16 * 1024 * 1024
+)
+
+// Logf receives the server activity. It has the same signature as log.Printf.
+type Logf func(format string, args ...any)
+
+// Server exposes a World through the AeroSpace unix socket protocol.
+type Server struct {
+	world *World
+	logf  Logf
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer creates a server for the given world.
+// logf may be nil to discard the server activity.
+func NewServer(world *World, logf Logf) *Server {
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	return &Server{
+		world: world,
+		logf:  logf,
+		conns: map[net.Conn]struct{}{},
+	}
+}
+
+// World returns the world served by the server.
+func (s *Server) World() *World {
+	return s.world
+}
+
+// ListenAndServe listens on the unix socket path and serves until Close is
+// called. A stale socket file left by a previous run is replaced.
+func (s *Server) ListenAndServe(socketPath string) error {
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove stale socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s: %w", socketPath, err)
+	}
+
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on the listener until Close is called.
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("unable to accept connection: %w", err)
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleConnection(conn)
+		}()
+	}
+}
+
+// Close stops accepting connections and closes the open ones.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) handleConnection(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	if err := handshake(conn); err != nil {
+		s.logf("HANDSHAKE: %v", err)
+		return
+	}
+
+	for {
+		command, err := readCommand(conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				s.logf("READ: %v", err)
+			}
+			return
+		}
+
+		response := s.world.Execute(command.Args)
+		s.logf("COMMAND: %v -> exit=%d", command.Args, response.ExitCode)
+
+		if err := writeResponse(conn, response); err != nil {
+			s.logf("WRITE: %v", err)
+			return
+		}
+	}
+}
+
+func handshake(conn net.Conn) error {
+	var clientVersion uint32
+	if err := binary.Read(conn, binary.LittleEndian, &clientVersion); err != nil {
+		return fmt.Errorf("unable to read protocol version: %w", err)
+	}
+
+	if err := binary.Write(conn, binary.LittleEndian, socketProtocolVersion); err != nil {
+		return fmt.Errorf("unable to write protocol version: %w", err)
+	}
+
+	if clientVersion != socketProtocolVersion {
+		return fmt.Errorf(
+			"protocol version mismatch: client=%d server=%d",
+			clientVersion,
+			socketProtocolVersion,
+		)
+	}
+
+	return nil
+}
+
+func readCommand(conn net.Conn) (client.Command, error) {
+	var command client.Command
+
+	var length uint32
+	if err := binary.Read(conn, binary.LittleEndian, &length); err != nil {
+		return command, err
+	}
+	if length > maxRequestSize {
+		return command, fmt.Errorf("request length %d exceeds maximum %d", length, maxRequestSize)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return command, err
+	}
+
+	if err := json.Unmarshal(data, &command); err != nil {
+		return command, fmt.Errorf("unable to parse request: %w", err)
+	}
+
+	return command, nil
+}
+
+func writeResponse(conn net.Conn, response client.Response) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("unable to encode response: %w", err)
+	}
+
+	frame := make([]byte, 4, 4+len(data))
+	//nolint:gosec // data is far below the uint32 limit
+	binary.LittleEndian.PutUint32(frame, uint32(len(data)))
+	frame = append(frame, data...)
+
+	_, err = conn.Write(frame)
+	return err
+}
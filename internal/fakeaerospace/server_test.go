@@ -0,0 +1,54 @@
+package fakeaerospace_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/testutils"
+)
+
+func TestServer(t *testing.T) {
+	t.Run("serves the aerospace-ipc client", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, worldYAML)
+
+		focused, err := client.Windows().GetFocusedWindow()
+		if err != nil {
+			t.Fatalf("unable to get focused window: %v", err)
+		}
+		if focused.WindowID != 1 || focused.AppName != "Ghostty" {
+			t.Fatalf("unexpected focused window: %+v", focused)
+		}
+
+		err = client.Workspaces().MoveWindowToWorkspace(workspaces.MoveWindowToWorkspaceArgs{
+			WorkspaceName: ".scratchpad",
+		})
+		if err != nil {
+			t.Fatalf("unable to move window: %v", err)
+		}
+
+		windows, err := client.Windows().GetAllWindowsByWorkspace(".scratchpad")
+		if err != nil {
+			t.Fatalf("unable to list windows: %v", err)
+		}
+		if len(windows) != 2 {
+			t.Fatalf("expected 2 windows in scratchpad, got %+v", windows)
+		}
+
+		workspace, err := client.Workspaces().GetFocusedWorkspace()
+		if err != nil {
+			t.Fatalf("unable to get focused workspace: %v", err)
+		}
+		if workspace.Workspace != "ws1" {
+			t.Fatalf("expected ws1 to stay focused, got %q", workspace.Workspace)
+		}
+	})
+
+	t.Run("reports failed commands as errors", func(t *testing.T) {
+		client, _ := testutils.StartFakeAeroSpace(t, worldYAML)
+
+		_, err := client.Connection().SendCommand("focus", []string{"--window-id", "404"})
+		if err == nil {
+			t.Fatalf("expected error for unknown window")
+		}
+	})
+}
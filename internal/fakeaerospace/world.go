@@ -0,0 +1,323 @@
+package fakeaerospace
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	// DefaultServerVersion is reported by the fake server when the world file
+	// does not define one. It must satisfy the aerospace-ipc version check.
+	DefaultServerVersion = "0.21.0-Beta fake-aerospace"
+
+	defaultMonitorID   = 1
+	defaultMonitorName = "Built-in Retina Display"
+	defaultConfigPath  = "/tmp/fake-aerospace.toml"
+)
+
+// Monitor describes a monitor in the fake world.
+type Monitor struct {
+	MonitorID   int    `yaml:"monitor-id"`
+	MonitorName string `yaml:"monitor-name,omitempty"`
+}
+
+// Workspace describes a workspace in the fake world.
+//
+// The yaml shape is compatible with the snapshot context used by the tests,
+// extended with the monitor the workspace is attached to.
+type Workspace struct {
+	Workspace       string `yaml:"workspace"`
+	FocusedWindowID int    `yaml:"focused-window-id,omitempty"`
+	MonitorID       int    `yaml:"monitor-id,omitempty"`
+}
+
+// Window describes a window in the fake world.
+//
+// The yaml shape is the same used by the snapshot context in the tests.
+type Window struct {
+	WindowID                    int    `yaml:"window-id"`
+	WindowTitle                 string `yaml:"window-title,omitempty"`
+	WindowLayout                string `yaml:"window-layout,omitempty"`
+	WindowParentContainerLayout string `yaml:"parent-layout,omitempty"`
+	AppName                     string `yaml:"app-name,omitempty"`
+	AppBundleID                 string `yaml:"app-bundle-id,omitempty"`
+	Workspace                   string `yaml:"workspace,omitempty"`
+}
+
+// State is the serializable representation of a fake world.
+type State struct {
+	ServerVersion    string `yaml:"server-version,omitempty"`
+	ConfigPath       string `yaml:"config-path,omitempty"`
+	FocusedWorkspace string `yaml:"focused-workspace,omitempty"`
+	// VisibleWorkspaces are the workspaces shown on the monitors other than
+	// the focused one. A monitor without one shows its first workspace.
+	VisibleWorkspaces []string    `yaml:"visible-workspaces,omitempty"`
+	Monitors          []Monitor   `yaml:"monitors,omitempty"`
+	Workspaces        []Workspace `yaml:"workspaces,omitempty"`
+	Windows           []Window    `yaml:"windows,omitempty"`
+}
+
+// World is an in-memory and stateful model of AeroSpace.
+// It applies AeroSpace commands and answers queries the same way the real
+// server would, so it can be used to run the CLI without macOS.
+type World struct {
+	mu                sync.Mutex
+	state             State
+	previousWorkspace string
+}
+
+// NewWorld creates a world from the given state, filling the gaps that
+// AeroSpace would never leave empty (monitors, workspaces and focus).
+func NewWorld(state State) *World {
+	world := &World{state: state}
+	world.normalize()
+	return world
+}
+
+// LoadWorld reads a world from a yaml file.
+func LoadWorld(path string) (*World, error) {
+	// #nosec G304 -- the world file is provided by the user on purpose.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read world file: %w", err)
+	}
+
+	return ParseWorld(data)
+}
+
+// ParseWorld parses a world from yaml content.
+func ParseWorld(data []byte) (*World, error) {
+	var state State
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("unable to parse world: %w", err)
+	}
+
+	return NewWorld(state), nil
+}
+
+// Snapshot returns a copy of the current state of the world.
+func (w *World) Snapshot() State {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.copyState()
+}
+
+// Marshal renders the current state of the world as yaml.
+func (w *World) Marshal() ([]byte, error) {
+	return marshalState(w.Snapshot())
+}
+
+// ServerVersion returns the version reported by the world.
+func (w *World) ServerVersion() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.state.ServerVersion
+}
+
+func marshalState(state State) ([]byte, error) {
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render world: %w", err)
+	}
+	return data, nil
+}
+
+func (w *World) copyState() State {
+	state := w.state
+	state.Monitors = append([]Monitor(nil), w.state.Monitors...)
+	state.Workspaces = append([]Workspace(nil), w.state.Workspaces...)
+	state.Windows = append([]Window(nil), w.state.Windows...)
+	state.VisibleWorkspaces = append([]string(nil), w.state.VisibleWorkspaces...)
+	return state
+}
+
+func (w *World) normalize() {
+	if w.state.ServerVersion == "" {
+		w.state.ServerVersion = DefaultServerVersion
+	}
+	if w.state.ConfigPath == "" {
+		w.state.ConfigPath = defaultConfigPath
+	}
+	if len(w.state.Monitors) == 0 {
+		w.state.Monitors = []Monitor{{
+			MonitorID:   defaultMonitorID,
+			MonitorName: defaultMonitorName,
+		}}
+	}
+
+	for i := range w.state.Workspaces {
+		if w.state.Workspaces[i].MonitorID == 0 {
+			w.state.Workspaces[i].MonitorID = w.state.Monitors[0].MonitorID
+		}
+	}
+
+	for _, window := range w.state.Windows {
+		if window.Workspace != "" {
+			w.ensureWorkspace(window.Workspace)
+		}
+	}
+
+	if w.state.FocusedWorkspace == "" {
+		for _, workspace := range w.state.Workspaces {
+			if workspace.FocusedWindowID != 0 {
+				w.state.FocusedWorkspace = workspace.Workspace
+				break
+			}
+		}
+	}
+	if w.state.FocusedWorkspace == "" && len(w.state.Workspaces) > 0 {
+		w.state.FocusedWorkspace = w.state.Workspaces[0].Workspace
+	}
+	if w.state.FocusedWorkspace != "" {
+		w.ensureWorkspace(w.state.FocusedWorkspace)
+	}
+}
+
+// ensureWorkspace returns the workspace with the given name, creating it on
+// the focused monitor when it does not exist, like AeroSpace does.
+func (w *World) ensureWorkspace(name string) *Workspace {
+	if workspace := w.findWorkspace(name); workspace != nil {
+		return workspace
+	}
+
+	monitorID := w.state.Monitors[0].MonitorID
+	if focused := w.findWorkspace(w.state.FocusedWorkspace); focused != nil {
+		monitorID = focused.MonitorID
+	}
+
+	w.state.Workspaces = append(w.state.Workspaces, Workspace{
+		Workspace: name,
+		MonitorID: monitorID,
+	})
+	return &w.state.Workspaces[len(w.state.Workspaces)-1]
+}
+
+func (w *World) findWorkspace(name string) *Workspace {
+	for i := range w.state.Workspaces {
+		if w.state.Workspaces[i].Workspace == name {
+			return &w.state.Workspaces[i]
+		}
+	}
+	return nil
+}
+
+func (w *World) findWindow(windowID int) *Window {
+	for i := range w.state.Windows {
+		if w.state.Windows[i].WindowID == windowID {
+			return &w.state.Windows[i]
+		}
+	}
+	return nil
+}
+
+func (w *World) findMonitor(monitorID int) *Monitor {
+	for i := range w.state.Monitors {
+		if w.state.Monitors[i].MonitorID == monitorID {
+			return &w.state.Monitors[i]
+		}
+	}
+	return nil
+}
+
+func (w *World) focusedWindow() *Window {
+	workspace := w.findWorkspace(w.state.FocusedWorkspace)
+	if workspace == nil || workspace.FocusedWindowID == 0 {
+		return nil
+	}
+	return w.findWindow(workspace.FocusedWindowID)
+}
+
+func (w *World) focusedMonitor() *Monitor {
+	workspace := w.findWorkspace(w.state.FocusedWorkspace)
+	if workspace == nil {
+		return &w.state.Monitors[0]
+	}
+	if monitor := w.findMonitor(workspace.MonitorID); monitor != nil {
+		return monitor
+	}
+	return &w.state.Monitors[0]
+}
+
+func (w *World) windowsInWorkspace(name string) []Window {
+	var result []Window
+	for _, window := range w.state.Windows {
+		if window.Workspace == name {
+			result = append(result, window)
+		}
+	}
+	return result
+}
+
+func (w *World) monitorOfWorkspace(name string) int {
+	if workspace := w.findWorkspace(name); workspace != nil {
+		return workspace.MonitorID
+	}
+	return w.state.Monitors[0].MonitorID
+}
+
+// setFocusedWorkspace focuses the workspace, which becomes the one shown on
+// its monitor. The workspace focused before stays shown on its own monitor.
+func (w *World) setFocusedWorkspace(name string) {
+	if previous := w.findWorkspace(w.state.FocusedWorkspace); previous != nil {
+		w.showWorkspace(*previous)
+	}
+	w.state.FocusedWorkspace = name
+	if focused := w.findWorkspace(name); focused != nil {
+		w.state.VisibleWorkspaces = slices.DeleteFunc(w.state.VisibleWorkspaces, func(visible string) bool {
+			workspace := w.findWorkspace(visible)
+			return workspace == nil || workspace.MonitorID == focused.MonitorID
+		})
+	}
+}
+
+func (w *World) showWorkspace(shown Workspace) {
+	w.state.VisibleWorkspaces = slices.DeleteFunc(w.state.VisibleWorkspaces, func(visible string) bool {
+		workspace := w.findWorkspace(visible)
+		return workspace == nil || workspace.MonitorID == shown.MonitorID
+	})
+	w.state.VisibleWorkspaces = append(w.state.VisibleWorkspaces, shown.Workspace)
+}
+
+// isVisible reports whether the workspace is the one shown on its monitor:
+// the focused one, else the one in VisibleWorkspaces, else its first one.
+func (w *World) isVisible(workspace Workspace) bool {
+	shown := map[int]string{}
+	for _, name := range append(slices.Clone(w.state.VisibleWorkspaces), w.state.FocusedWorkspace) {
+		if candidate := w.findWorkspace(name); candidate != nil {
+			shown[candidate.MonitorID] = candidate.Workspace
+		}
+	}
+	for _, candidate := range w.state.Workspaces {
+		if _, ok := shown[candidate.MonitorID]; !ok {
+			shown[candidate.MonitorID] = candidate.Workspace
+		}
+	}
+	return shown[workspace.MonitorID] == workspace.Workspace
+}
+
+// refocusWorkspace picks the next window to focus in a workspace after the
+// focused one left it. The most recently added window wins, mirroring the
+// way AeroSpace falls back to the closest sibling.
+func (w *World) refocusWorkspace(workspace *Workspace) {
+	remaining := w.windowsInWorkspace(workspace.Workspace)
+	if len(remaining) == 0 {
+		workspace.FocusedWindowID = 0
+		return
+	}
+	workspace.FocusedWindowID = remaining[len(remaining)-1].WindowID
+}
+
+func (w *World) sortedWorkspaces() []Workspace {
+	workspaces := append([]Workspace(nil), w.state.Workspaces...)
+	sort.SliceStable(workspaces, func(i, j int) bool {
+		return workspaces[i].Workspace < workspaces[j].Workspace
+	})
+	return workspaces
+}
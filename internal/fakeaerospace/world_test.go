@@ -0,0 +1,182 @@
+package fakeaerospace_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+)
+
+const worldYAML = `
+workspaces:
+  - workspace: ws1
+    focused-window-id: 1
+windows:
+  - window-id: 1
+    window-title: Terminal
+    app-name: Ghostty
+    app-bundle-id: com.mitchellh.ghostty
+    workspace: ws1
+  - window-id: 2
+    window-title: Finder
+    app-name: Finder
+    app-bundle-id: com.apple.finder
+    workspace: .scratchpad
+    window-layout: floating
+`
+
+func mustParseWorld(t *testing.T) *fakeaerospace.World {
+	t.Helper()
+
+	world, err := fakeaerospace.ParseWorld([]byte(worldYAML))
+	if err != nil {
+		t.Fatalf("unexpected error parsing world: %v", err)
+	}
+	return world
+}
+
+func TestWorld(t *testing.T) {
+	t.Run("fills the defaults", func(t *testing.T) {
+		state := mustParseWorld(t).Snapshot()
+
+		if state.ServerVersion != fakeaerospace.DefaultServerVersion {
+			t.Fatalf("expected default server version, got %q", state.ServerVersion)
+		}
+		if state.FocusedWorkspace != "ws1" {
+			t.Fatalf("expected ws1 to be focused, got %q", state.FocusedWorkspace)
+		}
+		if len(state.Workspaces) != 2 {
+			t.Fatalf("expected the scratchpad workspace to be created, got %+v", state.Workspaces)
+		}
+		if len(state.Monitors) != 1 {
+			t.Fatalf("expected a default monitor, got %+v", state.Monitors)
+		}
+	})
+
+	t.Run("lists windows with the requested format", func(t *testing.T) {
+		world := mustParseWorld(t)
+
+		response := world.Execute([]string{
+			"list-windows", "--all", "--json", "--format", "%{window-id} %{app-name}",
+		})
+
+		expected := `[{"window-id":1,"app-name":"Ghostty"},{"window-id":2,"app-name":"Finder"}]`
+		if response.ExitCode != 0 || response.StdOut != expected {
+			t.Fatalf("unexpected response: %+v", response)
+		}
+	})
+
+	t.Run("moves a window and keeps focus when not following", func(t *testing.T) {
+		world := mustParseWorld(t)
+
+		response := world.Execute([]string{"move-node-to-workspace", ".scratchpad", "--window-id", "1"})
+		if response.ExitCode != 0 {
+			t.Fatalf("unexpected failure: %+v", response)
+		}
+
+		focused := world.Execute([]string{"list-windows", "--focused", "--json"})
+		if focused.StdOut != "[]" {
+			t.Fatalf("expected no focused window, got %s", focused.StdOut)
+		}
+
+		inScratchpad := world.Execute([]string{"list-windows", "--workspace", ".scratchpad", "--count"})
+		if inScratchpad.StdOut != "2" {
+			t.Fatalf("expected 2 windows in scratchpad, got %s", inScratchpad.StdOut)
+		}
+	})
+
+	t.Run("moves a window with focus following it", func(t *testing.T) {
+		world := mustParseWorld(t)
+
+		world.Execute([]string{
+			"move-node-to-workspace", "ws1", "--window-id", "2", "--focus-follows-window",
+		})
+
+		state := world.Snapshot()
+		if state.FocusedWorkspace != "ws1" {
+			t.Fatalf("expected ws1 to be focused, got %q", state.FocusedWorkspace)
+		}
+		for _, workspace := range state.Workspaces {
+			if workspace.Workspace == "ws1" && workspace.FocusedWindowID != 2 {
+				t.Fatalf("expected window 2 to be focused, got %d", workspace.FocusedWindowID)
+			}
+		}
+	})
+
+	t.Run("reports noop moves only when asked to fail", func(t *testing.T) {
+		world := mustParseWorld(t)
+
+		noop := world.Execute([]string{"move-node-to-workspace", "ws1", "--window-id", "1"})
+		if noop.ExitCode != 0 || !strings.Contains(noop.StdErr, "already belongs") {
+			t.Fatalf("unexpected noop response: %+v", noop)
+		}
+
+		failed := world.Execute([]string{
+			"move-node-to-workspace", "ws1", "--window-id", "1", "--fail-if-noop",
+		})
+		if failed.ExitCode != 1 {
+			t.Fatalf("expected failure, got %+v", failed)
+		}
+	})
+
+	t.Run("cycles layouts", func(t *testing.T) {
+		world := mustParseWorld(t)
+
+		world.Execute([]string{"layout", "floating", "tiling", "--window-id", "2"})
+
+		state := world.Snapshot()
+		for _, window := range state.Windows {
+			if window.WindowID == 2 && window.WindowLayout != "h_tiles" {
+				t.Fatalf("expected window 2 to be tiled, got %q", window.WindowLayout)
+			}
+		}
+	})
+
+	t.Run("lists the workspace shown on each monitor", func(t *testing.T) {
+		world, err := fakeaerospace.ParseWorld([]byte(`
+monitors:
+  - monitor-id: 1
+  - monitor-id: 2
+workspaces:
+  - workspace: ws1
+    monitor-id: 1
+  - workspace: ws2
+    monitor-id: 1
+  - workspace: ws3
+    monitor-id: 2
+  - workspace: ws4
+    monitor-id: 2
+`))
+		if err != nil {
+			t.Fatalf("unexpected error parsing world: %v", err)
+		}
+		visible := func() string {
+			t.Helper()
+			response := world.Execute([]string{"list-workspaces", "--monitor", "all", "--visible"})
+			if response.ExitCode != 0 {
+				t.Fatalf("unexpected response: %+v", response)
+			}
+			return strings.Join(strings.Fields(response.StdOut), " ")
+		}
+
+		if got := visible(); got != "ws1 ws3" {
+			t.Fatalf("expected the first workspaces shown, got %q", got)
+		}
+		world.Execute([]string{"workspace", "ws4"})
+		world.Execute([]string{"workspace", "ws2"})
+		if got := visible(); got != "ws2 ws4" {
+			t.Fatalf("expected ws4 kept on its monitor, got %q", got)
+		}
+	})
+
+	t.Run("fails on unknown windows and commands", func(t *testing.T) {
+		world := mustParseWorld(t)
+
+		if response := world.Execute([]string{"focus", "--window-id", "42"}); response.ExitCode != 1 {
+			t.Fatalf("expected failure for unknown window, got %+v", response)
+		}
+		if response := world.Execute([]string{"unknown-command"}); response.ExitCode != 1 {
+			t.Fatalf("expected failure for unknown command, got %+v", response)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package ipcrecord_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/ipcrecord"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	world := fakeaerospace.NewWorld(fakeaerospace.State{
+		Windows: []fakeaerospace.Window{
+			{WindowID: 1, AppName: "Finder", Workspace: "ws1"},
+		},
+	})
+
+	var session bytes.Buffer
+	recorder := ipcrecord.NewRecordingConnection(fakeaerospace.NewConnection(world), &session)
+
+	listed, err := recorder.SendCommand("list-windows", []string{"--all", "--json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = recorder.SendCommand("focus", []string{"--window-id", "42"}); err == nil {
+		t.Fatalf("expected error focusing unknown window")
+	}
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err = os.WriteFile(path, session.Bytes(), 0o600); err != nil {
+		t.Fatalf("unable to write session: %v", err)
+	}
+
+	entries, err := ipcrecord.LoadSession(path)
+	if err != nil {
+		t.Fatalf("unable to load session: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].DurationMs < 0 || entries[0].Time.IsZero() {
+		t.Fatalf("expected timing information, got %+v", entries[0])
+	}
+
+	t.Run("replays responses and errors in any order", func(t *testing.T) {
+		replay := ipcrecord.NewReplayConnection(entries)
+
+		if _, err := replay.SendCommand("focus", []string{"--window-id", "42"}); err == nil {
+			t.Fatalf("expected recorded error")
+		}
+
+		replayed, err := replay.SendCommand("list-windows", []string{"--all", "--json"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if replayed.StdOut != listed.StdOut {
+			t.Fatalf("expected %q, got %q", listed.StdOut, replayed.StdOut)
+		}
+
+		if pending := replay.Pending(); len(pending) != 0 {
+			t.Fatalf("expected no pending entries, got %+v", pending)
+		}
+	})
+
+	t.Run("serves each response once", func(t *testing.T) {
+		replay := ipcrecord.NewReplayConnection(entries)
+
+		args := []string{"--all", "--json"}
+		if _, err := replay.SendCommand("list-windows", args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err := replay.SendCommand("list-windows", args)
+		if !errors.Is(err, ipcrecord.ErrNoRecordedResponse) {
+			t.Fatalf("expected ErrNoRecordedResponse, got %v", err)
+		}
+	})
+
+	t.Run("reports the recorded server version", func(t *testing.T) {
+		version, err := ipcrecord.NewReplayConnection(entries).GetServerVersion()
+		if err != nil || version != fakeaerospace.DefaultServerVersion {
+			t.Fatalf("unexpected server version %q: %v", version, err)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+// Package ipcrecord records the traffic between the CLI and AeroSpace and
+// replays it later, so tests can run against real AeroSpace output.
+//
+// A session is a JSON lines file, one Entry per SendCommand call.
+package ipcrecord
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+// Entry is a recorded SendCommand call.
+type Entry struct {
+	// Time is when the request was sent.
+	Time time.Time `json:"time"`
+	// Command is the AeroSpace command, e.g. list-windows.
+	Command string `json:"command"`
+	// Args are the command arguments.
+	Args []string `json:"args"`
+	// Response is the response from AeroSpace, nil when the call failed.
+	Response *client.Response `json:"response,omitempty"`
+	// Error is the error returned by the connection, if any.
+	Error string `json:"error,omitempty"`
+	// DurationMs is how long the call took in milliseconds.
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// RecordingConnection wraps a connection and writes every SendCommand
+// request and response to a session.
+type RecordingConnection struct {
+	conn client.AeroSpaceConnection
+
+	mu      sync.Mutex
+	session io.Writer
+}
+
+// NewRecordingConnection records the traffic of conn into session.
+// If session is an io.Closer it is closed together with the connection.
+func NewRecordingConnection(
+	conn client.AeroSpaceConnection,
+	session io.Writer,
+) *RecordingConnection {
+	return &RecordingConnection{
+		conn:    conn,
+		session: session,
+	}
+}
+
+// SendCommand sends the command and records it.
+func (r *RecordingConnection) SendCommand(command string, args []string) (*client.Response, error) {
+	startedAt := time.Now()
+	response, err := r.conn.SendCommand(command, args)
+	elapsed := time.Since(startedAt)
+
+	entry := Entry{
+		Time:       startedAt,
+		Command:    command,
+		Args:       args,
+		Response:   response,
+		DurationMs: float64(elapsed) / float64(time.Millisecond),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if writeErr := r.write(entry); writeErr != nil {
+		return response, fmt.Errorf("unable to record command %s: %w", command, writeErr)
+	}
+
+	return response, err
+}
+
+// GetSocketPath returns the socket path of the wrapped connection.
+func (r *RecordingConnection) GetSocketPath() (string, error) {
+	return r.conn.GetSocketPath()
+}
+
+// GetServerVersion returns the server version of the wrapped connection.
+func (r *RecordingConnection) GetServerVersion() (string, error) {
+	return r.conn.GetServerVersion()
+}
+
+// CheckServerVersion checks the server version of the wrapped connection.
+func (r *RecordingConnection) CheckServerVersion() error {
+	return r.conn.CheckServerVersion()
+}
+
+// Unwrap returns the recorded connection.
+func (r *RecordingConnection) Unwrap() client.AeroSpaceConnection {
+	return r.conn
+}
+
+// CloseConnection closes the wrapped connection and the session.
+func (r *RecordingConnection) CloseConnection() error {
+	err := r.conn.CloseConnection()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if closer, ok := r.session.(io.Closer); ok {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+func (r *RecordingConnection) write(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// One write per entry, so a crash never leaves a partial line behind.
+	_, err = r.session.Write(append(line, '\n'))
+	return err
+}
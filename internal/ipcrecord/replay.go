@@ -0,0 +1,140 @@
+package ipcrecord
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+const (
+	// ReplaySocketPath is reported as the socket path of replay connections.
+	ReplaySocketPath = "replay"
+
+	// maxLineSize fits the largest response the aerospace-ipc client accepts.
+	maxLineSize = 16 * 1024 * 1024
+)
+
+// ErrNoRecordedResponse is returned when a session has no entry left for a
+// request.
+var ErrNoRecordedResponse = errors.New("no recorded response")
+
+// ReplayConnection serves the responses of a recorded session.
+//
+// Each request is answered by the first unused entry with the same command
+// and arguments, so the calls don't need to happen in the recorded order
+// but each recorded response is served only once.
+type ReplayConnection struct {
+	mu      sync.Mutex
+	entries []Entry
+	used    []bool
+}
+
+// NewReplayConnection creates a connection that replays the given entries.
+func NewReplayConnection(entries []Entry) *ReplayConnection {
+	return &ReplayConnection{
+		entries: entries,
+		used:    make([]bool, len(entries)),
+	}
+}
+
+// LoadSession reads the entries of a session file.
+func LoadSession(path string) ([]Entry, error) {
+	// #nosec G304 -- session files are provided on purpose.
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open session: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("unable to parse session line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read session: %w", err)
+	}
+
+	return entries, nil
+}
+
+// SendCommand returns the recorded response for the request.
+func (r *ReplayConnection) SendCommand(command string, args []string) (*client.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, entry := range r.entries {
+		if r.used[i] || entry.Command != command || !slices.Equal(entry.Args, args) {
+			continue
+		}
+		r.used[i] = true
+
+		if entry.Error != "" {
+			return nil, errors.New(entry.Error)
+		}
+		if entry.Response == nil {
+			break
+		}
+		response := *entry.Response
+		return &response, nil
+	}
+
+	return nil, fmt.Errorf("%w for: %s %v", ErrNoRecordedResponse, command, args)
+}
+
+// Pending returns the recorded entries that were not replayed yet.
+func (r *ReplayConnection) Pending() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending []Entry
+	for i, entry := range r.entries {
+		if !r.used[i] {
+			pending = append(pending, entry)
+		}
+	}
+	return pending
+}
+
+// GetSocketPath returns ReplaySocketPath.
+func (r *ReplayConnection) GetSocketPath() (string, error) {
+	return ReplaySocketPath, nil
+}
+
+// GetServerVersion returns the server version found in the recorded responses.
+func (r *ReplayConnection) GetServerVersion() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, entry := range r.entries {
+		if entry.Response != nil && entry.Response.ServerVersion != "" {
+			return entry.Response.ServerVersion, nil
+		}
+	}
+	return "", fmt.Errorf("%w with a server version", ErrNoRecordedResponse)
+}
+
+// CheckServerVersion always succeeds, the session is what it is.
+func (r *ReplayConnection) CheckServerVersion() error {
+	return nil
+}
+
+// CloseConnection is a no-op.
+func (r *ReplayConnection) CloseConnection() error {
+	return nil
+}
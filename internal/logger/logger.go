@@ -115,14 +115,20 @@ func (l *EmptyLogger) AsJSON(_ any) string {
 	return ""
 }
 
+// Path returns the file the logs are written to, set with the
+// AEROSPACE_SCRATCHPAD_LOGS_PATH env var.
+func Path() string {
+	if path := os.Getenv(constants.EnvAeroSpaceScratchpadLogsPath); path != "" {
+		return path
+	}
+	return "/tmp/aerospace-scratchpad.log"
+}
+
 // NewLogger creates a new logger instance
 // It accepts a path to a file where logs will be written
 // and a boolean indicating whether to log to stdout as well.
 func NewLogger() (Logger, error) {
-	path := os.Getenv(constants.EnvAeroSpaceScratchpadLogsPath)
-	if path == "" {
-		path = "/tmp/aerospace-scratchpad.log"
-	}
+	path := Path()
 
 	// #nosec G304,G703 -- log path is intentionally configurable via env var.
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
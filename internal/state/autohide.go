@@ -0,0 +1,141 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// AutoHideWindow is a shown window that goes back to the scratchpad of its
+// monitor once another window gains focus, or once it has been without focus
+// for HideAfter.
+type AutoHideWindow struct {
+	WindowID  int    `json:"window_id"`
+	AppName   string `json:"app_name"`
+	MonitorID int    `json:"monitor_id"`
+	// HideAfter is how long the window may go without focus, zero hides it as
+	// soon as it loses focus.
+	HideAfter time.Duration `json:"hide_after,omitempty"`
+	// Workspace is where the window was shown. Moving it elsewhere cancels
+	// the auto-hide.
+	Workspace string    `json:"workspace,omitempty"`
+	ShownAt   time.Time `json:"shown_at,omitzero"`
+}
+
+// Timed reports whether the window hides after a period without focus.
+func (w AutoHideWindow) Timed() bool {
+	return w.HideAfter > 0
+}
+
+// AutoHide is the set of auto-hide windows, stored as a JSON array.
+type AutoHide struct {
+	path string
+}
+
+// NewAutoHide creates an auto-hide set stored in path.
+func NewAutoHide(path string) *AutoHide {
+	return &AutoHide{
+		path: path,
+	}
+}
+
+// DefaultAutoHidePath returns the auto-hide set file in the state Dir.
+func DefaultAutoHidePath() string {
+	return filepath.Join(Dir(), "autohide.json")
+}
+
+// List returns the auto-hide windows. A set that doesn't exist yet is empty.
+func (a *AutoHide) List() ([]AutoHideWindow, error) {
+	data, err := os.ReadFile(a.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read auto-hide windows: %w", err)
+	}
+
+	var windows []AutoHideWindow
+	if err = json.Unmarshal(data, &windows); err != nil {
+		return nil, fmt.Errorf("unable to parse auto-hide windows: %w", err)
+	}
+	return windows, nil
+}
+
+// Add tracks the windows, the ones already tracked are updated. The set is
+// locked while it is rewritten, the hooks, the hide timer and the commands
+// showing windows change it concurrently.
+func (a *AutoHide) Add(windows ...AutoHideWindow) error {
+	lock, err := a.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	tracked, err := a.List()
+	if err != nil {
+		return err
+	}
+
+	for _, window := range windows {
+		index := slices.IndexFunc(tracked, func(current AutoHideWindow) bool {
+			return current.WindowID == window.WindowID
+		})
+		if index >= 0 {
+			tracked[index] = window
+			continue
+		}
+		tracked = append(tracked, window)
+	}
+
+	return a.write(tracked)
+}
+
+// Remove stops tracking the windows with the given ids.
+func (a *AutoHide) Remove(windowIDs ...int) error {
+	lock, err := a.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	tracked, err := a.List()
+	if err != nil {
+		return err
+	}
+
+	tracked = slices.DeleteFunc(tracked, func(window AutoHideWindow) bool {
+		return slices.Contains(windowIDs, window.WindowID)
+	})
+	return a.write(tracked)
+}
+
+// Replace rewrites the set with windows.
+func (a *AutoHide) Replace(windows []AutoHideWindow) error {
+	lock, err := a.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	return a.write(windows)
+}
+
+func (a *AutoHide) lock() (*Lock, error) {
+	return WaitLock(a.path + ".lock")
+}
+
+func (a *AutoHide) write(windows []AutoHideWindow) error {
+	if windows == nil {
+		windows = []AutoHideWindow{}
+	}
+
+	data, err := json.MarshalIndent(windows, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode auto-hide windows: %w", err)
+	}
+	return writeFileAtomic(a.path, append(data, '\n'))
+}
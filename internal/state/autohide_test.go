@@ -0,0 +1,35 @@
+package state_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+)
+
+func TestAutoHide(t *testing.T) {
+	t.Run("keeps the windows added concurrently", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "autohide.json")
+
+		var wg sync.WaitGroup
+		for id := 1; id <= 50; id++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := state.NewAutoHide(path).Add(state.AutoHideWindow{WindowID: id}); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		tracked, err := state.NewAutoHide(path).List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tracked) != 50 {
+			t.Fatalf("expected 50 windows, got %d", len(tracked))
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// FocusReturn is the window focused before a scratchpad window was brought
+// in, focused again once that window is hidden.
+type FocusReturn struct {
+	WindowID         int `json:"window_id"`
+	PreviousWindowID int `json:"previous_window_id"`
+}
+
+// FocusReturns is the set of focus returns, stored as a JSON array.
+type FocusReturns struct {
+	path string
+}
+
+// NewFocusReturns creates a focus return set stored in path.
+func NewFocusReturns(path string) *FocusReturns {
+	return &FocusReturns{
+		path: path,
+	}
+}
+
+// DefaultFocusReturnsPath returns the focus returns file in the state Dir.
+func DefaultFocusReturnsPath() string {
+	return filepath.Join(Dir(), "focus-returns.json")
+}
+
+// List returns the focus returns. A set that doesn't exist yet is empty.
+func (f *FocusReturns) List() ([]FocusReturn, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read focus returns: %w", err)
+	}
+
+	var returns []FocusReturn
+	if err = json.Unmarshal(data, &returns); err != nil {
+		return nil, fmt.Errorf("unable to parse focus returns: %w", err)
+	}
+	return returns, nil
+}
+
+// Set records the focus returns, replacing the ones of the same windows. The
+// set is locked while it is rewritten, show, summon and the hooks change it
+// concurrently.
+func (f *FocusReturns) Set(returns ...FocusReturn) error {
+	lock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	current, err := f.List()
+	if err != nil {
+		return err
+	}
+
+	for _, focusReturn := range returns {
+		index := slices.IndexFunc(current, func(existing FocusReturn) bool {
+			return existing.WindowID == focusReturn.WindowID
+		})
+		if index >= 0 {
+			current[index] = focusReturn
+			continue
+		}
+		current = append(current, focusReturn)
+	}
+
+	return f.replace(current)
+}
+
+// Take removes the focus returns of the windows with the given ids and
+// returns them.
+func (f *FocusReturns) Take(windowIDs ...int) ([]FocusReturn, error) {
+	lock, err := f.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	current, err := f.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var taken []FocusReturn
+	current = slices.DeleteFunc(current, func(focusReturn FocusReturn) bool {
+		if slices.Contains(windowIDs, focusReturn.WindowID) {
+			taken = append(taken, focusReturn)
+			return true
+		}
+		return false
+	})
+	if len(taken) == 0 {
+		return nil, nil
+	}
+	return taken, f.replace(current)
+}
+
+func (f *FocusReturns) lock() (*Lock, error) {
+	return WaitLock(f.path + ".lock")
+}
+
+func (f *FocusReturns) replace(returns []FocusReturn) error {
+	if returns == nil {
+		returns = []FocusReturn{}
+	}
+
+	data, err := json.MarshalIndent(returns, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode focus returns: %w", err)
+	}
+	return writeFileAtomic(f.path, append(data, '\n'))
+}
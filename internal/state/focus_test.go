@@ -0,0 +1,74 @@
+package state_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+)
+
+func TestFocusReturns(t *testing.T) {
+	t.Run("replaces the return of a window and takes them once", func(t *testing.T) {
+		returns := state.NewFocusReturns(filepath.Join(t.TempDir(), "focus-returns.json"))
+
+		err := returns.Set(
+			state.FocusReturn{WindowID: 1, PreviousWindowID: 10},
+			state.FocusReturn{WindowID: 2, PreviousWindowID: 10},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = returns.Set(state.FocusReturn{WindowID: 1, PreviousWindowID: 20}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		taken, err := returns.Take(1, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(taken) != 1 || taken[0].PreviousWindowID != 20 {
+			t.Fatalf("expected the latest return of window 1, got %+v", taken)
+		}
+
+		taken, err = returns.Take(1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(taken) != 0 {
+			t.Fatalf("expected nothing left for window 1, got %+v", taken)
+		}
+
+		left, err := returns.List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(left) != 1 || left[0].WindowID != 2 {
+			t.Fatalf("expected only window 2 left, got %+v", left)
+		}
+	})
+	t.Run("keeps the returns set concurrently", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "focus-returns.json")
+
+		var wg sync.WaitGroup
+		for id := 1; id <= 50; id++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				focusReturn := state.FocusReturn{WindowID: id, PreviousWindowID: 100}
+				if err := state.NewFocusReturns(path).Set(focusReturn); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		returns, err := state.NewFocusReturns(path).List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(returns) != 50 {
+			t.Fatalf("expected 50 returns, got %d", len(returns))
+		}
+	})
+}
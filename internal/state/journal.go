@@ -0,0 +1,150 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// DefaultJournalSize is how many operations the journal keeps.
+const DefaultJournalSize = 100
+
+// JournalEntry is a window moved by a command.
+type JournalEntry struct {
+	Time           time.Time `json:"time"`
+	Command        string    `json:"command"`
+	WindowID       int       `json:"window_id"`
+	AppName        string    `json:"app_name"`
+	FromWorkspace  string    `json:"from_workspace"`
+	ToWorkspace    string    `json:"to_workspace"`
+	PreviousLayout string    `json:"previous_layout"`
+}
+
+// Journal is a bounded log of the windows moved, stored as JSON lines.
+// Only the last size entries are kept.
+type Journal struct {
+	path string
+	size int
+}
+
+// NewJournal creates a journal stored in path that keeps size entries.
+func NewJournal(path string, size int) *Journal {
+	return &Journal{
+		path: path,
+		size: size,
+	}
+}
+
+// DefaultJournalPath returns the journal file in the state Dir.
+func DefaultJournalPath() string {
+	return filepath.Join(Dir(), "journal.jsonl")
+}
+
+// Entries returns the entries from the oldest to the newest.
+// A journal that doesn't exist yet is empty.
+func (j *Journal) Entries() ([]JournalEntry, error) {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read journal: %w", err)
+	}
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// A damaged line must not make the whole journal unusable.
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read journal: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Append adds entries to the journal, dropping the oldest ones over its size.
+// The journal is locked while it is rewritten, so concurrent commands and
+// hooks don't lose each other's entries.
+func (j *Journal) Append(entries ...JournalEntry) error {
+	lock, err := j.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	current, err := j.Entries()
+	if err != nil {
+		return err
+	}
+
+	return j.write(append(current, entries...))
+}
+
+// Remove drops the given entries, e.g. the ones undone, newest first. The
+// journal is read again under the lock, so the entries appended meanwhile
+// are kept.
+func (j *Journal) Remove(entries ...JournalEntry) error {
+	lock, err := j.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	current, err := j.Entries()
+	if err != nil {
+		return err
+	}
+	for _, removed := range entries {
+		for index := len(current) - 1; index >= 0; index-- {
+			if current[index].sameAs(removed) {
+				current = slices.Delete(current, index, index+1)
+				break
+			}
+		}
+	}
+
+	return j.write(current)
+}
+
+// sameAs reports whether both entries record the same operation. The times
+// are compared with Equal, their locations differ once decoded.
+func (e JournalEntry) sameAs(other JournalEntry) bool {
+	sameTime := e.Time.Equal(other.Time)
+	e.Time, other.Time = time.Time{}, time.Time{}
+	return sameTime && e == other
+}
+
+func (j *Journal) lock() (*Lock, error) {
+	return WaitLock(j.path + ".lock")
+}
+
+func (j *Journal) write(entries []JournalEntry) error {
+	if j.size > 0 && len(entries) > j.size {
+		entries = entries[len(entries)-j.size:]
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("unable to encode journal entry: %w", err)
+		}
+	}
+
+	return writeFileAtomic(j.path, buffer.Bytes())
+}
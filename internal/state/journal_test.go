@@ -0,0 +1,131 @@
+package state_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+)
+
+func TestJournal(t *testing.T) {
+	t.Run("is empty when the file doesn't exist", func(t *testing.T) {
+		journal := state.NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"), 10)
+
+		entries, err := journal.Entries()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 0 {
+			t.Fatalf("expected no entries, got %+v", entries)
+		}
+	})
+
+	t.Run("keeps only the newest entries", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state", "journal.jsonl")
+		journal := state.NewJournal(path, 2)
+
+		for id := 1; id <= 3; id++ {
+			if err := journal.Append(state.JournalEntry{
+				Time:     time.Now(),
+				Command:  "move",
+				WindowID: id,
+			}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		entries, err := journal.Entries()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 2 || entries[0].WindowID != 2 || entries[1].WindowID != 3 {
+			t.Fatalf("expected windows 2 and 3, got %+v", entries)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Fatalf("expected a private journal, got %v", info.Mode().Perm())
+		}
+	})
+
+	t.Run("skips damaged lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.jsonl")
+		content := "{\"window_id\":1}\nnot json\n{\"window_id\":2}\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("unable to write journal: %v", err)
+		}
+
+		entries, err := state.NewJournal(path, 10).Entries()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 entries, got %+v", entries)
+		}
+	})
+
+	t.Run("removes only the given entries", func(t *testing.T) {
+		journal := state.NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"), 10)
+		for id := 1; id <= 3; id++ {
+			if err := journal.Append(state.JournalEntry{Time: time.Now(), WindowID: id}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		undone, err := journal.Entries()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Appended by another process while the last entry is undone.
+		if err = journal.Append(state.JournalEntry{Time: time.Now(), WindowID: 4}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err = journal.Remove(undone[2]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		entries, err := journal.Entries()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var ids []int
+		for _, entry := range entries {
+			ids = append(ids, entry.WindowID)
+		}
+		if !slices.Equal(ids, []int{1, 2, 4}) {
+			t.Fatalf("expected windows 1, 2 and 4, got %v", ids)
+		}
+	})
+
+	t.Run("keeps the entries appended concurrently", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.jsonl")
+
+		var wg sync.WaitGroup
+		for id := 1; id <= 50; id++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				journal := state.NewJournal(path, 100)
+				if err := journal.Append(state.JournalEntry{WindowID: id}); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		entries, err := state.NewJournal(path, 100).Entries()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 50 {
+			t.Fatalf("expected 50 entries, got %d", len(entries))
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// ErrLocked is returned when another process holds the lock.
+var ErrLocked = errors.New("locked by another process")
+
+// Lock is an exclusive lock on a file, released by Unlock or when the
+// process exits.
+type Lock struct {
+	file *os.File
+}
+
+// DefaultHideTimerLockPath returns the lock held by the process hiding the
+// windows shown with --hide-after.
+func DefaultHideTimerLockPath() string {
+	return filepath.Join(Dir(), "hidetimer.lock")
+}
+
+// TryLock takes the lock on path without waiting, ErrLocked when another
+// process holds it.
+func TryLock(path string) (*Lock, error) {
+	return lock(path, syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+// WaitLock takes the lock on path, waiting while another process holds it.
+func WaitLock(path string) (*Lock, error) {
+	return lock(path, syscall.LOCK_EX)
+}
+
+func lock(path string, how int) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), dirFileMode); err != nil {
+		return nil, fmt.Errorf("unable to create state dir: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, fileMode)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open lock: %w", err)
+	}
+	if err = syscall.Flock(int(file.Fd()), how); err != nil {
+		_ = file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("unable to lock %s: %w", path, err)
+	}
+
+	return &Lock{file: file}, nil
+}
+
+// Unlock releases the lock.
+func (l *Lock) Unlock() error {
+	defer l.file.Close()
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+}
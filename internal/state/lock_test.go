@@ -0,0 +1,63 @@
+package state_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+)
+
+func TestTryLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timer.lock")
+
+	lock, err := state.TryLock(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = state.TryLock(path); !errors.Is(err, state.ErrLocked) {
+		t.Fatalf("expected the lock to be held, got %v", err)
+	}
+
+	if err = lock.Unlock(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lock, err = state.TryLock(path)
+	if err != nil {
+		t.Fatalf("expected the lock to be released, got %v", err)
+	}
+	_ = lock.Unlock()
+}
+
+func TestWaitLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.lock")
+
+	lock, err := state.TryLock(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	acquired := make(chan *state.Lock)
+	go func() {
+		waiting, waitErr := state.WaitLock(path)
+		if waitErr != nil {
+			t.Errorf("unexpected error: %v", waitErr)
+		}
+		acquired <- waiting
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("expected to wait for the lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err = lock.Unlock(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if waiting := <-acquired; waiting != nil {
+		_ = waiting.Unlock()
+	}
+}
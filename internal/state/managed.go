@@ -0,0 +1,82 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// DefaultManagedSize is how many windows the managed set keeps.
+const DefaultManagedSize = 1000
+
+// Managed is the set of windows aerospace-scratchpad moved to a scratchpad
+// workspace, stored as a JSON array of window ids. Only the last size windows
+// moved are kept.
+type Managed struct {
+	path string
+	size int
+}
+
+// NewManaged creates a managed set stored in path that keeps size windows.
+func NewManaged(path string, size int) *Managed {
+	return &Managed{
+		path: path,
+		size: size,
+	}
+}
+
+// DefaultManagedPath returns the managed set file in the state Dir.
+func DefaultManagedPath() string {
+	return filepath.Join(Dir(), "managed.json")
+}
+
+// List returns the managed window ids, from the oldest to the newest move.
+// A set that doesn't exist yet is empty.
+func (m *Managed) List() ([]int, error) {
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read managed windows: %w", err)
+	}
+
+	var windowIDs []int
+	if err = json.Unmarshal(data, &windowIDs); err != nil {
+		return nil, fmt.Errorf("unable to parse managed windows: %w", err)
+	}
+	return windowIDs, nil
+}
+
+// Add records the windows as moved to a scratchpad workspace, dropping the
+// ones moved the longest ago over its size. The set is locked while it is
+// rewritten, the windows are moved by concurrent commands and hooks.
+func (m *Managed) Add(windowIDs ...int) error {
+	lock, err := WaitLock(m.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	managed, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	managed = slices.DeleteFunc(managed, func(windowID int) bool {
+		return slices.Contains(windowIDs, windowID)
+	})
+	managed = append(managed, windowIDs...)
+	if m.size > 0 && len(managed) > m.size {
+		managed = managed[len(managed)-m.size:]
+	}
+
+	data, err := json.Marshal(managed)
+	if err != nil {
+		return fmt.Errorf("unable to encode managed windows: %w", err)
+	}
+	return writeFileAtomic(m.path, append(data, '\n'))
+}
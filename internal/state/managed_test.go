@@ -0,0 +1,41 @@
+package state_test
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+)
+
+func TestManaged(t *testing.T) {
+	t.Run("is empty when the file doesn't exist", func(t *testing.T) {
+		managed := state.NewManaged(filepath.Join(t.TempDir(), "managed.json"), 10)
+
+		windowIDs, err := managed.List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(windowIDs) != 0 {
+			t.Fatalf("expected no managed windows, got %v", windowIDs)
+		}
+	})
+
+	t.Run("keeps each window once and only the last moved", func(t *testing.T) {
+		managed := state.NewManaged(filepath.Join(t.TempDir(), "managed.json"), 3)
+
+		for _, windowID := range []int{1, 2, 3, 1, 4} {
+			if err := managed.Add(windowID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		windowIDs, err := managed.List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(windowIDs, []int{3, 1, 4}) {
+			t.Fatalf("expected windows 3, 1 and 4, got %v", windowIDs)
+		}
+	})
+}
@@ -0,0 +1,319 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// MovingMarkerTTL is how long a moving marker is honored. A marker left
+// behind by a crash expires instead of swallowing the next pull.
+const MovingMarkerTTL = 5 * time.Second
+
+// FocusMarkerTTL is how long the focus changes that follow a command of
+// aerospace-scratchpad are attributed to it.
+const FocusMarkerTTL = time.Second
+
+// NewWindowTTL is how long a window seen by the window-detected hook counts
+// as brand-new.
+const NewWindowTTL = 5 * time.Second
+
+const (
+	movingMarkerFileName = "moving.json"
+	focusMarkerFileName  = "focusing.json"
+	newWindowsFileName   = "new-windows.json"
+)
+
+// MovingMarker tells the pull-window hook that a window is being moved on
+// purpose, so the workspace change it causes must not pull it back.
+type MovingMarker struct {
+	PID       int       `json:"pid"`
+	Timestamp time.Time `json:"timestamp"`
+	WindowID  int       `json:"window_id"`
+}
+
+// Expired reports whether the marker is older than MovingMarkerTTL.
+func (m MovingMarker) Expired(now time.Time) bool {
+	return now.Sub(m.Timestamp) > MovingMarkerTTL
+}
+
+// RuntimeDir returns the per user directory for short lived files:
+// $XDG_RUNTIME_DIR/aerospace-scratchpad, or a directory of the user in the
+// temp dir. It is created private to the user and refused when someone else
+// owns it.
+func RuntimeDir() (string, error) {
+	dir := filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d", dirName, os.Getuid()))
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		dir = filepath.Join(runtimeDir, dirName)
+	}
+
+	if err := os.MkdirAll(dir, dirFileMode); err != nil {
+		return "", fmt.Errorf("unable to create runtime dir: %w", err)
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", fmt.Errorf("unable to check runtime dir: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("runtime dir %s is not a directory", dir)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != os.Getuid() {
+		return "", fmt.Errorf("runtime dir %s belongs to another user", dir)
+	}
+	if info.Mode().Perm() != dirFileMode {
+		if err = os.Chmod(dir, dirFileMode); err != nil {
+			return "", fmt.Errorf("unable to restrict runtime dir: %w", err)
+		}
+	}
+
+	return dir, nil
+}
+
+// MovingMarkerPath returns the moving marker file in the RuntimeDir.
+func MovingMarkerPath() (string, error) {
+	dir, err := RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, movingMarkerFileName), nil
+}
+
+// WriteMovingMarker marks windowID as being moved by this process.
+func WriteMovingMarker(windowID int) error {
+	path, err := MovingMarkerPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(MovingMarker{
+		PID:       os.Getpid(),
+		Timestamp: time.Now(),
+		WindowID:  windowID,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to encode moving marker: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+// ConsumeMovingMarker reports whether windowID is being moved on purpose,
+// removing the marker when it is. Markers of other windows are returned and
+// left for them, expired or unreadable ones are removed.
+func ConsumeMovingMarker(windowID int) (*MovingMarker, bool, error) {
+	path, err := MovingMarkerPath()
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to read moving marker: %w", err)
+	}
+
+	var marker MovingMarker
+	if err = json.Unmarshal(data, &marker); err != nil || marker.Expired(time.Now()) {
+		return nil, false, removeMarker(path)
+	}
+	if marker.WindowID != windowID {
+		return &marker, false, nil
+	}
+
+	return &marker, true, removeMarker(path)
+}
+
+// ReadMovingMarker returns the moving marker without consuming it, nil when
+// there is none.
+func ReadMovingMarker() (*MovingMarker, error) {
+	path, err := MovingMarkerPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read moving marker: %w", err)
+	}
+
+	var marker MovingMarker
+	if err = json.Unmarshal(data, &marker); err != nil {
+		return nil, fmt.Errorf("unable to decode moving marker: %w", err)
+	}
+	return &marker, nil
+}
+
+// FocusMarker tells the focus-changed hook the windows aerospace-scratchpad
+// itself is focusing or moving, so they aren't hidden meanwhile.
+type FocusMarker struct {
+	PID       int       `json:"pid"`
+	Timestamp time.Time `json:"timestamp"`
+	WindowIDs []int     `json:"window_ids"`
+}
+
+// Expired reports whether the marker is older than FocusMarkerTTL.
+func (m FocusMarker) Expired(now time.Time) bool {
+	return now.Sub(m.Timestamp) > FocusMarkerTTL
+}
+
+// Covers reports whether the window is being focused or moved.
+func (m FocusMarker) Covers(windowID int) bool {
+	return slices.Contains(m.WindowIDs, windowID)
+}
+
+// FocusMarkerPath returns the focus marker file in the RuntimeDir.
+func FocusMarkerPath() (string, error) {
+	dir, err := RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, focusMarkerFileName), nil
+}
+
+// WriteFocusMarker marks the windows as being focused or moved by this
+// process. The windows of a recent marker are kept, a command may focus
+// several windows one after the other.
+func WriteFocusMarker(windowIDs ...int) error {
+	path, err := FocusMarkerPath()
+	if err != nil {
+		return err
+	}
+
+	recent, err := RecentFocusMarker()
+	if err != nil {
+		return err
+	}
+	marker := FocusMarker{
+		PID:       os.Getpid(),
+		Timestamp: time.Now(),
+		WindowIDs: windowIDs,
+	}
+	if recent != nil {
+		for _, windowID := range recent.WindowIDs {
+			if !marker.Covers(windowID) {
+				marker.WindowIDs = append(marker.WindowIDs, windowID)
+			}
+		}
+	}
+
+	data, err := json.Marshal(marker)
+	if err != nil {
+		return fmt.Errorf("unable to encode focus marker: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+// RecentFocusMarker returns the focus marker when it hasn't expired yet.
+// Unlike the moving marker it isn't consumed, a single command may cause
+// several focus changes. Expired or unreadable markers are removed.
+func RecentFocusMarker() (*FocusMarker, error) {
+	path, err := FocusMarkerPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read focus marker: %w", err)
+	}
+
+	var marker FocusMarker
+	if err = json.Unmarshal(data, &marker); err != nil || marker.Expired(time.Now()) {
+		return nil, removeMarker(path)
+	}
+	return &marker, nil
+}
+
+// NewWindow is a window the window-detected hook has just seen created.
+type NewWindow struct {
+	WindowID  int       `json:"window_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Expired reports whether the window is older than NewWindowTTL.
+func (w NewWindow) Expired(now time.Time) bool {
+	return now.Sub(w.Timestamp) > NewWindowTTL
+}
+
+// NewWindowsPath returns the new windows file in the RuntimeDir.
+func NewWindowsPath() (string, error) {
+	dir, err := RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, newWindowsFileName), nil
+}
+
+// WriteNewWindow marks windowID as brand-new, dropping the expired ones.
+func WriteNewWindow(windowID int) error {
+	path, err := NewWindowsPath()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	windows := []NewWindow{{WindowID: windowID, Timestamp: now}}
+	for _, window := range readNewWindows(path) {
+		if window.WindowID != windowID && !window.Expired(now) {
+			windows = append(windows, window)
+		}
+	}
+
+	data, err := json.Marshal(windows)
+	if err != nil {
+		return fmt.Errorf("unable to encode new windows: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+// IsNewWindow reports whether windowID was marked by WriteNewWindow less than
+// NewWindowTTL ago.
+func IsNewWindow(windowID int) (bool, error) {
+	path, err := NewWindowsPath()
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for _, window := range readNewWindows(path) {
+		if window.WindowID == windowID && !window.Expired(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// readNewWindows returns the windows of the new windows file, none when it is
+// missing or unreadable.
+func readNewWindows(path string) []NewWindow {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var windows []NewWindow
+	if err = json.Unmarshal(data, &windows); err != nil {
+		return nil
+	}
+	return windows
+}
+
+func removeMarker(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to remove marker: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package state_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+)
+
+func TestMovingMarker(t *testing.T) {
+	t.Run("is consumed only by the window it refers to", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		if err := state.WriteMovingMarker(42); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, beingMoved, err := state.ConsumeMovingMarker(7); err != nil || beingMoved {
+			t.Fatalf("expected window 7 not to be moving, got %v %v", beingMoved, err)
+		}
+		marker, beingMoved, err := state.ConsumeMovingMarker(42)
+		if err != nil || !beingMoved || marker.PID != os.Getpid() {
+			t.Fatalf("expected window 42 to be moving, got %+v %v %v", marker, beingMoved, err)
+		}
+		if _, beingMoved, err = state.ConsumeMovingMarker(42); err != nil || beingMoved {
+			t.Fatalf("expected the marker to be consumed, got %v %v", beingMoved, err)
+		}
+	})
+
+	t.Run("is read without being consumed", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		if marker, err := state.ReadMovingMarker(); err != nil || marker != nil {
+			t.Fatalf("expected no moving marker, got %+v %v", marker, err)
+		}
+		if err := state.WriteMovingMarker(42); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for range 2 {
+			marker, err := state.ReadMovingMarker()
+			if err != nil || marker == nil || marker.WindowID != 42 {
+				t.Fatalf("expected the marker of window 42, got %+v %v", marker, err)
+			}
+		}
+	})
+
+	t.Run("keeps the runtime dir private", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		dir, err := state.RuntimeDir()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = os.Chmod(dir, 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err = state.RuntimeDir(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		info, err := os.Stat(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Mode().Perm() != 0o700 {
+			t.Fatalf("expected a private runtime dir, got %v", info.Mode().Perm())
+		}
+	})
+}
+
+func TestFocusMarker(t *testing.T) {
+	t.Run("is kept until it expires", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		if marker, err := state.RecentFocusMarker(); err != nil || marker != nil {
+			t.Fatalf("expected no focus marker, got %+v %v", marker, err)
+		}
+		if err := state.WriteFocusMarker(42); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for range 2 {
+			marker, err := state.RecentFocusMarker()
+			if err != nil || marker == nil || marker.PID != os.Getpid() {
+				t.Fatalf("expected a recent focus marker, got %+v %v", marker, err)
+			}
+		}
+	})
+
+	t.Run("covers the windows of the recent markers", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		for _, windowID := range []int{42, 7, 42} {
+			if err := state.WriteFocusMarker(windowID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		marker, err := state.RecentFocusMarker()
+		if err != nil || marker == nil {
+			t.Fatalf("expected a recent focus marker, got %+v %v", marker, err)
+		}
+		if !marker.Covers(42) || !marker.Covers(7) || len(marker.WindowIDs) != 2 {
+			t.Fatalf("expected windows 42 and 7 covered, got %+v", marker.WindowIDs)
+		}
+	})
+
+	t.Run("expires after the TTL", func(t *testing.T) {
+		marker := state.FocusMarker{Timestamp: time.Now().Add(-2 * state.FocusMarkerTTL)}
+		if !marker.Expired(time.Now()) {
+			t.Fatalf("expected the marker to be expired")
+		}
+	})
+}
+
+func TestNewWindows(t *testing.T) {
+	t.Run("are new until they expire", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		if isNew, err := state.IsNewWindow(42); err != nil || isNew {
+			t.Fatalf("expected window 42 not to be new, got %v %v", isNew, err)
+		}
+		for _, windowID := range []int{42, 7, 42} {
+			if err := state.WriteNewWindow(windowID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		for _, windowID := range []int{42, 7} {
+			if isNew, err := state.IsNewWindow(windowID); err != nil || !isNew {
+				t.Fatalf("expected window %d to be new, got %v %v", windowID, isNew, err)
+			}
+		}
+		if isNew, err := state.IsNewWindow(99); err != nil || isNew {
+			t.Fatalf("expected window 99 not to be new, got %v %v", isNew, err)
+		}
+	})
+
+	t.Run("expire after the TTL", func(t *testing.T) {
+		window := state.NewWindow{Timestamp: time.Now().Add(-2 * state.NewWindowTTL)}
+		if !window.Expired(time.Now()) {
+			t.Fatalf("expected the window to be expired")
+		}
+	})
+}
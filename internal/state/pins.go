@@ -0,0 +1,124 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// PinnedWindow is a window that follows the workspace switches.
+type PinnedWindow struct {
+	WindowID int    `json:"window_id"`
+	AppName  string `json:"app_name"`
+}
+
+// Pins is the set of pinned windows, stored as a JSON array.
+type Pins struct {
+	path string
+}
+
+// NewPins creates a pinned set stored in path.
+func NewPins(path string) *Pins {
+	return &Pins{
+		path: path,
+	}
+}
+
+// DefaultPinsPath returns the pinned set file in the state Dir.
+func DefaultPinsPath() string {
+	return filepath.Join(Dir(), "pins.json")
+}
+
+// List returns the pinned windows. A set that doesn't exist yet is empty.
+func (p *Pins) List() ([]PinnedWindow, error) {
+	data, err := os.ReadFile(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read pinned windows: %w", err)
+	}
+
+	var pinned []PinnedWindow
+	if err = json.Unmarshal(data, &pinned); err != nil {
+		return nil, fmt.Errorf("unable to parse pinned windows: %w", err)
+	}
+	return pinned, nil
+}
+
+// Add pins the windows, the ones already pinned are kept once. The set is
+// locked while it is rewritten, pin runs along the hooks pruning it.
+func (p *Pins) Add(windows ...PinnedWindow) error {
+	lock, err := p.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	pinned, err := p.List()
+	if err != nil {
+		return err
+	}
+
+	for _, window := range windows {
+		index := slices.IndexFunc(pinned, func(current PinnedWindow) bool {
+			return current.WindowID == window.WindowID
+		})
+		if index >= 0 {
+			pinned[index] = window
+			continue
+		}
+		pinned = append(pinned, window)
+	}
+
+	return p.write(pinned)
+}
+
+// Remove unpins the windows with the given ids.
+func (p *Pins) Remove(windowIDs ...int) error {
+	lock, err := p.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	pinned, err := p.List()
+	if err != nil {
+		return err
+	}
+
+	pinned = slices.DeleteFunc(pinned, func(window PinnedWindow) bool {
+		return slices.Contains(windowIDs, window.WindowID)
+	})
+	return p.write(pinned)
+}
+
+// Replace rewrites the set with windows.
+func (p *Pins) Replace(windows []PinnedWindow) error {
+	lock, err := p.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	return p.write(windows)
+}
+
+func (p *Pins) lock() (*Lock, error) {
+	return WaitLock(p.path + ".lock")
+}
+
+func (p *Pins) write(windows []PinnedWindow) error {
+	if windows == nil {
+		windows = []PinnedWindow{}
+	}
+
+	data, err := json.MarshalIndent(windows, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode pinned windows: %w", err)
+	}
+	return writeFileAtomic(p.path, append(data, '\n'))
+}
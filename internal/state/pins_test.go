@@ -0,0 +1,68 @@
+package state_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/state"
+)
+
+func TestPins(t *testing.T) {
+	t.Run("adds each window once and removes them", func(t *testing.T) {
+		pins := state.NewPins(filepath.Join(t.TempDir(), "pins.json"))
+
+		err := pins.Add(
+			state.PinnedWindow{WindowID: 1, AppName: "Music"},
+			state.PinnedWindow{WindowID: 2, AppName: "Zoom"},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = pins.Add(state.PinnedWindow{WindowID: 1, AppName: "Music"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		pinned, err := pins.List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pinned) != 2 {
+			t.Fatalf("expected 2 pinned windows, got %+v", pinned)
+		}
+
+		if err = pins.Remove(1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pinned, err = pins.List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pinned) != 1 || pinned[0].WindowID != 2 {
+			t.Fatalf("expected only window 2 pinned, got %+v", pinned)
+		}
+	})
+	t.Run("keeps the windows pinned concurrently", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pins.json")
+
+		var wg sync.WaitGroup
+		for id := 1; id <= 50; id++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := state.NewPins(path).Add(state.PinnedWindow{WindowID: id}); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		pinned, err := state.NewPins(path).List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pinned) != 50 {
+			t.Fatalf("expected 50 windows, got %d", len(pinned))
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Package state keeps what aerospace-scratchpad remembers between runs,
+// e.g. the journal of the windows it moved.
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/constants"
+)
+
+const (
+	dirName     = "aerospace-scratchpad"
+	dirFileMode = 0o700
+	// fileMode keeps the state private, it has window titles and app names.
+	fileMode = 0o600
+)
+
+// Dir returns the directory where the state is kept:
+// $AEROSPACE_SCRATCHPAD_STATE_DIR, $XDG_STATE_HOME/aerospace-scratchpad or
+// ~/.local/state/aerospace-scratchpad.
+func Dir() string {
+	if dir := os.Getenv(constants.EnvAeroSpaceScratchpadStateDir); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, dirName)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "state", dirName)
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d", dirName, os.Getuid()))
+}
+
+// writeFileAtomic replaces path with data, so readers never see it half
+// written.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), dirFileMode); err != nil {
+		return fmt.Errorf("unable to create state dir: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to create temp file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+	if err = file.Chmod(fileMode); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("unable to set permissions of %s: %w", path, err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+	if err = os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("unable to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// TaggedWindow is a window tagged by a window-detected rule.
+type TaggedWindow struct {
+	WindowID int    `json:"window_id"`
+	AppName  string `json:"app_name"`
+	Tag      string `json:"tag"`
+}
+
+// Tags is the set of tagged windows, stored as a JSON array.
+type Tags struct {
+	path string
+}
+
+// NewTags creates a tag set stored in path.
+func NewTags(path string) *Tags {
+	return &Tags{
+		path: path,
+	}
+}
+
+// DefaultTagsPath returns the tags file in the state Dir.
+func DefaultTagsPath() string {
+	return filepath.Join(Dir(), "tags.json")
+}
+
+// List returns the tagged windows. A set that doesn't exist yet is empty.
+func (t *Tags) List() ([]TaggedWindow, error) {
+	data, err := os.ReadFile(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read tags: %w", err)
+	}
+
+	var tagged []TaggedWindow
+	if err = json.Unmarshal(data, &tagged); err != nil {
+		return nil, fmt.Errorf("unable to parse tags: %w", err)
+	}
+	return tagged, nil
+}
+
+// Add tags a window, a window has each tag once. The tags are locked while
+// they are rewritten, the window-detected hook runs once per new window.
+func (t *Tags) Add(window TaggedWindow) error {
+	lock, err := WaitLock(t.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	tagged, err := t.List()
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(tagged, window) {
+		return nil
+	}
+	tagged = append(tagged, window)
+
+	data, err := json.MarshalIndent(tagged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode tags: %w", err)
+	}
+	return writeFileAtomic(t.path, append(data, '\n'))
+}
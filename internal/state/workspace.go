@@ -0,0 +1,86 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PreviousWorkspacePath returns the file remembering the workspace focused
+// before the current one, in the state Dir.
+func PreviousWorkspacePath() string {
+	return filepath.Join(Dir(), "previous-workspace")
+}
+
+// WritePreviousWorkspace remembers the workspace focused before the current
+// one. AeroSpace only tells it to the exec-on-workspace-change callbacks.
+func WritePreviousWorkspace(workspace string) error {
+	return writeFileAtomic(PreviousWorkspacePath(), []byte(workspace+"\n"))
+}
+
+// ReadPreviousWorkspace returns the workspace written by
+// WritePreviousWorkspace, empty when there is none yet.
+func ReadPreviousWorkspace() (string, error) {
+	data, err := os.ReadFile(PreviousWorkspacePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read previous workspace: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// MonitorWorkspacesPath returns the file remembering the workspace last
+// focused on each monitor, in the state Dir.
+func MonitorWorkspacesPath() string {
+	return filepath.Join(Dir(), "monitor-workspaces.json")
+}
+
+// WriteMonitorWorkspace remembers workspace as the last one focused on
+// monitorID.
+func WriteMonitorWorkspace(monitorID int, workspace string) error {
+	workspaces, err := readMonitorWorkspaces()
+	if err != nil {
+		return err
+	}
+	if workspaces[monitorID] == workspace {
+		return nil
+	}
+	workspaces[monitorID] = workspace
+
+	data, err := json.Marshal(workspaces)
+	if err != nil {
+		return fmt.Errorf("unable to encode monitor workspaces: %w", err)
+	}
+	return writeFileAtomic(MonitorWorkspacesPath(), data)
+}
+
+// ReadMonitorWorkspace returns the workspace written by WriteMonitorWorkspace
+// for monitorID, empty when there is none yet.
+func ReadMonitorWorkspace(monitorID int) (string, error) {
+	workspaces, err := readMonitorWorkspaces()
+	if err != nil {
+		return "", err
+	}
+	return workspaces[monitorID], nil
+}
+
+func readMonitorWorkspaces() (map[int]string, error) {
+	workspaces := map[int]string{}
+
+	data, err := os.ReadFile(MonitorWorkspacesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return workspaces, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read monitor workspaces: %w", err)
+	}
+	if err = json.Unmarshal(data, &workspaces); err != nil {
+		return nil, fmt.Errorf("unable to parse monitor workspaces: %w", err)
+	}
+	return workspaces, nil
+}
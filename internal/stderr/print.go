@@ -1,7 +1,9 @@
 package stderr
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
@@ -17,18 +19,81 @@ func SetBehavior(shouldExit bool) {
 	ShouldExit = shouldExit
 }
 
+// ExitCoder is implemented by errors that exit with a specific code,
+// e.g. timeouts, so scripts can tell them apart.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+//nolint:gochecknoglobals // configurable exit code, see SetExitCodeFunc
+var defaultExitCode func() int
+
+// SetExitCodeFunc sets how the exit code is decided when none of the printed
+// values is an ExitCoder. nil restores the default exit code 1.
+func SetExitCodeFunc(fn func() int) {
+	defaultExitCode = fn
+}
+
+//nolint:gochecknoglobals // where the errors are printed, see SetOutput
+var output io.Writer
+
+// SetOutput sets where the errors are printed, e.g. the response of a daemon
+// request. nil restores os.Stderr.
+func SetOutput(w io.Writer) {
+	output = w
+}
+
+func writer() io.Writer {
+	if output != nil {
+		return output
+	}
+	return os.Stderr
+}
+
+//nolint:gochecknoglobals // last exit code, see LastExitCode
+var lastExitCode int
+
+// LastExitCode returns the exit code of the last error printed since
+// ResetExitCode, or 0 if none was. Useful when ShouldExit is false.
+func LastExitCode() int {
+	return lastExitCode
+}
+
+// ResetExitCode forgets the errors printed so far.
+func ResetExitCode() {
+	lastExitCode = 0
+}
+
+func exit(code int) {
+	lastExitCode = code
+	if ShouldExit {
+		os.Exit(code)
+	}
+}
+
+func exitCode(a []any) int {
+	for _, value := range a {
+		var coder ExitCoder
+		if err, ok := value.(error); ok && errors.As(err, &coder) {
+			return coder.ExitCode()
+		}
+	}
+	if defaultExitCode != nil {
+		return defaultExitCode()
+	}
+	return 1
+}
+
 func Writef(tmpl string, a ...any) {
 	logger := logger.GetDefaultLogger()
 	logger.LogError(fmt.Sprintf(tmpl, a...))
 
 	errorMessage := fmt.Errorf(tmpl, a...)
-	_, err := fmt.Fprintln(os.Stderr, errorMessage)
+	_, err := fmt.Fprintln(writer(), errorMessage)
 	if err != nil {
 		panic(fmt.Sprintf("Failure: unable to print error message: %v", err))
 	}
-	if ShouldExit {
-		os.Exit(1)
-	}
+	exit(exitCode(a))
 }
 
 // Println prints an error message to stderr and exits the program if ShouldExit is true.
@@ -38,24 +103,20 @@ func Println(tmpl string, a ...any) {
 	logger.LogError(fmt.Sprintf(tmpl, a...))
 
 	errorMessage := fmt.Errorf(tmpl, a...)
-	_, err := fmt.Fprintln(os.Stderr, errorMessage)
+	_, err := fmt.Fprintln(writer(), errorMessage)
 	if err != nil {
 		panic(fmt.Sprintf("Failure: unable to print error message: %v", err))
 	}
-	if ShouldExit {
-		os.Exit(1)
-	}
+	exit(exitCode(a))
 }
 
 func Printf(tmpl string, a ...any) {
 	logger := logger.GetDefaultLogger()
 	logger.LogError(fmt.Sprintf(tmpl, a...))
 
-	_, err := fmt.Fprintf(os.Stderr, tmpl, a...)
+	_, err := fmt.Fprintf(writer(), tmpl, a...)
 	if err != nil {
 		panic(fmt.Sprintf("Failure: unable to print error message: %v", err))
 	}
-	if ShouldExit {
-		os.Exit(1)
-	}
+	exit(exitCode(a))
 }
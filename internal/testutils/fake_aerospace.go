@@ -0,0 +1,113 @@
+package testutils
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	aerospacecli "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
+)
+
+// StartFakeAeroSpace serves the world described in yaml through the real
+// unix socket protocol and returns a client connected to it.
+// The server is stopped when the test finishes.
+func StartFakeAeroSpace(
+	t *testing.T,
+	worldYAML string,
+) (*aerospacecli.AeroSpaceWM, *fakeaerospace.World) {
+	t.Helper()
+
+	world, err := fakeaerospace.ParseWorld([]byte(worldYAML))
+	if err != nil {
+		t.Fatalf("unable to parse world: %v", err)
+	}
+
+	// Unix socket paths are limited in length, so avoid the long test dirs.
+	dir, err := os.MkdirTemp("", "fas")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	socketPath := filepath.Join(dir, "aerospace.sock")
+
+	server := fakeaerospace.NewServer(world, t.Logf)
+	done := make(chan error, 1)
+	go func() {
+		done <- server.ListenAndServe(socketPath)
+	}()
+
+	var client *aerospacecli.AeroSpaceWM
+	for range 100 {
+		client, err = aerospacecli.NewCustomClient(aerospacecli.CustomConnectionOpts{
+			SocketPath: socketPath,
+		})
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("unable to connect to fake server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = client.CloseConnection()
+		_ = server.Close()
+		<-done
+		_ = os.RemoveAll(dir)
+	})
+
+	return client, world
+}
+
+// StartUnresponsiveAeroSpace accepts connections and completes the handshake
+// but never answers a request, like a stuck AeroSpace. It returns a socket
+// connection to it, closed when the test finishes.
+func StartUnresponsiveAeroSpace(t *testing.T) *client.AeroSpaceSocketConnection {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "fas")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	socketPath := filepath.Join(dir, "aerospace.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("unable to listen on %s: %v", socketPath, err)
+	}
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var version uint32
+				_ = binary.Read(conn, binary.LittleEndian, &version)
+				_ = binary.Write(conn, binary.LittleEndian, version)
+				// Swallow the requests without ever answering.
+				_, _ = io.Copy(io.Discard, conn)
+			}()
+		}
+	}()
+
+	conn, err := client.NewAeroSpaceSocketConnection(socketPath)
+	if err != nil {
+		t.Fatalf("unable to connect to unresponsive server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.CloseConnection()
+		_ = listener.Close()
+		_ = os.RemoveAll(dir)
+	})
+
+	return conn
+}
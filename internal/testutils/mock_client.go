@@ -314,7 +314,7 @@ func (r *routingConnection) CheckServerVersion() error {
 }
 
 func (r *routingConnection) GetServerVersion() (string, error) {
-	return "0.3.0", nil
+	return "0.21.0-Beta test", nil
 }
 
 func (r *routingConnection) CloseConnection() error {
@@ -0,0 +1,43 @@
+package testutils
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/ipcrecord"
+)
+
+// NewReplayClient creates a client that answers with the responses recorded
+// in testdata/sessions/<name>. Sessions are recorded with:
+//
+//	aerospace-scratchpad --record session.jsonl <command>
+//
+// Only sessions recorded from a real AeroSpace belong there, named after the
+// AeroSpace version they were recorded with, e.g. list-0.19.2.jsonl. A
+// session recorded with --simulate would only replay the fake server.
+//
+// The test fails if any recorded request was not replayed.
+func NewReplayClient(t *testing.T, name string) *aerospace.ConnectionClient {
+	t.Helper()
+
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatalf("unable to resolve testutils directory")
+	}
+
+	entries, err := ipcrecord.LoadSession(filepath.Join(filepath.Dir(file), "testdata", "sessions", name))
+	if err != nil {
+		t.Fatalf("unable to load session %s: %v", name, err)
+	}
+
+	replay := ipcrecord.NewReplayConnection(entries)
+	t.Cleanup(func() {
+		for _, pending := range replay.Pending() {
+			t.Errorf("recorded request was not replayed: %s %v", pending.Command, pending.Args)
+		}
+	})
+
+	return aerospace.NewClientFromConnection(replay)
+}
@@ -9,22 +9,15 @@ import (
 	"github.com/goccy/go-yaml"
 
 	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/fakeaerospace"
 )
 
-type SnapshotWorkspace struct {
-	Workspace       string `yaml:"workspace"`
-	FocusedWindowID int    `yaml:"focused-window-id,omitempty"`
-}
-
-type SnapshotWindow struct {
-	WindowID                    int    `yaml:"window-id"`
-	WindowTitle                 string `yaml:"window-title,omitempty"`
-	WindowLayout                string `yaml:"window-layout,omitempty"`
-	WindowParentContainerLayout string `yaml:"parent-layout,omitempty"`
-	AppName                     string `yaml:"app-name,omitempty"`
-	AppBundleID                 string `yaml:"app-bundle-id,omitempty"`
-	Workspace                   string `yaml:"workspace,omitempty"`
-}
+// SnapshotWorkspace and SnapshotWindow share the yaml shape of the fake
+// AeroSpace world, so a snapshot context can be used as a --simulate world.
+type (
+	SnapshotWorkspace = fakeaerospace.Workspace
+	SnapshotWindow    = fakeaerospace.Window
+)
 
 type SnapshotContext struct {
 	Workspaces []SnapshotWorkspace `yaml:"workspaces,omitempty"`
@@ -5,17 +5,28 @@ package main
 
 import (
 	"log"
+	"os"
 
 	aerospacecli "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
 	"github.com/cristianoliveira/aerospace-scratchpad/cmd"
+	"github.com/cristianoliveira/aerospace-scratchpad/internal/aerospace"
 	"github.com/cristianoliveira/aerospace-scratchpad/internal/logger"
 )
 
 func main() {
+	// Hotkeys are faster through the daemon, when it is running.
+	if exitCode, forwarded := cmd.ForwardToDaemon(os.Args[1:], os.Stdout, os.Stderr); forwarded {
+		os.Exit(exitCode)
+	}
+
 	defaultLogger, err := logger.NewLogger()
 	if err != nil {
-		log.Fatalf("Error: creating logger\n%v", err)
-		return
+		// doctor reports the log file it can't write instead of failing on it.
+		if !cmd.RunsDoctor(os.Args[1:]) {
+			log.Fatalf("Error: creating logger\n%v", err)
+			return
+		}
+		defaultLogger = &logger.EmptyLogger{}
 	}
 	defer func() {
 		if closeErr := defaultLogger.Close(); closeErr != nil {
@@ -25,10 +36,32 @@ func main() {
 	logger.SetDefaultLogger(defaultLogger)
 	defaultLogger.LogInfo("Executing Aerospace Scratchpad CLI")
 
-	aerospaceMarkClient, err := aerospacecli.NewClient()
-	if err != nil {
-		log.Printf("Error creating Aerospace client: %v", err)
+	var aerospaceMarkClient aerospace.AeroSpaceWMClient
+	if cmd.RequiresConnection(os.Args[1:]) {
+		aerospaceMarkClient, err = newClient()
+		if err != nil {
+			log.Printf("Error creating Aerospace client: %v", err)
+		}
 	}
 
 	cmd.Execute(aerospaceMarkClient)
 }
+
+// newClient connects to AeroSpace. The connection is replaced when it
+// breaks, e.g. AeroSpace restarted, so queries can be retried on it.
+func newClient() (aerospace.AeroSpaceWMClient, error) {
+	wm, err := aerospacecli.NewClient()
+	if err != nil {
+		return wm, err
+	}
+
+	conn := wm.Connection()
+	socketPath, err := conn.GetSocketPath()
+	if err != nil {
+		return wm, nil
+	}
+
+	return aerospace.NewClientFromConnection(
+		aerospace.NewReconnectingConnection(conn, aerospace.DialSocket(socketPath)),
+	), nil
+}
@@ -2,33 +2,40 @@
 
 set -euo pipefail
 
-# Current benchmark using aerospace-scratchpad
-# 2025/05/31 08:13:36 GetAllWindows took 31.3675ms
-# 2025/05/31 08:13:36 GetFocusedWorkspace took 50.599167ms
-# 2025/05/31 08:13:36 IsWindowInWorkspace took 50.858125ms
-# 2025/05/31 08:13:36 MoveWindowToWorkspace took 70.47675ms
-# 2025/05/31 08:13:36 SetFocusByWindowID took 83.191167ms
-# Window '55 | Finder  | .scratchpad | com.apple.finder' is summoned
-# aerospace-scratchpad show Finder  0.01s user 0.01s system 18% cpu 0.116 total
+# Compares aerospace-scratchpad against a bash script doing the same IPC calls.
+#
+# Usage: scripts/benchmark.sh <window-app-name>
+#
+# The CLI reports each IPC call and its latency with `--timings`, e.g.:
+# [timings]    2.912ms ok    list-workspaces --focused --json
+# [timings]    6.148ms ok    list-windows --all --json --format ...
+# [timings]    9.771ms ok    move-node-to-workspace 2 --window-id 55
+# [timings]    4.405ms ok    focus --window-id 55
+# [timings]   24.318ms total (4 requests)
 #
 # Previous benchmark using aerospace-scratchpad
 # aerospace-scratchpad show Finder  0.01s user 0.01s system 12% cpu 0.125 total
 # aerospace-scratchpad show Finder  0.01s user 0.01s system 14% cpu 0.097 total
 # aerospace-scratchpad show Finder  0.01s user 0.01s system 12% cpu 0.140 total
 
-# Test cli `time aerospace-scratchpad show <window-name>`
-# Test script `time scripts/benchmark.sh <window-id> <workspace-id>`
-
 WINDOW_APP_NAME=${1?"Missing window app name"}
 
-WINDOW_ID="$(aerospace list-windows --all | grep "Finder" | awk '{print $1}')"
-WORKSPACE_DEST="$(aerospace list-workspaces --focused | awk '{print $1}')"
-aerospace list-workspaces --focused --json > /dev/null
-aerospace list-windows --focused --json > /dev/null
-aerospace move-node-to-workspace "$WORKSPACE_DEST" --window-id "$WINDOW_ID" > /dev/null
-aerospace focus --window-id "$WINDOW_ID" > /dev/null
+echo "== aerospace-scratchpad --timings show $WINDOW_APP_NAME"
+time aerospace-scratchpad --timings show "$WINDOW_APP_NAME" > /dev/null
+
+echo "== bash script"
+bash_equivalent() {
+  local window_id workspace_dest
+  window_id="$(aerospace list-windows --all | grep "$WINDOW_APP_NAME" | awk '{print $1}' | head -n 1)"
+  workspace_dest="$(aerospace list-workspaces --focused | awk '{print $1}')"
+  aerospace list-workspaces --focused --json > /dev/null
+  aerospace list-windows --focused --json > /dev/null
+  aerospace move-node-to-workspace "$workspace_dest" --window-id "$window_id" > /dev/null
+  aerospace focus --window-id "$window_id" > /dev/null
+}
+time bash_equivalent
 
-# Latest output:
+# Latest output of the bash script:
 # scripts/benchmark.sh 55 2  0.11s user 0.07s system 69% cpu 0.263 total
 # scripts/benchmark.sh 55 2  0.11s user 0.06s system 69% cpu 0.252 total
 # scripts/benchmark.sh 55 2  0.11s user 0.06s system 72% cpu 0.246 total